@@ -0,0 +1,199 @@
+// Package admin 提供调度器的运行时管理接口，便于在不重新部署的情况下查看和操作调度器
+//
+//	mux.Handle("/admin/", http.StripPrefix("/admin", admin.NewHandler(scheduler)))
+//
+// 接口不做认证，应只暴露在内网或加上认证中间件
+package admin
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+// laneView 通道状态的JSON形式
+type laneView struct {
+	Lane     string `json:"lane"`
+	Queued   int    `json:"queued"`
+	Inflight int64  `json:"inflight"`
+	Quota    int    `json:"quota,omitempty"`
+}
+
+// stateView GET /state 的响应
+type stateView struct {
+	State   string     `json:"state"`
+	Paused  bool       `json:"paused"`
+	Busy    int        `json:"busy_workers"`
+	Workers int        `json:"workers"`
+	Lanes   []laneView `json:"lanes"`
+}
+
+// taskView 执行中任务的JSON形式
+type taskView struct {
+	ID      string    `json:"id"`
+	BatchID string    `json:"batch_id"`
+	Lane    string    `json:"lane"`
+	Attempt int       `json:"attempt"`
+	Started time.Time `json:"started"`
+	Running string    `json:"running"`
+	// Checkpoints 和 SinceCheckpoint 在任务调用 fastscheduler.Checkpoint 后出现
+	Checkpoints     int64  `json:"checkpoints,omitempty"`
+	SinceCheckpoint string `json:"since_checkpoint,omitempty"`
+}
+
+// batchView 批次状态的JSON形式
+type batchView struct {
+	ID        string    `json:"id"`
+	Lane      string    `json:"lane"`
+	Created   time.Time `json:"created"`
+	Done      int       `json:"done"`
+	Total     int       `json:"total"`
+	Succeeded bool      `json:"succeeded"`
+	Open      bool      `json:"open"`
+}
+
+func newBatchView(info fastscheduler.BatchInfo) batchView {
+	return batchView{
+		ID:        info.ID,
+		Lane:      info.Lane.String(),
+		Created:   info.Created,
+		Done:      info.Done,
+		Total:     info.Total,
+		Succeeded: info.Succeeded,
+		Open:      info.Open,
+	}
+}
+
+// tasksView GET /tasks 的响应
+type tasksView struct {
+	Running []taskView     `json:"running"`
+	Queued  map[string]int `json:"queued"`
+}
+
+// NewHandler 返回管理接口：
+//
+//	GET  /state        调度器状态、worker使用情况和各通道排队数
+//	GET  /tasks        执行中的任务和各通道排队数
+//	POST /pool?size=N  调整worker池大小
+//	GET  /batches      未完成的批次及其进度
+//	GET  /batches/{id} 单个批次的进度
+//	POST /batches/{id}/cancel  取消批次中尚未完成的任务
+//	POST /batches/{id}/tasks/{task}/cancel  取消批次中的单个任务
+//	POST /pause        暂停调度新任务，执行中的任务继续完成
+//	POST /resume       恢复调度
+//	GET  /dashboard    实时仪表盘页面，最近任务和耗时需要调度器启用 WithTaskLog
+//	GET  /dashboard/events  仪表盘数据的 server-sent events 流
+func NewHandler(s *fastscheduler.Scheduler) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /dashboard", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, dashboardHTML)
+	})
+	mux.HandleFunc("GET /dashboard/events", func(w http.ResponseWriter, r *http.Request) {
+		serveEvents(s, w, r)
+	})
+	mux.HandleFunc("GET /state", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, state(s))
+	})
+	mux.HandleFunc("GET /tasks", func(w http.ResponseWriter, r *http.Request) {
+		view := tasksView{Running: []taskView{}, Queued: make(map[string]int)}
+		now := time.Now()
+		for _, t := range s.Running() {
+			tv := taskView{
+				ID:          t.ID,
+				BatchID:     t.BatchID,
+				Lane:        t.Lane.String(),
+				Attempt:     t.Attempt,
+				Started:     t.Started,
+				Running:     now.Sub(t.Started).String(),
+				Checkpoints: t.Checkpoints,
+			}
+			if t.Checkpoints > 0 {
+				tv.SinceCheckpoint = now.Sub(t.LastCheckpoint).String()
+			}
+			view.Running = append(view.Running, tv)
+		}
+		for _, lane := range s.Stats().Lanes {
+			view.Queued[lane.Lane.String()] = lane.Queued
+		}
+		writeJSON(w, view)
+	})
+	mux.HandleFunc("POST /pool", func(w http.ResponseWriter, r *http.Request) {
+		size, err := strconv.Atoi(r.FormValue("size"))
+		if err != nil || size <= 0 {
+			http.Error(w, "size must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		s.Resize(size)
+		writeJSON(w, state(s))
+	})
+	mux.HandleFunc("GET /batches", func(w http.ResponseWriter, r *http.Request) {
+		views := []batchView{}
+		for _, info := range s.Batches() {
+			views = append(views, newBatchView(info))
+		}
+		writeJSON(w, views)
+	})
+	mux.HandleFunc("GET /batches/{id}", func(w http.ResponseWriter, r *http.Request) {
+		batch := s.Batch(r.PathValue("id"))
+		if batch == nil {
+			http.Error(w, "batch not found", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, newBatchView(batch.Info()))
+	})
+	mux.HandleFunc("POST /batches/{id}/cancel", func(w http.ResponseWriter, r *http.Request) {
+		batch := s.Batch(r.PathValue("id"))
+		if batch == nil {
+			http.Error(w, "batch not found", http.StatusNotFound)
+			return
+		}
+		batch.Cancel()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /batches/{id}/tasks/{task}/cancel", func(w http.ResponseWriter, r *http.Request) {
+		if err := s.CancelTask(r.PathValue("id"), r.PathValue("task")); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /pause", func(w http.ResponseWriter, r *http.Request) {
+		s.Pause()
+		writeJSON(w, state(s))
+	})
+	mux.HandleFunc("POST /resume", func(w http.ResponseWriter, r *http.Request) {
+		s.Resume()
+		writeJSON(w, state(s))
+	})
+	return mux
+}
+
+// state 汇总调度器状态
+func state(s *fastscheduler.Scheduler) stateView {
+	stats := s.Stats()
+	view := stateView{
+		State:   s.State().String(),
+		Paused:  s.Paused(),
+		Busy:    stats.Workers.Busy,
+		Workers: stats.Workers.Size,
+	}
+	for _, lane := range stats.Lanes {
+		view.Lanes = append(view.Lanes, laneView{
+			Lane:     lane.Lane.String(),
+			Queued:   lane.Queued,
+			Inflight: lane.Inflight,
+			Quota:    lane.Quota,
+		})
+	}
+	return view
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,170 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+func getJSON(t *testing.T, url string, v interface{}) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: %s", url, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	scheduler := fastscheduler.NewScheduler(1, 10)
+	defer scheduler.Stop()
+	server := httptest.NewServer(NewHandler(scheduler))
+	defer server.Close()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	task := func(id string) *fastscheduler.Task {
+		return &fastscheduler.Task{
+			ID: id,
+			Execute: func(ctx context.Context) (fastscheduler.TaskResult, error) {
+				started <- struct{}{}
+				<-release
+				return fastscheduler.TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+			},
+		}
+	}
+	batch, err := scheduler.SubmitBatch([]*fastscheduler.Task{task("a"), task("b")}, fastscheduler.WithBatchID("report"))
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	<-started
+
+	var tasks tasksView
+	getJSON(t, server.URL+"/tasks", &tasks)
+	if len(tasks.Running) != 1 || tasks.Running[0].ID != "a" {
+		t.Errorf("Expected task a running, got %+v", tasks.Running)
+	}
+	if tasks.Queued["normal"] != 1 {
+		t.Errorf("Expected 1 queued normal task, got %v", tasks.Queued)
+	}
+	if tasks.Running[0].BatchID != "report" {
+		t.Errorf("Expected running task in batch report, got %q", tasks.Running[0].BatchID)
+	}
+
+	var batches []batchView
+	getJSON(t, server.URL+"/batches", &batches)
+	if len(batches) != 1 || batches[0].ID != "report" || batches[0].Total != 2 {
+		t.Errorf("Unexpected batches %+v", batches)
+	}
+	var one batchView
+	getJSON(t, server.URL+"/batches/report", &one)
+	if one.ID != "report" {
+		t.Errorf("Unexpected batch %+v", one)
+	}
+	if resp, err := http.Get(server.URL + "/batches/missing"); err != nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown batch, got %v, %v", resp.Status, err)
+	}
+
+	// 扩容后排队的任务立即开始
+	resp, err := http.PostForm(server.URL+"/pool", url.Values{"size": {"2"}})
+	if err != nil {
+		t.Fatalf("POST /pool failed: %v", err)
+	}
+	var st stateView
+	json.NewDecoder(resp.Body).Decode(&st)
+	resp.Body.Close()
+	if st.Workers != 2 {
+		t.Errorf("Expected pool size 2, got %d", st.Workers)
+	}
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("Queued task did not start after resize")
+	}
+	close(release)
+	batch.Wait()
+
+	getJSON(t, server.URL+"/state", &st)
+	if st.State != "running" || st.Busy != 0 || len(st.Lanes) != 3 {
+		t.Errorf("Unexpected state %+v", st)
+	}
+
+	resp, err = http.Post(server.URL+"/pause", "", nil)
+	if err != nil {
+		t.Fatalf("POST /pause failed: %v", err)
+	}
+	json.NewDecoder(resp.Body).Decode(&st)
+	resp.Body.Close()
+	if !st.Paused || !scheduler.Paused() {
+		t.Error("Expected scheduler to be paused")
+	}
+	resp, err = http.Post(server.URL+"/resume", "", nil)
+	if err != nil {
+		t.Fatalf("POST /resume failed: %v", err)
+	}
+	resp.Body.Close()
+	if scheduler.Paused() {
+		t.Error("Expected scheduler to be resumed")
+	}
+
+	resp, err = http.PostForm(server.URL+"/pool", url.Values{"size": {"0"}})
+	if err != nil {
+		t.Fatalf("POST /pool failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid size, got %s", resp.Status)
+	}
+}
+
+func TestHandler_CancelBatch(t *testing.T) {
+	scheduler := fastscheduler.NewScheduler(1, 10)
+	defer scheduler.Stop()
+	server := httptest.NewServer(NewHandler(scheduler))
+	defer server.Close()
+
+	batch, err := scheduler.SubmitBatch([]*fastscheduler.Task{{
+		ID: "wait",
+		Execute: func(ctx context.Context) (fastscheduler.TaskResult, error) {
+			<-ctx.Done()
+			return fastscheduler.TaskResult{}, ctx.Err()
+		},
+	}})
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+
+	resp, err := http.Post(server.URL+"/batches/"+batch.ID()+"/tasks/missing/cancel", "", nil)
+	if err != nil {
+		t.Fatalf("POST task cancel failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown task, got %s", resp.Status)
+	}
+
+	resp, err = http.Post(server.URL+"/batches/"+batch.ID()+"/cancel", "", nil)
+	if err != nil {
+		t.Fatalf("POST cancel failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected 204, got %s", resp.Status)
+	}
+	batch.Wait()
+	if batch.Outcome() != fastscheduler.OutcomeCancelled {
+		t.Errorf("Expected cancelled batch, got %s", batch.Outcome())
+	}
+}
@@ -0,0 +1,167 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+// dashboardInterval 仪表盘推送更新的间隔
+const dashboardInterval = time.Second
+
+// dashboardRecent 仪表盘展示的最近任务数
+const dashboardRecent = 20
+
+// outcomeView 最近任务结果的JSON形式
+type outcomeView struct {
+	ID       string    `json:"id"`
+	Lane     string    `json:"lane"`
+	Success  bool      `json:"success"`
+	Error    string    `json:"error,omitempty"`
+	Duration float64   `json:"duration_ms"`
+	Finished time.Time `json:"finished"`
+}
+
+// snapshotView 仪表盘每次推送的数据
+type snapshotView struct {
+	stateView
+	Recent []outcomeView `json:"recent"`
+	// Latency 最近执行任务耗时的百分位(毫秒)
+	Latency map[string]float64 `json:"latency_ms"`
+}
+
+// snapshot 汇总仪表盘数据，最近任务和耗时需要调度器启用 WithTaskLog
+func snapshot(s *fastscheduler.Scheduler) snapshotView {
+	view := snapshotView{stateView: state(s), Recent: []outcomeView{}, Latency: map[string]float64{}}
+	records := s.RecentTasks()
+
+	var durations []time.Duration
+	for _, r := range records {
+		if r.Attempts > 0 {
+			durations = append(durations, r.Duration)
+		}
+	}
+	if len(durations) > 0 {
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		for _, p := range []int{50, 90, 99} {
+			d := durations[(len(durations)-1)*p/100]
+			view.Latency[fmt.Sprintf("p%d", p)] = milliseconds(d)
+		}
+	}
+
+	// 最新的在前
+	for i := len(records) - 1; i >= 0 && len(view.Recent) < dashboardRecent; i-- {
+		r := records[i]
+		o := outcomeView{
+			ID:       r.TaskID,
+			Lane:     r.Lane.String(),
+			Success:  r.Success,
+			Duration: milliseconds(r.Duration),
+			Finished: r.Finished,
+		}
+		if r.Err != nil {
+			o.Error = r.Err.Error()
+		}
+		view.Recent = append(view.Recent, o)
+	}
+	return view
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// serveEvents 以 server-sent events 推送仪表盘数据，连接建立时立即推送一次
+func serveEvents(s *fastscheduler.Scheduler, w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	ticker := time.NewTicker(dashboardInterval)
+	defer ticker.Stop()
+	for {
+		data, err := json.Marshal(snapshot(s))
+		if err != nil {
+			return
+		}
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case <-ticker.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// dashboardHTML 自包含的仪表盘页面，通过相对路径订阅 dashboard/events
+const dashboardHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>fast-scheduler</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; color: #222; }
+h2 { margin-top: 1.5em; font-size: 1.1em; }
+table { border-collapse: collapse; }
+td, th { padding: 4px 12px; border-bottom: 1px solid #ddd; text-align: left; }
+.bar { background: #eee; width: 300px; height: 14px; }
+.bar div { background: #4a8; height: 100%; }
+.fail { color: #c33; }
+</style>
+</head>
+<body>
+<h1>fast-scheduler <small id="state"></small></h1>
+<h2>Workers</h2>
+<div class="bar"><div id="util" style="width:0"></div></div>
+<p id="workers"></p>
+<h2>Queues</h2>
+<table id="lanes"><tr><th>lane</th><th>queued</th><th>inflight</th></tr></table>
+<h2>Latency</h2>
+<p id="latency">enable WithTaskLog to see task latency</p>
+<h2>Recent tasks</h2>
+<table id="recent"><tr><th>task</th><th>lane</th><th>result</th><th>duration</th></tr></table>
+<script>
+function rows(table, header, items) {
+  table.innerHTML = header;
+  for (const cells of items) {
+    const tr = table.insertRow();
+    for (const c of cells) {
+      const td = tr.insertCell();
+      td.textContent = c.text;
+      if (c.cls) td.className = c.cls;
+    }
+  }
+}
+const events = new EventSource(location.pathname.replace(/\/?$/, "/events"));
+events.onmessage = (e) => {
+  const s = JSON.parse(e.data);
+  document.getElementById("state").textContent = s.state;
+  document.getElementById("workers").textContent = s.busy_workers + " / " + s.workers + " busy";
+  document.getElementById("util").style.width = (s.workers ? 100 * s.busy_workers / s.workers : 0) + "%";
+  rows(document.getElementById("lanes"), "<tr><th>lane</th><th>queued</th><th>inflight</th></tr>",
+    s.lanes.map(l => [{text: l.lane}, {text: l.queued}, {text: l.inflight}]));
+  if (s.latency_ms.p50 !== undefined) {
+    document.getElementById("latency").textContent =
+      ["p50", "p90", "p99"].map(p => p + " " + s.latency_ms[p].toFixed(1) + "ms").join("  ");
+  }
+  rows(document.getElementById("recent"), "<tr><th>task</th><th>lane</th><th>result</th><th>duration</th></tr>",
+    s.recent.map(r => [{text: r.id}, {text: r.lane},
+      r.success ? {text: "ok"} : {text: r.error || "failed", cls: "fail"},
+      {text: r.duration_ms.toFixed(1) + "ms"}]));
+};
+</script>
+</body>
+</html>
+`
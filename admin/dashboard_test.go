@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+func TestDashboard(t *testing.T) {
+	scheduler := fastscheduler.NewScheduler(2, 10, fastscheduler.WithTaskLog(10))
+	defer scheduler.Stop()
+	server := httptest.NewServer(NewHandler(scheduler))
+	defer server.Close()
+
+	batch, err := scheduler.SubmitBatch([]*fastscheduler.Task{
+		{ID: "bad", Execute: func(ctx context.Context) (fastscheduler.TaskResult, error) {
+			return fastscheduler.TaskResult{}, errors.New("boom")
+		}},
+	})
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	batch.Wait()
+
+	resp, err := http.Get(server.URL + "/dashboard")
+	if err != nil {
+		t.Fatalf("GET /dashboard failed: %v", err)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(page), "EventSource") {
+		t.Error("Dashboard page should subscribe to events")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/dashboard/events", nil)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /dashboard/events failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected text/event-stream, got %s", ct)
+	}
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil {
+		t.Fatalf("Read event failed: %v", err)
+	}
+	var snap snapshotView
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &snap); err != nil {
+		t.Fatalf("Decode event failed: %v", err)
+	}
+	if len(snap.Recent) != 1 || snap.Recent[0].ID != "bad" || snap.Recent[0].Error != "boom" {
+		t.Errorf("Unexpected recent tasks %+v", snap.Recent)
+	}
+	if _, ok := snap.Latency["p99"]; !ok || snap.Workers != 2 {
+		t.Errorf("Unexpected snapshot %+v", snap)
+	}
+}
@@ -0,0 +1,26 @@
+package fastscheduler
+
+// After 使批次在 prev 中的所有批次完成后才开始入队，无论前序批次成功与否
+// 提交立即返回，不需要调用方阻塞等待 prev.Wait()，适合表达多阶段流水线。
+// 等待期间本批次被取消(例如父上下文结束)时，任务直接以取消结果完成
+func After(prev ...*Batch) BatchOption {
+	return func(g *taskGroup) {
+		for _, b := range prev {
+			if b != nil {
+				g.after = append(g.after, b.group.done)
+			}
+		}
+	}
+}
+
+// waitAfter 等待前序批次完成，本批次先被取消时返回取消原因
+func (g *taskGroup) waitAfter() error {
+	for _, done := range g.after {
+		select {
+		case <-done:
+		case <-g.ctx.Done():
+			return g.ctx.Err()
+		}
+	}
+	return nil
+}
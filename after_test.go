@@ -0,0 +1,84 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBatch_After(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	var fetched atomic.Int32
+	fetch, err := scheduler.SubmitBatch([]*Task{{
+		ID: "fetch",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			time.Sleep(30 * time.Millisecond)
+			fetched.Add(1)
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	var sawFetch atomic.Bool
+	publish, err := scheduler.SubmitBatch([]*Task{{
+		ID: "publish",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			sawFetch.Store(fetched.Load() == 1)
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	}}, After(fetch))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	publish.Wait()
+
+	if !sawFetch.Load() {
+		t.Error("Dependent batch started before previous batch completed")
+	}
+}
+
+func TestBatch_AfterCancelled(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	release := make(chan struct{})
+	defer close(release)
+	prev, err := scheduler.SubmitBatch([]*Task{{
+		ID: "blocked",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			<-release
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ran := false
+	next, err := scheduler.SubmitBatch([]*Task{{
+		ID: "next",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			ran = true
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	}}, After(prev), withParentContext(ctx))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	cancel()
+	next.Wait()
+
+	if ran {
+		t.Error("Cancelled batch should not run")
+	}
+	if !errors.Is(next.Err(), context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", next.Err())
+	}
+}
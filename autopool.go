@@ -0,0 +1,52 @@
+package fastscheduler
+
+import (
+	"math"
+	"runtime"
+	"time"
+)
+
+// AutoPoolSize 作为 NewScheduler 的 poolSize 传入时，worker数按 GOMAXPROCS 自动计算：
+// GOMAXPROCS × 倍数(默认1，通过 WithPoolMultiplier 设置)，之后 GOMAXPROCS 变化时自动调整
+const AutoPoolSize = -1
+
+// autoPoolInterval 检查 GOMAXPROCS 是否变化的间隔
+const autoPoolInterval = 250 * time.Millisecond
+
+// WithPoolMultiplier 设置自动池大小的倍数，IO密集型任务大部分时间在等待，可以设置为大于1的值
+// 只在 poolSize 为 AutoPoolSize 时生效，m 小于等于0时按1处理
+func WithPoolMultiplier(m float64) Option {
+	return func(s *Scheduler) {
+		s.poolMultiplier = m
+	}
+}
+
+// autoPoolSize 按当前 GOMAXPROCS 和倍数计算worker数，至少为1，调用方需持有 autoPoolMu
+// 计算所用的 GOMAXPROCS 记录在 autoProcs 中，之后与它比较判断是否变化
+func (s *Scheduler) autoPoolSize() int {
+	m := s.poolMultiplier
+	if m <= 0 {
+		m = 1
+	}
+	s.autoProcs = runtime.GOMAXPROCS(0)
+	return max(int(math.Ceil(float64(s.autoProcs)*m)), 1)
+}
+
+// runAutoPool 按间隔检查 GOMAXPROCS，变化时重新计算worker数，stop 关闭时退出
+// 调用 Resize 指定固定大小后不再调整
+func (s *Scheduler) runAutoPool(stop <-chan struct{}) {
+	ticker := s.clock.NewTicker(autoPoolInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C():
+			s.autoPoolMu.Lock()
+			if s.autoPool && runtime.GOMAXPROCS(0) != s.autoProcs {
+				s.resize(s.autoPoolSize())
+			}
+			s.autoPoolMu.Unlock()
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package fastscheduler
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestScheduler_AutoPoolSize(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(2))
+
+	scheduler := NewScheduler(AutoPoolSize, 10, WithPoolMultiplier(4))
+	defer scheduler.Stop()
+	workers := func() int { return scheduler.Stats().Workers.Size }
+	if n := workers(); n != 8 {
+		t.Fatalf("Expected 8 workers for GOMAXPROCS=2, got %d", n)
+	}
+
+	// GOMAXPROCS 变化后自动调整
+	runtime.GOMAXPROCS(3)
+	deadline := time.Now().Add(2 * time.Second)
+	for workers() != 12 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected 12 workers after GOMAXPROCS=3, got %d", workers())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 指定固定大小后不再自动调整
+	scheduler.Resize(5)
+	runtime.GOMAXPROCS(1)
+	time.Sleep(3 * autoPoolInterval)
+	if n := workers(); n != 5 {
+		t.Errorf("Expected fixed size 5 after Resize, got %d", n)
+	}
+
+	scheduler.Resize(AutoPoolSize)
+	if n := workers(); n != 4 {
+		t.Errorf("Expected 4 workers after Resize(AutoPoolSize), got %d", n)
+	}
+}
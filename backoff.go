@@ -0,0 +1,87 @@
+package fastscheduler
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff 退避策略，计算第 n 次(从1开始)等待的时长
+// 用于 RetryPolicy 的重试间隔、HedgeConfig 的对冲间隔和熔断的冷却时间，实现需要并发安全
+type Backoff interface {
+	Next(n int) time.Duration
+}
+
+// BackoffFunc 将普通函数适配为 Backoff
+type BackoffFunc func(n int) time.Duration
+
+// Next 实现 Backoff 接口
+func (f BackoffFunc) Next(n int) time.Duration {
+	return f(n)
+}
+
+// ConstantBackoff 每次等待相同的时长 d
+func ConstantBackoff(d time.Duration) Backoff {
+	return BackoffFunc(func(int) time.Duration { return d })
+}
+
+// ExponentialBackoff 第 n 次等待 base*2^(n-1)，不超过 maxDelay；maxDelay 小于等于0表示不设上限
+func ExponentialBackoff(base, maxDelay time.Duration) Backoff {
+	return BackoffFunc(func(n int) time.Duration {
+		return exponential(base, maxDelay, n)
+	})
+}
+
+// FullJitterBackoff 在 [0, base*2^(n-1)] 内均匀随机等待，上限同 ExponentialBackoff
+// 大量任务同时失败时随机化能把重试分散开，避免同时冲击后端
+func FullJitterBackoff(base, maxDelay time.Duration) Backoff {
+	return BackoffFunc(func(n int) time.Duration {
+		d := exponential(base, maxDelay, n)
+		if d <= 0 {
+			return 0
+		}
+		return rand.N(d + 1)
+	})
+}
+
+// FibonacciBackoff 按斐波那契数列增长：base、base、2*base、3*base、5*base…，不超过 maxDelay
+// maxDelay 小于等于0表示不设上限
+func FibonacciBackoff(base, maxDelay time.Duration) Backoff {
+	return BackoffFunc(func(n int) time.Duration {
+		prev, cur := time.Duration(0), base
+		for i := 1; i < n; i++ {
+			prev, cur = cur, prev+cur
+			if cur < prev || (maxDelay > 0 && cur >= maxDelay) {
+				return capDelay(cur, maxDelay)
+			}
+		}
+		return capDelay(cur, maxDelay)
+	})
+}
+
+// exponential 计算 base*2^(n-1)，溢出或超过 maxDelay 时返回上限
+func exponential(base, maxDelay time.Duration, n int) time.Duration {
+	d := base
+	for i := 1; i < n && d > 0; i++ {
+		if maxDelay > 0 && d >= maxDelay {
+			break
+		}
+		if d > math.MaxInt64/2 {
+			d = math.MaxInt64
+			break
+		}
+		d *= 2
+	}
+	return capDelay(d, maxDelay)
+}
+
+// capDelay 将 d 限制在 maxDelay 内，溢出为负数时同样返回上限
+func capDelay(d, maxDelay time.Duration) time.Duration {
+	if d < 0 {
+		d = math.MaxInt64
+	}
+	if maxDelay > 0 && d > maxDelay {
+		return maxDelay
+	}
+	return d
+}
@@ -0,0 +1,145 @@
+package fastscheduler
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBackoff_Strategies(t *testing.T) {
+	ms := time.Millisecond
+	cases := []struct {
+		name    string
+		backoff Backoff
+		want    []time.Duration
+	}{
+		{"constant", ConstantBackoff(10 * ms), []time.Duration{10 * ms, 10 * ms, 10 * ms}},
+		{"exponential", ExponentialBackoff(10*ms, 50*ms), []time.Duration{10 * ms, 20 * ms, 40 * ms, 50 * ms}},
+		{"fibonacci", FibonacciBackoff(10*ms, 45*ms), []time.Duration{10 * ms, 10 * ms, 20 * ms, 30 * ms, 45 * ms}},
+	}
+	for _, c := range cases {
+		for i, want := range c.want {
+			if got := c.backoff.Next(i + 1); got != want {
+				t.Errorf("%s: Next(%d) = %v, want %v", c.name, i+1, got, want)
+			}
+		}
+	}
+
+	// 没有上限时次数很大也不会溢出为负数
+	if d := ExponentialBackoff(time.Second, 0).Next(100); d <= 0 {
+		t.Errorf("Expected exponential backoff to saturate, got %v", d)
+	}
+	if d := FibonacciBackoff(time.Second, 0).Next(200); d <= 0 {
+		t.Errorf("Expected fibonacci backoff to saturate, got %v", d)
+	}
+
+	jitter := FullJitterBackoff(10*ms, 50*ms)
+	for n := 1; n <= 5; n++ {
+		for range 20 {
+			if d := jitter.Next(n); d < 0 || d > ExponentialBackoff(10*ms, 50*ms).Next(n) {
+				t.Fatalf("Jitter Next(%d) = %v out of range", n, d)
+			}
+		}
+	}
+}
+
+func TestTask_RetryBackoff(t *testing.T) {
+	scheduler := NewScheduler(1, 10)
+	defer scheduler.Stop()
+
+	var mu sync.Mutex
+	var asked []int
+	future, err := scheduler.Submit(&Task{
+		ID: "backoff",
+		Retry: &RetryPolicy{
+			MaxAttempts: 3,
+			Backoff: BackoffFunc(func(n int) time.Duration {
+				mu.Lock()
+				asked = append(asked, n)
+				mu.Unlock()
+				return time.Millisecond
+			}),
+		},
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: 503, BusinessCode: 1}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if r := future.Result(); r.Attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", r.Attempts)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Equal(asked, []int{1, 2}) {
+		t.Errorf("Expected backoff for retries 1 and 2, got %v", asked)
+	}
+}
+
+func TestScheduler_HedgeBackoff(t *testing.T) {
+	scheduler := NewScheduler(1, 10)
+	defer scheduler.Stop()
+
+	var mu sync.Mutex
+	var asked []int
+	future, err := scheduler.Submit(&Task{
+		ID: "hedged",
+		Hedge: &HedgeConfig{
+			MaxAttempts: 3,
+			Backoff: BackoffFunc(func(n int) time.Duration {
+				mu.Lock()
+				asked = append(asked, n)
+				mu.Unlock()
+				return 5 * time.Millisecond
+			}),
+		},
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			<-ctx.Done()
+			return TaskResult{HTTPCode: 499, BusinessCode: 1}, ctx.Err()
+		},
+		Timeout: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	future.Result()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(asked) < 2 || asked[0] != 1 || asked[1] != 2 {
+		t.Errorf("Expected hedge stagger for hedges 1 and 2, got %v", asked)
+	}
+}
+
+func TestCircuitBreaker_Backoff(t *testing.T) {
+	b := &breakerSet{
+		threshold: 1,
+		coolDown:  ExponentialBackoff(time.Second, time.Minute),
+		states:    make(map[string]*breakerState),
+	}
+	now := time.Unix(0, 0)
+	failed := TaskResult{HTTPCode: 502, BusinessCode: 1}
+
+	// 每次试探失败后冷却时间翻倍
+	for _, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		b.record("api", failed, nil, now)
+		if b.allow("api", now.Add(want-time.Millisecond)) {
+			t.Fatalf("Expected circuit open for %v", want)
+		}
+		now = now.Add(want)
+		if !b.allow("api", now) {
+			t.Fatalf("Expected probe after %v", want)
+		}
+	}
+
+	// 成功后重新从第1次计算
+	b.record("api", TaskResult{HTTPCode: 200}, nil, now)
+	b.record("api", failed, nil, now)
+	if !b.allow("api", now.Add(time.Second)) {
+		t.Error("Expected cool-down to restart after success")
+	}
+}
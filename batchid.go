@@ -0,0 +1,140 @@
+package fastscheduler
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WithBatchID 指定批次ID，默认自动分配；与未完成的批次重复时提交返回 ErrDuplicateBatchID
+func WithBatchID(id string) BatchOption {
+	return func(g *taskGroup) {
+		g.id = id
+	}
+}
+
+// batchRegistry 未完成的批次，按ID索引
+type batchRegistry struct {
+	mu      sync.RWMutex
+	batches map[string]*Batch
+	// seq 自动分配ID的序号
+	seq uint64
+}
+
+// register 为批次分配ID(未指定时)并登记
+func (r *batchRegistry) register(b *Batch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.batches == nil {
+		r.batches = make(map[string]*Batch)
+	}
+	g := b.group
+	if g.id == "" {
+		for {
+			r.seq++
+			g.id = fmt.Sprintf("batch-%d", r.seq)
+			if _, taken := r.batches[g.id]; !taken {
+				break
+			}
+		}
+	} else if _, dup := r.batches[g.id]; dup {
+		return fmt.Errorf("%w: %s", ErrDuplicateBatchID, g.id)
+	}
+	r.batches[g.id] = b
+	return nil
+}
+
+// forget 批次完成后移除登记
+func (r *batchRegistry) forget(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.batches, id)
+}
+
+// newBatch 按批次选项创建批次并登记
+func (s *Scheduler) newBatch(lane Lane, tasks []*Task, opts []BatchOption) (*Batch, error) {
+	group := s.newGroup(opts)
+	group.created = s.now()
+	group.lane = lane
+	batch := &Batch{
+		Tasks: tasks,
+		group: group,
+		lane:  lane,
+		opts:  opts,
+	}
+	if err := s.batches.register(batch); err != nil {
+		group.cancel()
+		if group.stopBase != nil {
+			group.stopBase()
+		}
+		return nil, err
+	}
+	s.logBatch(slog.LevelDebug, "batch created", group, slog.Int("tasks", len(tasks)))
+	return batch, nil
+}
+
+// ID 返回批次ID
+func (b *Batch) ID() string {
+	return b.group.id
+}
+
+// Batch 返回指定ID的未完成批次，不存在或已完成时返回nil
+func (s *Scheduler) Batch(id string) *Batch {
+	s.batches.mu.RLock()
+	defer s.batches.mu.RUnlock()
+	return s.batches.batches[id]
+}
+
+// BatchInfo 未完成批次的状态
+type BatchInfo struct {
+	ID   string
+	Lane Lane
+	// Created 批次创建的时间
+	Created time.Time
+	// Done 已完成的任务数，Total 已加入的任务数
+	Done, Total int
+	// Succeeded 是否已有任务成功
+	Succeeded bool
+	// Open 是否为尚未关闭的开放批次
+	Open bool
+}
+
+// Info 返回批次当前的状态
+func (b *Batch) Info() BatchInfo {
+	done, total := b.Progress()
+	return BatchInfo{
+		ID:        b.group.id,
+		Lane:      b.lane,
+		Created:   b.group.created,
+		Done:      done,
+		Total:     total,
+		Succeeded: b.IsSuccess(),
+		Open:      !b.group.closed.Load(),
+	}
+}
+
+// Batches 返回所有未完成批次的状态，按创建时间排序
+func (s *Scheduler) Batches() []BatchInfo {
+	s.batches.mu.RLock()
+	infos := make([]BatchInfo, 0, len(s.batches.batches))
+	for _, b := range s.batches.batches {
+		infos = append(infos, b.Info())
+	}
+	s.batches.mu.RUnlock()
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Created.Before(infos[j].Created) })
+	return infos
+}
+
+// Cancel 取消批次中尚未完成的任务，执行中任务的 ctx 被取消，已完成的结果不受影响；
+// 通过 Then 派生的后续任务链一并取消
+func (b *Batch) Cancel() {
+	b.group.cancelAll()
+}
+
+// StopPending 不再开始批次中尚未执行的任务，它们以 context.Canceled 完成；
+// 与 Cancel 不同，执行中任务的 ctx 不会被取消，适合开始后不应被中断的有副作用任务
+func (b *Batch) StopPending() {
+	b.group.stopPending()
+}
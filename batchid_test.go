@@ -0,0 +1,67 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestScheduler_BatchRegistry(t *testing.T) {
+	scheduler := NewScheduler(2, 10)
+	defer scheduler.Stop()
+
+	release := make(chan struct{})
+	blocking := func() []*Task {
+		return []*Task{{
+			ID: "block",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				select {
+				case <-release:
+					return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+				case <-ctx.Done():
+					return TaskResult{}, ctx.Err()
+				}
+			},
+		}}
+	}
+
+	named, err := scheduler.SubmitBatch(blocking(), WithBatchID("nightly-report"))
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	auto, err := scheduler.SubmitBatch(blocking())
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	if named.ID() != "nightly-report" || auto.ID() == "" || auto.ID() == named.ID() {
+		t.Errorf("Unexpected batch IDs %q and %q", named.ID(), auto.ID())
+	}
+	if _, err := scheduler.SubmitBatch(blocking(), WithBatchID("nightly-report")); !errors.Is(err, ErrDuplicateBatchID) {
+		t.Errorf("Expected ErrDuplicateBatchID, got %v", err)
+	}
+
+	if scheduler.Batch("nightly-report") != named {
+		t.Error("Batch lookup returned a different batch")
+	}
+	infos := scheduler.Batches()
+	if len(infos) != 2 || infos[0].ID != "nightly-report" || infos[0].Total != 1 {
+		t.Errorf("Unexpected active batches %+v", infos)
+	}
+
+	// 取消一个批次，另一个正常完成
+	scheduler.Batch(auto.ID()).Cancel()
+	auto.Wait()
+	if auto.Outcome() != OutcomeCancelled {
+		t.Errorf("Expected cancelled batch, got %s", auto.Outcome())
+	}
+	close(release)
+	named.Wait()
+
+	if scheduler.Batch("nightly-report") != nil || len(scheduler.Batches()) != 0 {
+		t.Error("Completed batches should be removed from the registry")
+	}
+	// 完成后ID可以再次使用
+	if _, err := scheduler.SubmitBatch(nil, WithBatchID("nightly-report")); err != nil {
+		t.Errorf("Reusing a completed batch ID failed: %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+// Package bench 提供调度器的基准测试工具：预置的负载模型和吞吐/延迟/分配报告
+// 用于在调整调度器实现或配置时发现性能回退
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+// Profile 描述一种负载
+type Profile struct {
+	// Name 负载名称，出现在报告中
+	Name string
+	// Batches 提交的批次数
+	Batches int
+	// BatchSize 每个批次的任务数
+	BatchSize int
+	// Concurrency 同时提交批次的goroutine数，默认1
+	Concurrency int
+	// Work 返回单个任务的执行时长，为nil时任务立即返回
+	Work func(r *rand.Rand) time.Duration
+	// Pause 返回同一提交goroutine两个批次之间的间隔，为nil时连续提交
+	Pause func(r *rand.Rand) time.Duration
+}
+
+// UniformFast 大量立即返回的小批次，衡量调度本身的开销
+func UniformFast() Profile {
+	return Profile{Name: "uniform-fast", Batches: 2000, BatchSize: 10, Concurrency: 4}
+}
+
+// HeavyTailed 执行时长服从帕累托分布，多数任务很快，少数任务很慢
+func HeavyTailed() Profile {
+	return Profile{
+		Name:        "heavy-tailed",
+		Batches:     200,
+		BatchSize:   10,
+		Concurrency: 4,
+		Work: func(r *rand.Rand) time.Duration {
+			// 帕累托分布，最小 100µs，形状参数 1.5，上限 20ms
+			d := 100 * time.Microsecond.Seconds() / math.Pow(1-r.Float64(), 1/1.5)
+			return min(time.Duration(d*float64(time.Second)), 20*time.Millisecond)
+		},
+	}
+}
+
+// Bursty 突发提交：一批批次集中到达后静默一段时间
+func Bursty() Profile {
+	return Profile{
+		Name:        "bursty",
+		Batches:     200,
+		BatchSize:   20,
+		Concurrency: 8,
+		Work: func(r *rand.Rand) time.Duration {
+			return time.Duration(r.IntN(200)) * time.Microsecond
+		},
+		Pause: func(r *rand.Rand) time.Duration {
+			if r.IntN(10) == 0 {
+				return 5 * time.Millisecond
+			}
+			return 0
+		},
+	}
+}
+
+// HugeBatches 少量超大批次，衡量批次准备和结果汇总的开销
+func HugeBatches() Profile {
+	return Profile{Name: "huge-batches", Batches: 4, BatchSize: 10000, Concurrency: 1}
+}
+
+// Profiles 返回所有预置负载
+func Profiles() []Profile {
+	return []Profile{UniformFast(), HeavyTailed(), Bursty(), HugeBatches()}
+}
+
+// Report 一次基准运行的结果
+type Report struct {
+	Profile string
+	Tasks   int
+	Elapsed time.Duration
+	// Throughput 每秒完成的任务数
+	Throughput float64
+	// P50/P99/Max 任务从批次提交到完成的延迟
+	P50 time.Duration
+	P99 time.Duration
+	Max time.Duration
+	// AllocsPerTask/BytesPerTask 每个任务的平均堆分配次数和字节数
+	AllocsPerTask float64
+	BytesPerTask  float64
+}
+
+// Run 在调度器上运行负载并返回报告，调度器由调用方创建和停止
+// 任务都以失败结果返回，避免首个成功取消同批次其他任务而影响测量
+func Run(ctx context.Context, s *fastscheduler.Scheduler, p Profile) (Report, error) {
+	concurrency := max(p.Concurrency, 1)
+	latencies := make([]time.Duration, p.Batches*p.BatchSize)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := rand.New(rand.NewPCG(uint64(w), 0))
+			for b := w; b < p.Batches; b += concurrency {
+				if ctx.Err() != nil {
+					return
+				}
+				if err := runBatch(s, p, r, latencies[b*p.BatchSize:(b+1)*p.BatchSize]); err != nil {
+					errOnce.Do(func() { firstErr = err })
+					return
+				}
+				if p.Pause != nil {
+					time.Sleep(p.Pause(r))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	if firstErr != nil {
+		return Report{}, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return Report{}, err
+	}
+
+	tasks := len(latencies)
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	report := Report{
+		Profile:       p.Name,
+		Tasks:         tasks,
+		Elapsed:       elapsed,
+		AllocsPerTask: float64(after.Mallocs-before.Mallocs) / float64(max(tasks, 1)),
+		BytesPerTask:  float64(after.TotalAlloc-before.TotalAlloc) / float64(max(tasks, 1)),
+	}
+	if tasks > 0 {
+		report.Throughput = float64(tasks) / elapsed.Seconds()
+		report.P50 = latencies[tasks*50/100]
+		report.P99 = latencies[tasks*99/100]
+		report.Max = latencies[tasks-1]
+	}
+	return report, nil
+}
+
+// runBatch 提交一个批次并记录每个任务的延迟
+func runBatch(s *fastscheduler.Scheduler, p Profile, r *rand.Rand, latencies []time.Duration) error {
+	tasks := make([]*fastscheduler.Task, len(latencies))
+	submitted := time.Now()
+	for i := range tasks {
+		var work time.Duration
+		if p.Work != nil {
+			work = p.Work(r)
+		}
+		tasks[i] = &fastscheduler.Task{
+			ID: "bench",
+			Execute: func(ctx context.Context) (fastscheduler.TaskResult, error) {
+				if work > 0 {
+					time.Sleep(work)
+				}
+				latencies[i] = time.Since(submitted)
+				return fastscheduler.TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+			},
+		}
+	}
+	batch, err := s.SubmitBatch(tasks)
+	if err != nil {
+		return err
+	}
+	batch.Wait()
+	return nil
+}
+
+// WriteTable 以表格形式输出报告
+func WriteTable(w io.Writer, reports []Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "profile\ttasks\telapsed\ttasks/s\tp50\tp99\tmax\tallocs/task\tB/task\t")
+	for _, r := range reports {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%.0f\t%s\t%s\t%s\t%.1f\t%.0f\t\n",
+			r.Profile, r.Tasks, r.Elapsed.Round(time.Millisecond), r.Throughput,
+			r.P50.Round(time.Microsecond), r.P99.Round(time.Microsecond), r.Max.Round(time.Microsecond),
+			r.AllocsPerTask, r.BytesPerTask)
+	}
+	return tw.Flush()
+}
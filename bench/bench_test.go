@@ -0,0 +1,60 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+func TestRun(t *testing.T) {
+	scheduler := fastscheduler.NewScheduler(4, 64)
+	defer scheduler.Stop()
+
+	var reports []Report
+	for _, p := range Profiles() {
+		// 缩小规模，只验证报告是否完整
+		p.Batches = 4
+		if p.BatchSize > 100 {
+			p.BatchSize = 100
+		}
+		report, err := Run(context.Background(), scheduler, p)
+		if err != nil {
+			t.Fatalf("%s: %v", p.Name, err)
+		}
+		if report.Tasks != p.Batches*p.BatchSize || report.Throughput <= 0 || report.P99 < report.P50 {
+			t.Errorf("%s: incomplete report %+v", p.Name, report)
+		}
+		reports = append(reports, report)
+	}
+
+	var table bytes.Buffer
+	if err := WriteTable(&table, reports); err != nil {
+		t.Fatalf("WriteTable failed: %v", err)
+	}
+	if lines := strings.Count(table.String(), "\n"); lines != len(reports)+1 {
+		t.Errorf("Expected header and %d rows, got:\n%s", len(reports), table.String())
+	}
+}
+
+func BenchmarkProfiles(b *testing.B) {
+	for _, p := range Profiles() {
+		b.Run(p.Name, func(b *testing.B) {
+			scheduler := fastscheduler.NewScheduler(runtime.GOMAXPROCS(0)*4, 1024)
+			defer scheduler.Stop()
+
+			for i := 0; i < b.N; i++ {
+				report, err := Run(context.Background(), scheduler, p)
+				if err != nil {
+					b.Fatal(err)
+				}
+				b.ReportMetric(report.Throughput, "tasks/s")
+				b.ReportMetric(float64(report.P99.Microseconds()), "p99-µs")
+				b.ReportMetric(report.AllocsPerTask, "allocs/task")
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package fastscheduler
+
+import (
+	"context"
+	"runtime"
+	"testing"
+)
+
+// 调度吞吐基准，用于评估调度器内部设计的改动
+
+func noopExecute(ctx context.Context) (TaskResult, error) {
+	return TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+}
+
+func BenchmarkScheduler_Batch1000(b *testing.B) {
+	benchmarkBatch1000(b)
+}
+
+func BenchmarkScheduler_Batch1000_WorkStealing(b *testing.B) {
+	benchmarkBatch1000(b, WithWorkStealing(0))
+}
+
+func benchmarkBatch1000(b *testing.B, opts ...Option) {
+	scheduler := NewScheduler(runtime.GOMAXPROCS(0), 1024, opts...)
+	defer scheduler.Stop()
+
+	tasks := make([]*Task, 1000)
+	for i := range tasks {
+		tasks[i] = &Task{ID: "noop", Execute: noopExecute}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		batch, err := scheduler.SubmitBatch(tasks)
+		if err != nil {
+			b.Fatal(err)
+		}
+		batch.Wait()
+	}
+	b.ReportMetric(float64(b.N*len(tasks))/b.Elapsed().Seconds(), "tasks/s")
+}
+
+func BenchmarkScheduler_ParallelSubmit(b *testing.B) {
+	benchmarkParallelSubmit(b)
+}
+
+func BenchmarkScheduler_ParallelSubmit_WorkStealing(b *testing.B) {
+	benchmarkParallelSubmit(b, WithWorkStealing(0))
+}
+
+func benchmarkParallelSubmit(b *testing.B, opts ...Option) {
+	scheduler := NewScheduler(runtime.GOMAXPROCS(0), 1024, opts...)
+	defer scheduler.Stop()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		task := &Task{ID: "noop", Execute: noopExecute}
+		for pb.Next() {
+			future, err := scheduler.Submit(task)
+			if err != nil {
+				b.Fatal(err)
+			}
+			<-future.Done()
+		}
+	})
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "tasks/s")
+}
@@ -0,0 +1,46 @@
+package fastscheduler
+
+import "time"
+
+// WithBestResult 批次中的任务全部执行完毕，由 less 从成功的结果中选出最优的一个作为获胜结果，
+// 而不是第一个成功的结果，例如多个供应商中报价最低、多个副本中时间戳最新的结果。
+// less(a, b) 返回 a 是否优于 b，同样优的结果取先完成的。
+// 获胜结果在批次完成时确定：WaitFirstSuccess、Future.Result、Then 和 Summary 得到的都是最优结果，
+// 批次完成前 IsSuccess 返回 false
+func WithBestResult(less func(a, b TaskResult) bool) BatchOption {
+	return func(g *taskGroup) {
+		g.cancelOnSuccess = false
+		g.best = &bestState{less: less}
+	}
+}
+
+// bestState 目前最优的成功结果，由 errMu 保护
+type bestState struct {
+	less   func(a, b TaskResult) bool
+	found  bool
+	result TaskResult
+	at     time.Time
+}
+
+// offerBest 记录一个成功结果，优于目前最优结果时替换
+func (g *taskGroup) offerBest(result TaskResult, at time.Time) {
+	g.errMu.Lock()
+	defer g.errMu.Unlock()
+	if b := g.best; !b.found || b.less(result, b.result) {
+		b.found, b.result, b.at = true, result, at
+	}
+}
+
+// pickBest 批次完成时将最优结果设为获胜结果
+func (g *taskGroup) pickBest() {
+	g.errMu.Lock()
+	b := *g.best
+	g.errMu.Unlock()
+	if !b.found {
+		return
+	}
+	g.success.Store(true)
+	g.winner = b.result
+	g.winnerAt = b.at
+	close(g.succeeded)
+}
@@ -0,0 +1,48 @@
+package fastscheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBatch_BestResult(t *testing.T) {
+	scheduler := NewScheduler(3, 10)
+	defer scheduler.Stop()
+
+	quote := func(id string, delay time.Duration, price float64) *Task {
+		return &Task{
+			ID: id,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				time.Sleep(delay)
+				if price < 0 {
+					return TaskResult{HTTPCode: 502}, nil
+				}
+				return TaskResult{HTTPCode: 200, Data: price}, nil
+			},
+		}
+	}
+	cheapest := func(a, b TaskResult) bool { return a.Data.(float64) < b.Data.(float64) }
+
+	// 最便宜的报价最后返回，仍然是获胜结果；先成功的任务不会取消其他任务
+	batch, err := scheduler.SubmitBatch([]*Task{
+		quote("fast", 0, 12.5),
+		quote("broken", 10*time.Millisecond, -1),
+		quote("slow", 30*time.Millisecond, 9.9),
+	}, WithBestResult(cheapest))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	best, err := batch.WaitFirstSuccess(context.Background())
+	if err != nil {
+		t.Fatalf("WaitFirstSuccess failed: %v", err)
+	}
+	if best.TaskID != "slow" {
+		t.Errorf("Best result = %s, want slow", best.TaskID)
+	}
+	batch.Wait()
+	sum := batch.Summary()
+	if sum.Winner.TaskID != "slow" || sum.SucceededTasks != 2 || sum.FailedTasks != 1 {
+		t.Errorf("Unexpected summary %+v", sum)
+	}
+}
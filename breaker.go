@@ -0,0 +1,93 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// WithCircuitBreaker 按 Task.Key 启用熔断
+// 同一 Key 连续 threshold 次执行失败后熔断 coolDown 时长，期间该 Key 的任务不再执行，
+// 直接以 ErrCircuitOpen 完成；冷却结束后放行一次试探执行，成功则恢复，失败则再次熔断
+func WithCircuitBreaker(threshold int, coolDown time.Duration) Option {
+	return WithCircuitBreakerBackoff(threshold, ConstantBackoff(coolDown))
+}
+
+// WithCircuitBreakerBackoff 与 WithCircuitBreaker 相同，但冷却时间由 coolDown 按连续熔断次数计算，
+// 例如试探执行反复失败时用 ExponentialBackoff 逐次拉长冷却；Key 执行成功后重新从第1次计算
+func WithCircuitBreakerBackoff(threshold int, coolDown Backoff) Option {
+	return func(s *Scheduler) {
+		if threshold > 0 {
+			s.breakers = &breakerSet{
+				threshold: threshold,
+				coolDown:  coolDown,
+				states:    make(map[string]*breakerState),
+			}
+		}
+	}
+}
+
+// breakerSet 各 Key 的熔断状态
+type breakerSet struct {
+	mu        sync.Mutex
+	threshold int
+	coolDown  Backoff
+	states    map[string]*breakerState
+}
+
+// breakerState 单个 Key 的熔断状态
+type breakerState struct {
+	// failures 连续失败次数
+	failures int
+	// openUntil 熔断结束时间，零值表示未熔断
+	openUntil time.Time
+	// probing 冷却结束后是否已放行试探执行
+	probing bool
+	// trips 连续熔断次数
+	trips int
+}
+
+// allow 判断 key 的任务在 now 时是否可以执行
+func (b *breakerSet) allow(key string, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	st := b.states[key]
+	if st == nil || st.openUntil.IsZero() {
+		return true
+	}
+	if now.Before(st.openUntil) || st.probing {
+		return false
+	}
+	st.probing = true
+	return true
+}
+
+// record 记录一次执行结果，被取消的执行不计入失败，只释放试探名额
+func (b *breakerSet) record(key string, result TaskResult, err error, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil && isSuccess(result) {
+		delete(b.states, key)
+		return
+	}
+	st := b.states[key]
+	if errors.Is(err, context.Canceled) {
+		if st != nil {
+			st.probing = false
+		}
+		return
+	}
+	if st == nil {
+		st = &breakerState{}
+		b.states[key] = st
+	}
+	st.failures++
+	if st.probing || st.failures >= b.threshold {
+		st.trips++
+		st.openUntil = now.Add(b.coolDown.Next(st.trips))
+		st.probing = false
+	}
+}
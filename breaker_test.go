@@ -0,0 +1,53 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_CircuitBreaker(t *testing.T) {
+	scheduler := NewScheduler(2, 10, WithCircuitBreaker(2, 50*time.Millisecond))
+	defer scheduler.Stop()
+
+	var calls atomic.Int32
+	var healthy atomic.Bool
+	call := func() TaskResult {
+		future, err := scheduler.Submit(&Task{
+			ID:  "payments",
+			Key: "payments-api",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				calls.Add(1)
+				if healthy.Load() {
+					return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+				}
+				return TaskResult{HTTPCode: 502, BusinessCode: 1}, nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		return future.Result()
+	}
+
+	call()
+	call()
+	if r := call(); !errors.Is(r.Err, ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen after consecutive failures, got %+v", r)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Open circuit should not execute tasks, got %d calls", calls.Load())
+	}
+
+	// 冷却结束后试探执行成功，熔断恢复
+	time.Sleep(60 * time.Millisecond)
+	healthy.Store(true)
+	if r := call(); r.HTTPCode != 200 {
+		t.Errorf("Expected probe to succeed, got %+v", r)
+	}
+	if r := call(); r.HTTPCode != 200 {
+		t.Errorf("Expected closed circuit, got %+v", r)
+	}
+}
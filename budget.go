@@ -0,0 +1,53 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+)
+
+// WithCostBudget 设置批次的成本预算
+// 任务按 Task.Cost 预估超出剩余预算时不再执行；已执行任务(包括重试)的实际成本
+// 累计达到预算后，取消批次中其余任务。因预算未执行或被取消的任务以 ErrBudgetExceeded 完成
+func WithCostBudget(max float64) BatchOption {
+	return func(g *taskGroup) {
+		g.budget = max
+	}
+}
+
+// admitCost 判断任务的预估成本是否仍在批次预算内
+func (g *taskGroup) admitCost(estimate float64) bool {
+	if g.budget <= 0 {
+		return true
+	}
+	g.costMu.Lock()
+	defer g.costMu.Unlock()
+	return !g.overBudget && g.cost+estimate <= g.budget
+}
+
+// chargeCost 累加批次成本，首次达到预算时取消批次中其余任务
+func (g *taskGroup) chargeCost(cost float64) {
+	g.costMu.Lock()
+	g.cost += cost
+	exceeded := g.budget > 0 && !g.overBudget && g.cost >= g.budget
+	if exceeded {
+		g.overBudget = true
+	}
+	g.costMu.Unlock()
+
+	if exceeded {
+		g.cancel()
+	}
+}
+
+// budgetError 将预算耗尽导致的取消转换为 ErrBudgetExceeded
+func (g *taskGroup) budgetError(err error) error {
+	if g.budget <= 0 || !errors.Is(err, context.Canceled) {
+		return err
+	}
+	g.costMu.Lock()
+	defer g.costMu.Unlock()
+	if g.overBudget {
+		return ErrBudgetExceeded
+	}
+	return err
+}
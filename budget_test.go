@@ -0,0 +1,76 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBatch_CostBudget(t *testing.T) {
+	scheduler := NewScheduler(1, 10)
+	defer scheduler.Stop()
+
+	var runs atomic.Int32
+	var tasks []*Task
+	for i := 0; i < 5; i++ {
+		tasks = append(tasks, &Task{
+			ID: fmt.Sprintf("call-%d", i),
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				runs.Add(1)
+				// 实际成本由任务上报，预估成本为0
+				return TaskResult{HTTPCode: 500, BusinessCode: 1, Cost: 10}, nil
+			},
+		})
+	}
+
+	batch, err := scheduler.SubmitBatch(tasks, WithCostBudget(25))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	if runs.Load() != 3 {
+		t.Errorf("Expected 3 runs before budget was reached, got %d", runs.Load())
+	}
+	if !errors.Is(batch.Err(), ErrBudgetExceeded) {
+		t.Errorf("Expected ErrBudgetExceeded, got %v", batch.Err())
+	}
+	if got := batch.Outcome(); got != OutcomeBudgetExceeded {
+		t.Errorf("Expected budget exceeded outcome, got %v", got)
+	}
+}
+
+func TestBatch_CostBudgetAdmission(t *testing.T) {
+	scheduler := NewScheduler(1, 10)
+	defer scheduler.Stop()
+
+	var runs atomic.Int32
+	var tasks []*Task
+	for i := 0; i < 5; i++ {
+		tasks = append(tasks, &Task{
+			ID:    fmt.Sprintf("call-%d", i),
+			Cost:  10,
+			Retry: &RetryPolicy{MaxAttempts: 2},
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				runs.Add(1)
+				return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+			},
+		})
+	}
+
+	// 重试同样消耗预算：两次执行后剩余预算不足以再执行任何任务
+	batch, err := scheduler.SubmitBatch(tasks, WithCostBudget(25))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	if runs.Load() != 2 {
+		t.Errorf("Expected 2 runs within budget, got %d", runs.Load())
+	}
+	if batch.Cost() != 20 {
+		t.Errorf("Expected batch cost 20, got %v", batch.Cost())
+	}
+}
@@ -0,0 +1,151 @@
+package fastscheduler
+
+import (
+	"fmt"
+	"sync"
+)
+
+// WithBulkhead 注册名为 name、并发数为 size 的隔离舱
+// Task.Bulkhead 为该名称的任务不使用共享worker池，而是在隔离舱自己的 size 个名额内按提交顺序执行，
+// 某类任务变慢时只会占满自己的隔离舱，不会挤占共享池和其他隔离舱。
+// 隔离舱中的任务不受优先级通道、配额和队列容量影响，暂停、停止与共享池一致；同步模式下不生效。
+// 任务指定了未注册的隔离舱时以 ErrUnknownBulkhead 完成
+func WithBulkhead(name string, size int) Option {
+	return func(s *Scheduler) {
+		if size <= 0 {
+			return
+		}
+		if s.bulkheads == nil {
+			s.bulkheads = make(map[string]*bulkhead)
+		}
+		s.bulkheads[name] = newBulkhead("bulkhead "+name, size)
+	}
+}
+
+// bulkhead 独立的worker池和等待队列，用于命名隔离舱和按任务类型划分的池
+type bulkhead struct {
+	// label 调度决策中显示的名称
+	label string
+	slots *workerSlots
+
+	mu      sync.Mutex
+	pending []*Task
+	// queued 有新任务或暂停状态变化时的信号
+	queued chan struct{}
+}
+
+// newBulkhead 创建容量为 size 的独立worker池
+func newBulkhead(label string, size int) *bulkhead {
+	return &bulkhead{label: label, slots: newWorkerSlots(size), queued: make(chan struct{}, 1)}
+}
+
+// bulkheadFor 返回执行任务的独立worker池：指定的隔离舱优先，其次是任务类型对应的池，
+// 都没有时返回nil，任务使用共享worker池
+func (s *Scheduler) bulkheadFor(t *Task) (*bulkhead, error) {
+	if t.Bulkhead == "" {
+		return s.classPool(t.Class), nil
+	}
+	b, ok := s.bulkheads[t.Bulkhead]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBulkhead, t.Bulkhead)
+	}
+	return b, nil
+}
+
+// push 放入任务并唤醒隔离舱的调度goroutine
+func (b *bulkhead) push(t *Task) {
+	t.bulkhead = b
+	b.mu.Lock()
+	b.pending = append(b.pending, t)
+	b.mu.Unlock()
+	b.signal()
+}
+
+func (b *bulkhead) signal() {
+	select {
+	case b.queued <- struct{}{}:
+	default:
+	}
+}
+
+// nextBulkheadTask 取出隔离舱的下一个任务，暂停期间和队列为空时等待，stop 关闭时返回false
+func (s *Scheduler) nextBulkheadTask(b *bulkhead, stop <-chan struct{}) (*Task, bool) {
+	for {
+		if !s.paused.Load() {
+			b.mu.Lock()
+			if len(b.pending) > 0 {
+				t := b.pending[0]
+				b.pending[0] = nil
+				b.pending = b.pending[1:]
+				b.mu.Unlock()
+				return t, true
+			}
+			b.mu.Unlock()
+		}
+		select {
+		case <-b.queued:
+		case <-stop:
+			return nil, false
+		}
+	}
+}
+
+// unpop 将已取出但因停止未能执行的任务放回队首，重新启动后最先调度
+func (b *bulkhead) unpop(t *Task) {
+	b.mu.Lock()
+	b.pending = append([]*Task{t}, b.pending...)
+	b.mu.Unlock()
+}
+
+// runBulkhead 隔离舱的调度循环：先获取隔离舱的名额再取任务，每个任务在独立goroutine中执行
+func (s *Scheduler) runBulkhead(b *bulkhead, stop <-chan struct{}) {
+	for {
+		if !b.slots.acquire(stop) {
+			return
+		}
+		task, ok := s.nextBulkheadTask(b, stop)
+		if !ok {
+			b.slots.release()
+			return
+		}
+		if !b.slots.acquireWeight(task, stop) {
+			b.slots.release()
+			b.unpop(task)
+			return
+		}
+		s.recordDecision(task, task.lane, false)
+		s.wg.Add(1)
+		go s.executeTask(task)
+	}
+}
+
+// allBulkheads 返回所有独立worker池，包括命名隔离舱和按任务类型划分的池
+func (s *Scheduler) allBulkheads() []*bulkhead {
+	var all []*bulkhead
+	for _, b := range s.bulkheads {
+		all = append(all, b)
+	}
+	for _, b := range s.classPools {
+		if b != nil {
+			all = append(all, b)
+		}
+	}
+	return all
+}
+
+// signalBulkheads 暂停状态变化时唤醒所有独立worker池的调度goroutine
+func (s *Scheduler) signalBulkheads() {
+	for _, b := range s.allBulkheads() {
+		b.signal()
+	}
+}
+
+// BulkheadUsage 返回隔离舱执行中的任务数和容量，隔离舱不存在时返回 ok=false
+func (s *Scheduler) BulkheadUsage(name string) (busy, size int, ok bool) {
+	b, ok := s.bulkheads[name]
+	if !ok {
+		return 0, 0, false
+	}
+	busy, size = b.slots.usage()
+	return busy, size, true
+}
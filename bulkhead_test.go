@@ -0,0 +1,92 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_Bulkhead(t *testing.T) {
+	scheduler := NewScheduler(2, 50, WithBulkhead("payments", 3))
+	defer scheduler.Stop()
+
+	// 慢的支付任务只占满自己的隔离舱
+	release := make(chan struct{})
+	var inflight, peak atomic.Int32
+	var slow []*Task
+	for i := 0; i < 10; i++ {
+		slow = append(slow, &Task{
+			ID:       fmt.Sprintf("charge-%d", i),
+			Bulkhead: "payments",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				n := inflight.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				<-release
+				inflight.Add(-1)
+				return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+			},
+		})
+	}
+	payments, err := scheduler.SubmitBatch(slow)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for inflight.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	future, err := scheduler.Submit(&Task{
+		ID: "search",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: 200}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := future.Wait(ctx); err != nil {
+		t.Fatalf("Shared pool was starved by the bulkhead: %v", err)
+	}
+	if busy, size, ok := scheduler.BulkheadUsage("payments"); !ok || busy != 3 || size != 3 {
+		t.Errorf("Expected payments bulkhead full (3/3), got %d/%d ok=%v", busy, size, ok)
+	}
+
+	close(release)
+	payments.Wait()
+	if p := peak.Load(); p != 3 {
+		t.Errorf("Expected at most 3 concurrent payments, got %d", p)
+	}
+}
+
+func TestScheduler_UnknownBulkhead(t *testing.T) {
+	scheduler := NewScheduler(1, 10)
+	defer scheduler.Stop()
+
+	future, err := scheduler.Submit(&Task{
+		ID:       "lost",
+		Bulkhead: "missing",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: 200}, nil
+		},
+	})
+	if err != nil && !errors.Is(err, ErrUnknownBulkhead) {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if err == nil {
+		if r := future.Result(); !errors.Is(r.Err, ErrUnknownBulkhead) {
+			t.Errorf("Expected ErrUnknownBulkhead, got %v", r.Err)
+		}
+	}
+}
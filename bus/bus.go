@@ -0,0 +1,159 @@
+// Package bus 从消息总线消费批次提交，按执行结果确认或否认消息
+// 其他服务只需向主题发布JSON格式的 BatchMessage，无需引入 Go 包
+//
+// 子包 natsbus 和 kafkabus 分别基于 nats.go 和 franz-go 从 NATS JetStream 和 Kafka 消费，
+// 它们是独立的Go模块，本包不依赖消息系统的客户端库；
+// 其他系统实现 Source 和 Message 即可接入(Message 与 nats.go 的 jetstream.Msg 方法一致，也可以直接使用)
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+// Message 总线上的一条消息
+type Message interface {
+	// Data 返回消息内容
+	Data() []byte
+	// Ack 确认消息已处理
+	Ack() error
+	// Nak 否认消息，总线稍后重新投递
+	Nak() error
+}
+
+// Source 按顺序拉取消息，ctx 结束时返回 ctx.Err()
+type Source interface {
+	Fetch(ctx context.Context) (Message, error)
+}
+
+// SourceFunc 将函数适配为 Source
+type SourceFunc func(ctx context.Context) (Message, error)
+
+// Fetch 调用 f
+func (f SourceFunc) Fetch(ctx context.Context) (Message, error) {
+	return f(ctx)
+}
+
+// BatchMessage 消息的JSON格式：一批已注册类型的任务
+// 例如 {"tasks":[{"id":"a","type":"quote.fetch","payload":"eyJzeW0iOiJBQkMifQ=="}]}
+// payload 按 encoding/json 对 []byte 的约定使用 base64 编码
+type BatchMessage struct {
+	Tasks []fastscheduler.SerializableTask `json:"tasks"`
+}
+
+// Config 消费配置
+type Config struct {
+	// Concurrency 同时执行的批次数，默认16
+	Concurrency int
+	// Options 每个批次的选项
+	Options []fastscheduler.BatchOption
+	// OnBatch 批次完成后、确认消息前调用，可用于发布结果
+	OnBatch func(msg BatchMessage, batch *fastscheduler.Batch)
+	// OnError 报告无法解析的消息和确认失败，为nil时忽略
+	OnError func(err error)
+}
+
+// defaultConcurrency 默认同时执行的批次数
+const defaultConcurrency = 16
+
+// Consume 从 src 拉取消息并提交到 s，直到 ctx 结束或 src 返回错误
+// 批次有任务成功时 Ack，全部失败、超时或被取消时 Nak 以便重新投递；
+// 无法解析或任务类型未注册的消息重新投递也不会成功，Ack 后通过 OnError 报告。
+// 调度器停止时返回 ErrSchedulerStopped。返回前等待已提交的批次完成并确认
+func Consume(ctx context.Context, s *fastscheduler.Scheduler, src Source, cfg Config) error {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = defaultConcurrency
+	}
+	report := func(err error) {
+		if cfg.OnError != nil {
+			cfg.OnError(err)
+		}
+	}
+
+	slots := make(chan struct{}, cfg.Concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		msg, err := src.Fetch(ctx)
+		if err != nil {
+			<-slots
+			return err
+		}
+
+		batchMsg, tasks, err := decode(msg.Data())
+		if err != nil {
+			<-slots
+			report(err)
+			if err := msg.Ack(); err != nil {
+				report(fmt.Errorf("bus: ack: %w", err))
+			}
+			continue
+		}
+		batch, err := s.SubmitBatch(tasks, cfg.Options...)
+		if batch == nil {
+			<-slots
+			if err := msg.Nak(); err != nil {
+				report(fmt.Errorf("bus: nak: %w", err))
+			}
+			if errors.Is(err, fastscheduler.ErrSchedulerStopped) {
+				return err
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			batch.Wait()
+			if cfg.OnBatch != nil {
+				cfg.OnBatch(batchMsg, batch)
+			}
+			settle := msg.Ack
+			if !batch.IsSuccess() {
+				settle = msg.Nak
+			}
+			if err := settle(); err != nil {
+				report(fmt.Errorf("bus: settle message: %w", err))
+			}
+		}()
+		// 批次部分入队时未入队的任务已以该错误完成，已入队的任务仍会执行，
+		// 由上面的 goroutine 等批次结束后按结果确认，不能立即 Nak 导致重复执行
+		if err != nil {
+			report(fmt.Errorf("bus: submit: %w", err))
+			if errors.Is(err, fastscheduler.ErrSchedulerStopped) {
+				return err
+			}
+		}
+	}
+}
+
+// decode 解析消息并创建任务
+func decode(data []byte) (BatchMessage, []*fastscheduler.Task, error) {
+	var msg BatchMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return msg, nil, fmt.Errorf("bus: decode message: %w", err)
+	}
+	if len(msg.Tasks) == 0 {
+		return msg, nil, errors.New("bus: message has no tasks")
+	}
+	tasks := make([]*fastscheduler.Task, len(msg.Tasks))
+	for i, st := range msg.Tasks {
+		t, err := st.Task()
+		if err != nil {
+			return msg, nil, fmt.Errorf("bus: task %s: %w", st.ID, err)
+		}
+		tasks[i] = t
+	}
+	return msg, tasks, nil
+}
@@ -0,0 +1,156 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+func init() {
+	fastscheduler.RegisterTaskType("bus.result", func(ctx context.Context, payload []byte) (fastscheduler.TaskResult, error) {
+		if string(payload) == "fail" {
+			return fastscheduler.TaskResult{}, errors.New("failed")
+		}
+		return fastscheduler.TaskResult{HTTPCode: 200, BusinessCode: 0, Data: string(payload)}, nil
+	})
+}
+
+// fakeMessage 记录确认结果的消息
+type fakeMessage struct {
+	data    string
+	settled chan string
+}
+
+func (m *fakeMessage) Data() []byte { return []byte(m.data) }
+func (m *fakeMessage) Ack() error   { m.settled <- "ack"; return nil }
+func (m *fakeMessage) Nak() error   { m.settled <- "nak"; return nil }
+
+func TestConsume(t *testing.T) {
+	scheduler := fastscheduler.NewScheduler(4, 10)
+	defer scheduler.Stop()
+
+	// payload "b2s=" 为 "ok"，"ZmFpbA==" 为 "fail"
+	messages := map[string]*fakeMessage{
+		"success":   {data: `{"tasks":[{"id":"a","type":"bus.result","payload":"ZmFpbA=="},{"id":"b","type":"bus.result","payload":"b2s="}]}`},
+		"failure":   {data: `{"tasks":[{"id":"c","type":"bus.result","payload":"ZmFpbA=="}]}`},
+		"malformed": {data: `{"tasks":[{"id":"d","type":"bus.unknown"}]}`},
+	}
+	queue := make(chan Message, len(messages))
+	for _, m := range messages {
+		m.settled = make(chan string, 1)
+		queue <- m
+	}
+	src := SourceFunc(func(ctx context.Context) (Message, error) {
+		select {
+		case m := <-queue:
+			return m, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+
+	var mu sync.Mutex
+	var published []string
+	var reported []error
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Consume(ctx, scheduler, src, Config{
+			OnBatch: func(msg BatchMessage, batch *fastscheduler.Batch) {
+				mu.Lock()
+				defer mu.Unlock()
+				published = append(published, msg.Tasks[0].ID+":"+batch.Outcome().String())
+			},
+			OnError: func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				reported = append(reported, err)
+			},
+		})
+	}()
+
+	want := map[string]string{"success": "ack", "failure": "nak", "malformed": "ack"}
+	for name, m := range messages {
+		select {
+		case got := <-m.settled:
+			if got != want[name] {
+				t.Errorf("Message %s: expected %s, got %s", name, want[name], got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Message %s was not settled", name)
+		}
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(published) != 2 {
+		t.Errorf("Expected OnBatch for 2 batches, got %v", published)
+	}
+	if len(reported) != 1 || !errors.Is(reported[0], fastscheduler.ErrUnknownTaskType) {
+		t.Errorf("Expected unknown task type to be reported, got %v", reported)
+	}
+}
+
+func TestConsume_PartialBatchSettlesAfterRunningTasks(t *testing.T) {
+	// 队列容量为1且拒绝溢出：第二个任务入队失败，第一个任务已入队，启动后才会执行
+	scheduler := fastscheduler.NewScheduler(1, 1, fastscheduler.WithManualStart(), fastscheduler.WithOverflowPolicy(fastscheduler.OverflowReject))
+	defer scheduler.Stop()
+
+	msg := &fakeMessage{
+		data:    `{"tasks":[{"id":"a","type":"bus.result","payload":"b2s="},{"id":"b","type":"bus.result","payload":"b2s="}]}`,
+		settled: make(chan string, 1),
+	}
+	queue := make(chan Message, 1)
+	queue <- msg
+	src := SourceFunc(func(ctx context.Context) (Message, error) {
+		select {
+		case m := <-queue:
+			return m, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+
+	var mu sync.Mutex
+	var reported []error
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Consume(ctx, scheduler, src, Config{OnError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			reported = append(reported, err)
+		}})
+	}()
+
+	select {
+	case got := <-msg.settled:
+		t.Fatalf("Message settled with %s while task a was still queued", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+	scheduler.Start()
+	select {
+	case got := <-msg.settled:
+		if got != "ack" {
+			t.Errorf("Expected ack after task a succeeded, got %s", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Message was not settled")
+	}
+
+	cancel()
+	<-done
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reported) != 1 || !errors.Is(reported[0], fastscheduler.ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull to be reported, got %v", reported)
+	}
+}
@@ -0,0 +1,17 @@
+module github.com/hawkli-1994/fast-scheduler/bus/kafkabus
+
+go 1.26.0
+
+require (
+	github.com/hawkli-1994/fast-scheduler v0.0.0
+	github.com/twmb/franz-go/pkg/kfake v0.0.0-20260918054303-01f206a7e32c
+)
+
+require (
+	github.com/klauspost/compress v1.20.0 // indirect
+	github.com/pierrec/lz4/v4 v4.1.30 // indirect
+	github.com/twmb/franz-go v1.22.1
+	github.com/twmb/franz-go/pkg/kmsg v1.14.0 // indirect
+)
+
+replace github.com/hawkli-1994/fast-scheduler => ../..
@@ -0,0 +1,240 @@
+// Package kafkabus 基于 franz-go 以消费组的方式从 Kafka 主题获取消息，为 bus.Consume 提供 Source
+//
+// 本包是独立的Go模块，只使用核心调度器时不需要引入 franz-go。
+// 分区由消费组在各实例之间分配和再平衡；只读取已提交事务的消息(read_committed)，
+// 压缩格式由 franz-go 处理(gzip、snappy、lz4、zstd)。
+//
+// Kafka 只能按分区提交 offset：Ack 后可提交的 offset 推进到分区中最早一条尚未确认的消息，
+// 定期自动提交，分区被收回或 Close 时同步提交。Kafka 没有单条消息的否认，
+// Nak 的消息在下一次 Fetch 时重新返回，可提交的 offset 不会越过它
+package kafkabus
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/hawkli-1994/fast-scheduler/bus"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// ErrClosed Source 已关闭
+var ErrClosed = errors.New("kafkabus: source closed")
+
+// Config 消费配置
+type Config struct {
+	// Brokers 用于发现集群的 broker 地址
+	Brokers []string
+	// Topic 消费的主题
+	Topic string
+	// Group 消费组，offset 以该组的名义提交
+	Group string
+	// StartFromLatest 消费组没有已提交的 offset 时从最新的消息开始，默认从最早的消息开始
+	StartFromLatest bool
+	// Options 追加的客户端选项，例如 kgo.DialTLSConfig、kgo.SASL、kgo.AutoCommitInterval
+	Options []kgo.Opt
+}
+
+// Source 从消费组分配到的分区逐条获取消息，实现 bus.Source
+// Fetch 不能并发调用，Ack 和 Nak 可以在任意 goroutine 中调用
+type Source struct {
+	client *kgo.Client
+	topic  string
+
+	mu sync.Mutex
+	// buffered 已拉取尚未返回的消息，retry 被 Nak 后等待重新返回的消息
+	buffered []*kgo.Record
+	retry    []*Message
+	// partitions 当前分配到的分区中已返回过消息的分区
+	partitions map[int32]*partition
+}
+
+// partition 分区内已返回消息的确认状态
+type partition struct {
+	// unsettled 已返回尚未 Ack 的 offset
+	unsettled map[int64]struct{}
+	// next 下一条将返回的 offset
+	next  int64
+	epoch int32
+}
+
+// Message Kafka 中的一条消息，实现 bus.Message
+type Message struct {
+	src    *Source
+	part   *partition
+	record *kgo.Record
+}
+
+var _ bus.Source = (*Source)(nil)
+var _ bus.Message = (*Message)(nil)
+
+// Open 创建消费组客户端并检查集群可以连接
+func Open(ctx context.Context, cfg Config) (*Source, error) {
+	if len(cfg.Brokers) == 0 || cfg.Topic == "" || cfg.Group == "" {
+		return nil, errors.New("kafkabus: Brokers, Topic and Group are required")
+	}
+	s := &Source{topic: cfg.Topic, partitions: make(map[int32]*partition)}
+	reset := kgo.NewOffset().AtStart()
+	if cfg.StartFromLatest {
+		reset = kgo.NewOffset().AtEnd()
+	}
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(cfg.Brokers...),
+		kgo.ConsumeTopics(cfg.Topic),
+		kgo.ConsumerGroup(cfg.Group),
+		kgo.ConsumeResetOffset(reset),
+		kgo.FetchIsolationLevel(kgo.ReadCommitted()),
+		kgo.AutoCommitMarks(),
+		kgo.OnPartitionsRevoked(s.revoked),
+		kgo.OnPartitionsLost(s.lost),
+	}
+	client, err := kgo.NewClient(append(opts, cfg.Options...)...)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Ping(ctx); err != nil {
+		client.Close()
+		return nil, err
+	}
+	s.client = client
+	return s, nil
+}
+
+// Fetch 返回下一条消息：先返回被 Nak 的消息，再返回已拉取的消息，都没有时从 broker 拉取
+func (s *Source) Fetch(ctx context.Context) (bus.Message, error) {
+	for {
+		if msg := s.next(); msg != nil {
+			return msg, nil
+		}
+		fetches := s.client.PollFetches(ctx)
+		if fetches.IsClientClosed() {
+			return nil, ErrClosed
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		s.buffered = append(s.buffered, fetches.Records()...)
+		s.mu.Unlock()
+		// 数据丢失时客户端已重置到有效的 offset，只是通知，继续消费
+		var loss *kgo.ErrDataLoss
+		if err := fetches.Err0(); err != nil && !errors.As(err, &loss) {
+			return nil, err
+		}
+	}
+}
+
+// next 取出一条等待返回的消息，记录为未确认
+func (s *Source) next() *Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.retry) > 0 {
+		msg := s.retry[0]
+		s.retry[0] = nil
+		s.retry = s.retry[1:]
+		return msg
+	}
+	if len(s.buffered) == 0 {
+		return nil
+	}
+	r := s.buffered[0]
+	s.buffered[0] = nil
+	s.buffered = s.buffered[1:]
+	p := s.partitions[r.Partition]
+	if p == nil {
+		p = &partition{unsettled: make(map[int64]struct{})}
+		s.partitions[r.Partition] = p
+	}
+	p.unsettled[r.Offset] = struct{}{}
+	p.next, p.epoch = r.Offset+1, r.LeaderEpoch
+	return &Message{src: s, part: p, record: r}
+}
+
+// settle 记录消息已确认并标记分区可提交的 offset，分区已被收回时忽略
+func (s *Source) settle(m *Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.partitions[m.record.Partition] != m.part {
+		return
+	}
+	p := m.part
+	delete(p.unsettled, m.record.Offset)
+	commit := p.next
+	for offset := range p.unsettled {
+		commit = min(commit, offset)
+	}
+	s.client.MarkCommitOffsets(map[string]map[int32]kgo.EpochOffset{
+		s.topic: {m.record.Partition: {Epoch: p.epoch, Offset: commit}},
+	})
+}
+
+// requeue 将被 Nak 的消息放回，分区已被收回时丢弃(由新的消费者从已提交的 offset 重新读取)
+func (s *Source) requeue(m *Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.partitions[m.record.Partition] == m.part {
+		s.retry = append(s.retry, m)
+	}
+}
+
+// revoked 分区被收回前提交已确认的 offset，并丢弃这些分区的状态
+func (s *Source) revoked(ctx context.Context, client *kgo.Client, revoked map[string][]int32) {
+	client.CommitMarkedOffsets(ctx)
+	s.forget(revoked[s.topic])
+}
+
+// lost 分区丢失时无法提交，只丢弃状态
+func (s *Source) lost(_ context.Context, _ *kgo.Client, lost map[string][]int32) {
+	s.forget(lost[s.topic])
+}
+
+// forget 丢弃分区的确认状态和尚未返回的消息
+func (s *Source) forget(partitions []int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	gone := make(map[int32]bool, len(partitions))
+	for _, p := range partitions {
+		gone[p] = true
+		delete(s.partitions, p)
+	}
+	buffered := s.buffered[:0]
+	for _, r := range s.buffered {
+		if !gone[r.Partition] {
+			buffered = append(buffered, r)
+		}
+	}
+	clear(s.buffered[len(buffered):])
+	s.buffered = buffered
+	retry := s.retry[:0]
+	for _, m := range s.retry {
+		if !gone[m.record.Partition] {
+			retry = append(retry, m)
+		}
+	}
+	clear(s.retry[len(retry):])
+	s.retry = retry
+}
+
+// Close 提交已确认的 offset 并离开消费组
+func (s *Source) Close() error {
+	s.client.Close()
+	return nil
+}
+
+// Data 返回消息内容
+func (m *Message) Data() []byte { return m.record.Value }
+
+// Record 返回原始记录，包括 Key、Headers、分区、offset 和时间戳
+func (m *Message) Record() *kgo.Record { return m.record }
+
+// Ack 确认消息，分区可提交的 offset 在之前的消息都确认后越过该消息
+func (m *Message) Ack() error {
+	m.src.settle(m)
+	return nil
+}
+
+// Nak 使消息在下一次 Fetch 时重新返回
+func (m *Message) Nak() error {
+	m.src.requeue(m)
+	return nil
+}
@@ -0,0 +1,172 @@
+package kafkabus
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+	"github.com/hawkli-1994/fast-scheduler/bus"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+var failures atomic.Int32
+
+func init() {
+	fastscheduler.RegisterTaskType("kafkabus.result", func(ctx context.Context, payload []byte) (fastscheduler.TaskResult, error) {
+		if string(payload) == "fail" {
+			failures.Add(1)
+			return fastscheduler.TaskResult{}, errors.New("failed")
+		}
+		return fastscheduler.TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+	})
+}
+
+// newCluster 启动单分区主题 tasks 的内存 Kafka 集群
+func newCluster(t *testing.T) []string {
+	t.Helper()
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(1, "tasks"))
+	if err != nil {
+		t.Fatalf("NewCluster failed: %v", err)
+	}
+	t.Cleanup(cluster.Close)
+	return cluster.ListenAddrs()
+}
+
+// produce 向主题写入消息
+func produce(t *testing.T, brokers []string, values ...string) {
+	t.Helper()
+	client, err := kgo.NewClient(kgo.SeedBrokers(brokers...), kgo.DefaultProduceTopic("tasks"))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+	for _, v := range values {
+		if err := client.ProduceSync(context.Background(), &kgo.Record{Value: []byte(v)}).FirstErr(); err != nil {
+			t.Fatalf("Produce failed: %v", err)
+		}
+	}
+}
+
+func open(t *testing.T, brokers []string) *Source {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	src, err := Open(ctx, Config{Brokers: brokers, Topic: "tasks", Group: "scheduler", Options: []kgo.Opt{kgo.FetchMaxWait(50 * time.Millisecond)}})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	return src
+}
+
+func fetch(t *testing.T, src *Source) *Message {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	msg, err := src.Fetch(ctx)
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	return msg.(*Message)
+}
+
+func TestSource_Consume(t *testing.T) {
+	failures.Store(0)
+	brokers := newCluster(t)
+	// payload "b2s=" 为 "ok"，"ZmFpbA==" 为 "fail"
+	produce(t, brokers,
+		`{"tasks":[{"id":"a","type":"kafkabus.result","payload":"b2s="}]}`,
+		`{"tasks":[{"id":"b","type":"kafkabus.result","payload":"ZmFpbA=="}]}`,
+	)
+
+	src := open(t, brokers)
+	scheduler := fastscheduler.NewScheduler(2, 10)
+	defer scheduler.Stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- bus.Consume(ctx, scheduler, src, bus.Config{Concurrency: 1})
+	}()
+
+	// 失败的消息被 Nak 后重新返回
+	deadline := time.Now().Add(10 * time.Second)
+	for failures.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if failures.Load() < 2 {
+		t.Fatalf("Expected the failed batch to be redelivered, ran %d times", failures.Load())
+	}
+	src.Close()
+
+	// 成功的消息已提交，重新打开后从失败的消息继续
+	src = open(t, brokers)
+	defer src.Close()
+	if msg := fetch(t, src); msg.Record().Offset != 1 {
+		t.Errorf("Expected to resume at offset 1, got %d", msg.Record().Offset)
+	}
+}
+
+func TestSource_CommitsUpToOldestUnacked(t *testing.T) {
+	brokers := newCluster(t)
+	produce(t, brokers, "a", "b", "c")
+
+	src := open(t, brokers)
+	a, b, c := fetch(t, src), fetch(t, src), fetch(t, src)
+	a.Ack()
+	c.Ack()
+	b.Nak()
+	if again := fetch(t, src); string(again.Data()) != "b" {
+		t.Fatalf("Expected nak'd message to be returned again, got %q", again.Data())
+	}
+	src.Close()
+
+	// b 未确认，提交的 offset 停在 b
+	src = open(t, brokers)
+	defer src.Close()
+	if msg := fetch(t, src); string(msg.Data()) != "b" {
+		t.Errorf("Expected to resume at b, got %q", msg.Data())
+	}
+}
+
+func TestSource_SkipsAbortedTransactions(t *testing.T) {
+	brokers := newCluster(t)
+	client, err := kgo.NewClient(kgo.SeedBrokers(brokers...), kgo.DefaultProduceTopic("tasks"), kgo.TransactionalID("producer"))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+	for _, tx := range []struct {
+		value  string
+		commit kgo.TransactionEndTry
+	}{{"aborted", kgo.TryAbort}, {"committed", kgo.TryCommit}} {
+		if err := client.BeginTransaction(); err != nil {
+			t.Fatalf("BeginTransaction failed: %v", err)
+		}
+		if err := client.ProduceSync(ctx, &kgo.Record{Value: []byte(tx.value)}).FirstErr(); err != nil {
+			t.Fatalf("Produce failed: %v", err)
+		}
+		if err := client.EndTransaction(ctx, tx.commit); err != nil {
+			t.Fatalf("EndTransaction failed: %v", err)
+		}
+	}
+
+	src := open(t, brokers)
+	defer src.Close()
+	if msg := fetch(t, src); string(msg.Data()) != "committed" {
+		t.Errorf("Expected only the committed message, got %q", msg.Data())
+	}
+}
+
+func TestOpen_RequiresConfig(t *testing.T) {
+	if _, err := Open(context.Background(), Config{Brokers: []string{"localhost:9092"}, Topic: "tasks"}); err == nil {
+		t.Error("Expected an error without Group")
+	}
+}
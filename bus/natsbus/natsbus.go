@@ -0,0 +1,82 @@
+// Package natsbus 基于 nats.go 的 JetStream 客户端从拉取消费者获取消息，为 bus.Consume 提供 Source
+//
+// 本包是独立的Go模块，只使用核心调度器时不需要引入 nats.go。
+// 流和持久化拉取消费者需预先创建，例如
+//
+//	nats consumer add TASKS scheduler --pull --ack explicit
+//
+// Fetch 返回的消息就是 jetstream.Msg，Ack/Nak 之外的方法(如 Term、InProgress)可以断言后调用
+package natsbus
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hawkli-1994/fast-scheduler/bus"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// defaultMaxWait 默认单次拉取请求的等待时间
+const defaultMaxWait = 5 * time.Second
+
+// Config 消费配置
+type Config struct {
+	// Stream 流名称
+	Stream string
+	// Consumer 持久化拉取消费者名称
+	Consumer string
+	// MaxWait 单次拉取请求在服务端等待消息的时间，超时后重新请求，默认5秒
+	MaxWait time.Duration
+}
+
+// Source 从拉取消费者逐条获取消息，实现 bus.Source
+type Source struct {
+	consumer jetstream.Consumer
+	maxWait  time.Duration
+}
+
+var _ bus.Source = (*Source)(nil)
+
+// New 在已建立的连接上查找 cfg 指定的拉取消费者
+// 连接由调用方创建和关闭，断线重连由 nats.go 处理
+func New(ctx context.Context, nc *nats.Conn, cfg Config) (*Source, error) {
+	if cfg.Stream == "" || cfg.Consumer == "" {
+		return nil, errors.New("natsbus: Stream and Consumer are required")
+	}
+	if cfg.MaxWait <= 0 {
+		cfg.MaxWait = defaultMaxWait
+	}
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return nil, err
+	}
+	consumer, err := js.Consumer(ctx, cfg.Stream, cfg.Consumer)
+	if err != nil {
+		return nil, err
+	}
+	return &Source{consumer: consumer, maxWait: cfg.MaxWait}, nil
+}
+
+// Fetch 拉取一条消息，拉取请求到期时重新请求，直到收到消息或 ctx 结束
+func (s *Source) Fetch(ctx context.Context) (bus.Message, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		pullCtx, cancel := context.WithTimeout(ctx, s.maxWait)
+		msg, err := s.consumer.Next(jetstream.FetchContext(pullCtx))
+		cancel()
+		switch {
+		case err == nil:
+			return msg, nil
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case errors.Is(err, nats.ErrTimeout), errors.Is(err, jetstream.ErrNoMessages), errors.Is(err, context.DeadlineExceeded):
+			continue
+		default:
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package natsbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+	"github.com/hawkli-1994/fast-scheduler/bus"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+func init() {
+	fastscheduler.RegisterTaskType("natsbus.result", func(ctx context.Context, payload []byte) (fastscheduler.TaskResult, error) {
+		if string(payload) == "fail" {
+			return fastscheduler.TaskResult{}, errors.New("failed")
+		}
+		return fastscheduler.TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+	})
+}
+
+// startJetStream 启动内嵌的 JetStream 服务器，创建流 TASKS 和拉取消费者 scheduler
+func startJetStream(t *testing.T) (*nats.Conn, jetstream.JetStream) {
+	t.Helper()
+	srv, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, JetStream: true, StoreDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	go srv.Start()
+	t.Cleanup(srv.Shutdown)
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server not ready")
+	}
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(nc.Close)
+	js, err := jetstream.New(nc)
+	if err != nil {
+		t.Fatalf("jetstream.New failed: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := js.CreateStream(ctx, jetstream.StreamConfig{Name: "TASKS", Subjects: []string{"tasks.>"}}); err != nil {
+		t.Fatalf("CreateStream failed: %v", err)
+	}
+	if _, err := js.CreateOrUpdateConsumer(ctx, "TASKS", jetstream.ConsumerConfig{Durable: "scheduler", AckPolicy: jetstream.AckExplicitPolicy}); err != nil {
+		t.Fatalf("CreateConsumer failed: %v", err)
+	}
+	return nc, js
+}
+
+func TestSource_Consume(t *testing.T) {
+	nc, js := startJetStream(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// payload "b2s=" 为 "ok"，"ZmFpbA==" 为 "fail"
+	for _, data := range []string{
+		`{"tasks":[{"id":"a","type":"natsbus.result","payload":"b2s="}]}`,
+		`{"tasks":[{"id":"b","type":"natsbus.result","payload":"ZmFpbA=="}]}`,
+	} {
+		if _, err := js.Publish(ctx, "tasks.submit", []byte(data)); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+
+	src, err := New(ctx, nc, Config{Stream: "TASKS", Consumer: "scheduler", MaxWait: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	scheduler := fastscheduler.NewScheduler(2, 10)
+	defer scheduler.Stop()
+	done := make(chan error, 1)
+	go func() {
+		done <- bus.Consume(ctx, scheduler, src, bus.Config{Concurrency: 1})
+	}()
+
+	// 成功的消息被确认，失败的消息被 Nak 后重新投递
+	consumer, err := js.Consumer(ctx, "TASKS", "scheduler")
+	if err != nil {
+		t.Fatalf("Consumer failed: %v", err)
+	}
+	var info *jetstream.ConsumerInfo
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if info, err = consumer.Info(ctx); err != nil {
+			t.Fatalf("Info failed: %v", err)
+		}
+		if info.AckFloor.Stream == 1 && info.NumRedelivered > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if info.AckFloor.Stream != 1 || info.NumRedelivered == 0 {
+		t.Errorf("Expected first message acked and second redelivered, got ack floor %d, redelivered %d", info.AckFloor.Stream, info.NumRedelivered)
+	}
+}
+
+func TestSource_RetriesExpiredPullRequests(t *testing.T) {
+	nc, js := startJetStream(t)
+	src, err := New(context.Background(), nc, Config{Stream: "TASKS", Consumer: "scheduler", MaxWait: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	// 多个拉取请求到期后消息才到达
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		js.Publish(context.Background(), "tasks.late", []byte("late"))
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	msg, err := src.Fetch(ctx)
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	jm, ok := msg.(jetstream.Msg)
+	if !ok || string(jm.Data()) != "late" || jm.Subject() != "tasks.late" {
+		t.Fatalf("Unexpected message %T %q", msg, msg.Data())
+	}
+	if err := jm.Term(); err != nil {
+		t.Fatalf("Term failed: %v", err)
+	}
+
+	cancel()
+	if _, err := src.Fetch(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled after cancel, got %v", err)
+	}
+}
+
+func TestNew_RequiresConsumer(t *testing.T) {
+	nc, _ := startJetStream(t)
+	if _, err := New(context.Background(), nc, Config{Stream: "TASKS"}); err == nil {
+		t.Error("Expected an error without Consumer")
+	}
+	if _, err := New(context.Background(), nc, Config{Stream: "TASKS", Consumer: "missing"}); !errors.Is(err, jetstream.ErrConsumerNotFound) {
+		t.Errorf("Expected ErrConsumerNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// callbackFor 按结果选择任务的回调：成功、取消(批次或任务被取消、调度器停止)或失败
+func callbackFor(task *Task, result TaskResult) func(TaskResult) {
+	switch {
+	case isSuccess(result):
+		return task.OnSuccess
+	case isCancelled(result):
+		return task.OnCancel
+	default:
+		return task.OnFailure
+	}
+}
+
+// isCancelled 判断结果是否因批次或任务被取消、调度器停止而结束
+func isCancelled(result TaskResult) bool {
+	return errors.Is(result.Err, context.Canceled) || errors.Is(result.Err, ErrSchedulerStopped)
+}
+
+// runCallbacks 在独立的goroutine中调用任务的回调，回调返回前批次的 Wait 不会返回
+func (s *Scheduler) runCallbacks(task *Task, result TaskResult) {
+	fn := callbackFor(task, result)
+	if fn == nil {
+		return
+	}
+	g := task.group
+	// 任务自身的计数尚未释放，此时增加计数是安全的
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer func() {
+			if v := recover(); v != nil {
+				s.logTask(slog.LevelError, "task callback panicked", task, slog.Any("panic", v))
+			}
+		}()
+		fn(result)
+	}()
+}
@@ -0,0 +1,66 @@
+package fastscheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestTask_Callbacks(t *testing.T) {
+	scheduler := NewScheduler(1, 10)
+	defer scheduler.Stop()
+
+	var (
+		mu    sync.Mutex
+		calls = make(map[string]string)
+	)
+	record := func(kind string) func(TaskResult) {
+		return func(r TaskResult) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls[r.TaskID] = kind
+		}
+	}
+	withCallbacks := func(task *Task) *Task {
+		task.OnSuccess = record("success")
+		task.OnFailure = record("failure")
+		task.OnCancel = record("cancel")
+		return task
+	}
+
+	// worker池大小为1，任务依次执行：失败、成功，成功后剩余任务被取消
+	batch, err := scheduler.SubmitBatch([]*Task{
+		withCallbacks(&Task{
+			ID: "fail",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 500}, nil
+			},
+		}),
+		withCallbacks(&Task{
+			ID: "ok",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 200}, nil
+			},
+		}),
+		withCallbacks(&Task{
+			ID: "late",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				<-ctx.Done()
+				return TaskResult{}, ctx.Err()
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	batch.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := map[string]string{"fail": "failure", "ok": "success", "late": "cancel"}
+	for id, kind := range want {
+		if calls[id] != kind {
+			t.Errorf("Task %s callback = %q, want %q", id, calls[id], kind)
+		}
+	}
+}
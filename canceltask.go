@@ -0,0 +1,66 @@
+package fastscheduler
+
+import (
+	"context"
+	"fmt"
+)
+
+// CancelTask 取消批次中指定ID的任务，不影响同批次的其他任务
+// 执行中的任务通过其上下文取消，排队或等待重试的任务在被调度时以 context.Canceled 完成而不再执行；
+// 批次中有多个任务使用该ID时全部取消。批次不存在、已完成或其中没有该任务时返回 ErrTaskNotFound
+func (s *Scheduler) CancelTask(batchID, taskID string) error {
+	b := s.Batch(batchID)
+	if b == nil {
+		return fmt.Errorf("%w: batch %s", ErrTaskNotFound, batchID)
+	}
+	g := b.group
+	g.taskMu.Lock()
+	defer g.taskMu.Unlock()
+	found := false
+	for _, t := range g.tasks {
+		if t.ID != taskID {
+			continue
+		}
+		found = true
+		t.cancelled = true
+		if t.taskCancel != nil {
+			t.taskCancel()
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w: task %s in batch %s", ErrTaskNotFound, taskID, batchID)
+	}
+	return nil
+}
+
+// track 登记任务副本，供 CancelTask 按ID查找
+func (g *taskGroup) track(t *Task) {
+	g.taskMu.Lock()
+	g.tasks = append(g.tasks, t)
+	g.taskMu.Unlock()
+}
+
+// taskContext 为一次执行创建任务自己的上下文，任务已被取消时返回 false
+// 返回的函数在执行结束时调用，释放上下文
+func (g *taskGroup) taskContext(t *Task) (context.Context, func(), bool) {
+	g.taskMu.Lock()
+	defer g.taskMu.Unlock()
+	if t.cancelled {
+		return nil, nil, false
+	}
+	ctx, cancel := context.WithCancel(t.runCtx())
+	t.taskCancel = cancel
+	return ctx, func() {
+		g.taskMu.Lock()
+		t.taskCancel = nil
+		g.taskMu.Unlock()
+		cancel()
+	}, true
+}
+
+// taskCancelled 返回任务是否已通过 CancelTask 取消
+func (g *taskGroup) taskCancelled(t *Task) bool {
+	g.taskMu.Lock()
+	defer g.taskMu.Unlock()
+	return t.cancelled
+}
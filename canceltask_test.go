@@ -0,0 +1,78 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestScheduler_CancelTask(t *testing.T) {
+	scheduler := NewScheduler(1, 10)
+	defer scheduler.Stop()
+
+	started := make(chan struct{})
+	var queuedRan, otherRan atomic.Bool
+	tasks := []*Task{
+		{
+			ID: "slow",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				close(started)
+				<-ctx.Done()
+				return TaskResult{}, ctx.Err()
+			},
+		},
+		{
+			ID: "queued",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				queuedRan.Store(true)
+				return TaskResult{HTTPCode: 500}, nil
+			},
+		},
+		{
+			ID: "other",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				otherRan.Store(true)
+				return TaskResult{HTTPCode: 500}, nil
+			},
+		},
+	}
+
+	batch, err := scheduler.SubmitBatch(tasks, WithBatchID("report"))
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	<-started
+
+	if err := scheduler.CancelTask("report", "missing"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound for unknown task, got %v", err)
+	}
+	// 先取消排队中的任务，再取消执行中的任务
+	if err := scheduler.CancelTask("report", "queued"); err != nil {
+		t.Fatalf("CancelTask failed: %v", err)
+	}
+	if err := scheduler.CancelTask("report", "slow"); err != nil {
+		t.Fatalf("CancelTask failed: %v", err)
+	}
+	batch.Wait()
+
+	results := make(map[string]TaskResult)
+	for r := range batch.ResultsChan() {
+		results[r.TaskID] = r
+	}
+	for _, id := range []string{"slow", "queued"} {
+		if !errors.Is(results[id].Err, context.Canceled) {
+			t.Errorf("Expected task %s to be cancelled, got %v", id, results[id].Err)
+		}
+	}
+	if queuedRan.Load() {
+		t.Error("Cancelled queued task was executed")
+	}
+	if !otherRan.Load() || results["other"].Err != nil {
+		t.Errorf("Expected other task to run unaffected, got %+v", results["other"])
+	}
+
+	if err := scheduler.CancelTask("report", "slow"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound for finished batch, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+//go:build linux
+
+package fastscheduler
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// cgroupCPUQuota 读取本进程所在cgroup的CPU配额(以CPU核数表示)，未设置限制时返回false
+func cgroupCPUQuota() (float64, bool) {
+	return cgroupCPUQuotaAt("/")
+}
+
+// cgroupCPUQuotaAt 以 root 为文件系统根读取配额，便于测试
+// 先由 /proc/self/cgroup 和 /proc/self/mountinfo 找到本进程的cgroup目录，
+// 从该目录向上到挂载点取各级配额的最小值(上级的限制同样生效)；
+// 无法解析时退回 /sys/fs/cgroup 下的固定路径
+func cgroupCPUQuotaAt(root string) (float64, bool) {
+	if cg, ok := resolveCgroup(root); ok {
+		var limit float64
+		found := false
+		for dir := cg.dir; strings.HasPrefix(dir, cg.mount); dir = filepath.Dir(dir) {
+			if quota, ok := readCPUQuota(dir, cg.v2); ok && (!found || quota < limit) {
+				limit, found = quota, true
+			}
+			if dir == cg.mount {
+				break
+			}
+		}
+		return limit, found
+	}
+
+	if quota, ok := readCPUQuota(filepath.Join(root, "sys/fs/cgroup"), true); ok {
+		return quota, true
+	}
+	return readCPUQuota(filepath.Join(root, "sys/fs/cgroup/cpu"), false)
+}
+
+// cgroupLocation 本进程的cgroup目录及其所在层级的挂载点
+type cgroupLocation struct {
+	dir   string
+	mount string
+	v2    bool
+}
+
+// resolveCgroup 解析本进程CPU控制器所在的cgroup目录
+// 混合模式下CPU控制器在v1层级中，优先使用v1；否则使用v2统一层级(0::/path)
+func resolveCgroup(root string) (cgroupLocation, bool) {
+	data, err := os.ReadFile(filepath.Join(root, "proc/self/cgroup"))
+	if err != nil {
+		return cgroupLocation{}, false
+	}
+	var v1Path, v2Path string
+	hasV1, hasV2 := false, false
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		// hierarchy-ID:controller-list:cgroup-path
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		switch {
+		case parts[0] == "0" && parts[1] == "":
+			v2Path, hasV2 = parts[2], true
+		case hasController(parts[1], "cpu"):
+			v1Path, hasV1 = parts[2], true
+		}
+	}
+	if !hasV1 && !hasV2 {
+		return cgroupLocation{}, false
+	}
+
+	f, err := os.Open(filepath.Join(root, "proc/self/mountinfo"))
+	if err != nil {
+		return cgroupLocation{}, false
+	}
+	defer f.Close()
+	var v2Mount, v2Root string
+	foundV2 := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// id parent major:minor root mountpoint options [optional...] - fstype source superoptions
+		fields := strings.Fields(scanner.Text())
+		sep := -1
+		for i, field := range fields {
+			if field == "-" {
+				sep = i
+				break
+			}
+		}
+		if sep < 5 || len(fields) < sep+4 {
+			continue
+		}
+		mountRoot, mountPoint := unescapeMount(fields[3]), unescapeMount(fields[4])
+		switch fstype := fields[sep+1]; {
+		case hasV1 && fstype == "cgroup" && hasController(fields[sep+3], "cpu"):
+			return cgroupLocation{dir: cgroupDir(root, mountPoint, mountRoot, v1Path), mount: filepath.Join(root, mountPoint)}, true
+		case hasV2 && fstype == "cgroup2" && !foundV2:
+			v2Mount, v2Root, foundV2 = mountPoint, mountRoot, true
+		}
+	}
+	if foundV2 {
+		return cgroupLocation{dir: cgroupDir(root, v2Mount, v2Root, v2Path), mount: filepath.Join(root, v2Mount), v2: true}, true
+	}
+	return cgroupLocation{}, false
+}
+
+// cgroupDir 将 /proc/self/cgroup 中的路径映射到挂载点下的目录
+// 挂载的是层级中的子树(如容器内挂载的是自己的cgroup)时去掉子树的前缀
+func cgroupDir(root, mountPoint, mountRoot, path string) string {
+	if mountRoot != "/" {
+		if rel, ok := strings.CutPrefix(path, mountRoot); ok {
+			path = rel
+		}
+	}
+	return filepath.Join(root, mountPoint, path)
+}
+
+// hasController 判断逗号分隔的列表中是否包含控制器 name
+func hasController(list, name string) bool {
+	for _, c := range strings.Split(list, ",") {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// unescapeMount 还原 mountinfo 中以八进制转义的空白和反斜杠
+func unescapeMount(s string) string {
+	return strings.NewReplacer(`\040`, " ", `\011`, "\t", `\012`, "\n", `\134`, `\`).Replace(s)
+}
+
+// readCPUQuota 读取cgroup目录中的CPU配额
+func readCPUQuota(dir string, v2 bool) (float64, bool) {
+	if v2 {
+		// "max 100000" 或 "200000 100000"
+		data, err := os.ReadFile(filepath.Join(dir, "cpu.max"))
+		if err != nil {
+			return 0, false
+		}
+		fields := strings.Fields(string(data))
+		if len(fields) == 2 && fields[0] != "max" {
+			return parseQuota(fields[0], fields[1])
+		}
+		return 0, false
+	}
+
+	quota, err := os.ReadFile(filepath.Join(dir, "cpu.cfs_quota_us"))
+	if err != nil {
+		return 0, false
+	}
+	period, err := os.ReadFile(filepath.Join(dir, "cpu.cfs_period_us"))
+	if err != nil {
+		return 0, false
+	}
+	return parseQuota(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+}
+
+// parseQuota 将配额和周期转换为CPU核数
+func parseQuota(quota, period string) (float64, bool) {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0, false
+	}
+	return q / p, true
+}
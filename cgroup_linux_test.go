@@ -0,0 +1,94 @@
+//go:build linux
+
+package fastscheduler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFiles 在 root 下创建文件，键为相对路径
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+}
+
+func TestCgroupCPUQuota(t *testing.T) {
+	const v2Mount = "30 23 0:26 / /sys/fs/cgroup rw,nosuid,nodev,noexec,relatime shared:4 - cgroup2 cgroup2 rw,nsdelegate\n"
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  float64
+		ok    bool
+	}{
+		{
+			name: "v2 cgroup namespace",
+			files: map[string]string{
+				"proc/self/cgroup":      "0::/\n",
+				"proc/self/mountinfo":   v2Mount,
+				"sys/fs/cgroup/cpu.max": "150000 100000\n",
+			},
+			want: 1.5, ok: true,
+		},
+		{
+			// 未使用cgroup命名空间时进程位于层级深处，限制设置在上级
+			name: "v2 nested with parent limit",
+			files: map[string]string{
+				"proc/self/cgroup":                        "0::/kubepods/pod1/ctr\n",
+				"proc/self/mountinfo":                     v2Mount,
+				"sys/fs/cgroup/cpu.max":                   "max 100000\n",
+				"sys/fs/cgroup/kubepods/cpu.max":          "max 100000\n",
+				"sys/fs/cgroup/kubepods/pod1/cpu.max":     "200000 100000\n",
+				"sys/fs/cgroup/kubepods/pod1/ctr/cpu.max": "max 100000\n",
+				"sys/fs/cgroup/kubepods/other/cpu.max":    "50000 100000\n",
+			},
+			want: 2, ok: true,
+		},
+		{
+			// 混合模式：CPU控制器在v1层级，挂载的是容器自己的子树
+			name: "v1 hybrid",
+			files: map[string]string{
+				"proc/self/cgroup": "4:cpu,cpuacct:/docker/abc\n0::/docker/abc\n",
+				"proc/self/mountinfo": v2Mount +
+					"35 30 0:31 /docker/abc /sys/fs/cgroup/cpu,cpuacct rw,nosuid - cgroup cgroup rw,cpu,cpuacct\n",
+				"sys/fs/cgroup/cpu,cpuacct/cpu.cfs_quota_us":  "50000\n",
+				"sys/fs/cgroup/cpu,cpuacct/cpu.cfs_period_us": "100000\n",
+			},
+			want: 0.5, ok: true,
+		},
+		{
+			name: "unlimited",
+			files: map[string]string{
+				"proc/self/cgroup":      "0::/\n",
+				"proc/self/mountinfo":   v2Mount,
+				"sys/fs/cgroup/cpu.max": "max 100000\n",
+			},
+		},
+		{
+			name: "fallback without proc",
+			files: map[string]string{
+				"sys/fs/cgroup/cpu.max": "300000 100000\n",
+			},
+			want: 3, ok: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFiles(t, root, tt.files)
+			got, ok := cgroupCPUQuotaAt(root)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("Expected %v (%v), got %v (%v)", tt.want, tt.ok, got, ok)
+			}
+		})
+	}
+}
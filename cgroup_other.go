@@ -0,0 +1,8 @@
+//go:build !linux
+
+package fastscheduler
+
+// cgroupCPUQuota 非Linux平台没有cgroup配额
+func cgroupCPUQuota() (float64, bool) {
+	return 0, false
+}
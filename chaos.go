@@ -0,0 +1,104 @@
+package fastscheduler
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// ChaosConfig 故障注入配置，各概率取值 0~1，每次执行(包括重试和对冲尝试)独立抽样
+type ChaosConfig struct {
+	// DelayProbability 执行前注入延迟的概率，延迟在 [MinDelay, MaxDelay] 间均匀分布
+	DelayProbability float64
+	MinDelay         time.Duration
+	MaxDelay         time.Duration
+
+	// ErrorProbability 不执行任务、直接以 Error 失败的概率
+	ErrorProbability float64
+	// Error 注入的错误，nil 时为 ErrChaosInjected
+	Error error
+
+	// DropProbability 任务执行后丢弃其结果、以 ErrChaosDropped 失败的概率，模拟响应丢失
+	DropProbability float64
+
+	// Seed 随机数种子，相同种子产生相同的注入序列；0 表示随机
+	Seed uint64
+}
+
+// WithChaos 按配置随机延迟任务、注入错误或丢弃结果，用于验证重试、对冲和熔断配置在故障下的表现
+// 注入发生在 Executor 之外，注入的失败计入重试、熔断和统计，与真实失败相同
+func WithChaos(cfg ChaosConfig) Option {
+	return func(s *Scheduler) {
+		if cfg.MaxDelay < cfg.MinDelay {
+			cfg.MaxDelay = cfg.MinDelay
+		}
+		if cfg.Error == nil {
+			cfg.Error = ErrChaosInjected
+		}
+		seed := cfg.Seed
+		if seed == 0 {
+			seed = rand.Uint64()
+		}
+		s.chaos = &chaos{cfg: cfg, rng: rand.New(rand.NewPCG(seed, seed))}
+	}
+}
+
+// chaos 故障注入器
+type chaos struct {
+	cfg ChaosConfig
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// chaosPlan 一次执行的注入决定
+type chaosPlan struct {
+	delay time.Duration
+	fail  bool
+	drop  bool
+}
+
+// plan 抽样本次执行注入的故障
+func (c *chaos) plan() chaosPlan {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var p chaosPlan
+	if c.rng.Float64() < c.cfg.DelayProbability {
+		p.delay = c.cfg.MinDelay
+		if span := c.cfg.MaxDelay - c.cfg.MinDelay; span > 0 {
+			p.delay += time.Duration(c.rng.Int64N(int64(span) + 1))
+		}
+	}
+	p.fail = c.rng.Float64() < c.cfg.ErrorProbability
+	p.drop = c.rng.Float64() < c.cfg.DropProbability
+	return p
+}
+
+// withChaos 按注入决定包装一次执行
+func (s *Scheduler) withChaos(ctx context.Context, t *Task, run func() (TaskResult, error)) (TaskResult, error) {
+	if s.chaos == nil {
+		return run()
+	}
+	p := s.chaos.plan()
+	if p.delay > 0 {
+		s.logTask(slog.LevelDebug, "chaos delay", t, slog.Duration("delay", p.delay))
+		timer := s.clock.NewTimer(p.delay)
+		select {
+		case <-timer.C():
+		case <-ctx.Done():
+			timer.Stop()
+			return TaskResult{}, ctx.Err()
+		}
+	}
+	if p.fail {
+		s.logTask(slog.LevelDebug, "chaos error", t)
+		return TaskResult{HTTPCode: 503}, s.chaos.cfg.Error
+	}
+	result, err := run()
+	if p.drop {
+		s.logTask(slog.LevelDebug, "chaos dropped result", t)
+		return TaskResult{HTTPCode: 504}, ErrChaosDropped
+	}
+	return result, err
+}
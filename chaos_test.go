@@ -0,0 +1,66 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_Chaos(t *testing.T) {
+	var executed atomic.Int32
+	ok := func(ctx context.Context) (TaskResult, error) {
+		executed.Add(1)
+		return TaskResult{HTTPCode: 200}, nil
+	}
+
+	tests := []struct {
+		name     string
+		cfg      ChaosConfig
+		err      error
+		executed int32
+	}{
+		{"Error", ChaosConfig{ErrorProbability: 1}, ErrChaosInjected, 0},
+		{"Drop", ChaosConfig{DropProbability: 1}, ErrChaosDropped, 3},
+		{"Delay", ChaosConfig{DelayProbability: 1, MinDelay: 20 * time.Millisecond, MaxDelay: 20 * time.Millisecond}, nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executed.Store(0)
+			scheduler := NewScheduler(1, 10, WithChaos(tt.cfg))
+			defer scheduler.Stop()
+
+			batch, err := scheduler.SubmitBatch([]*Task{{ID: "a", Execute: ok, Retry: &RetryPolicy{MaxAttempts: 3}}})
+			if err != nil {
+				t.Fatalf("Submit failed: %v", err)
+			}
+			batch.Wait()
+			result := <-batch.ResultsChan()
+			if !errors.Is(result.Err, tt.err) {
+				t.Errorf("Expected error %v, got %v", tt.err, result.Err)
+			}
+			if got := executed.Load(); got != tt.executed {
+				t.Errorf("Task executed %d times, want %d", got, tt.executed)
+			}
+			if tt.cfg.MinDelay > 0 && result.Metrics.Duration < tt.cfg.MinDelay {
+				t.Errorf("Duration = %s, want at least %s", result.Metrics.Duration, tt.cfg.MinDelay)
+			}
+		})
+	}
+}
+
+func TestChaos_Seed(t *testing.T) {
+	cfg := ChaosConfig{DelayProbability: 0.5, MaxDelay: time.Second, ErrorProbability: 0.3, DropProbability: 0.2, Seed: 42}
+	newChaos := func() *chaos {
+		s := &Scheduler{}
+		WithChaos(cfg)(s)
+		return s.chaos
+	}
+	a, b := newChaos(), newChaos()
+	for i := 0; i < 100; i++ {
+		if pa, pb := a.plan(), b.plan(); pa != pb {
+			t.Fatalf("Plan %d differs with the same seed: %+v vs %+v", i, pa, pb)
+		}
+	}
+}
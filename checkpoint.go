@@ -0,0 +1,58 @@
+package fastscheduler
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// Checkpoint 供长时间运行的 Execute 在循环中调用，检查任务是否已被取消
+// 已取消时返回 ctx.Err()，否则记录一次进度并让出处理器后返回nil，开销很小。
+// 在调度器执行的任务中调用时，Running() 返回的 TaskInfo 中 Checkpoints 和
+// LastCheckpoint 反映任务的进展，可用于区分运行缓慢和卡住的任务
+//
+//	for _, item := range items {
+//	    if err := fastscheduler.Checkpoint(ctx); err != nil {
+//	        return fastscheduler.TaskResult{}, err
+//	    }
+//	    process(item)
+//	}
+func Checkpoint(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	if p, _ := ctx.Value(progressKey{}).(*taskProgress); p != nil {
+		p.record()
+	}
+	runtime.Gosched()
+	return nil
+}
+
+// progressKey 任务上下文中执行进度的键
+type progressKey struct{}
+
+// taskProgress 单次任务执行的检查点记录
+type taskProgress struct {
+	now   func() time.Time
+	count atomic.Int64
+	// last 最近一次检查点的时间(UnixNano)，0表示尚未记录
+	last atomic.Int64
+}
+
+// record 记录一次检查点
+func (p *taskProgress) record() {
+	p.last.Store(p.now().UnixNano())
+	p.count.Add(1)
+}
+
+// snapshot 返回检查点次数和最近一次的时间
+func (p *taskProgress) snapshot() (int64, time.Time) {
+	n := p.count.Load()
+	if n == 0 {
+		return 0, time.Time{}
+	}
+	return n, time.Unix(0, p.last.Load())
+}
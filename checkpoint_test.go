@@ -0,0 +1,62 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckpoint(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := Checkpoint(ctx); err != nil {
+		t.Errorf("Expected nil before cancellation, got %v", err)
+	}
+	cancel()
+	if err := Checkpoint(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestCheckpoint_RecordsProgressOnRunningTask(t *testing.T) {
+	scheduler := NewScheduler(1, 10)
+	defer scheduler.Stop()
+
+	step, release := make(chan struct{}), make(chan struct{})
+	task := &Task{ID: "long", Execute: func(ctx context.Context) (TaskResult, error) {
+		for i := 0; i < 3; i++ {
+			if err := Checkpoint(ctx); err != nil {
+				return TaskResult{}, err
+			}
+			step <- struct{}{}
+		}
+		<-release
+		return TaskResult{HTTPCode: 200}, nil
+	}}
+	future, err := scheduler.Submit(task)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		<-step
+	}
+
+	running := scheduler.Running()
+	if len(running) != 1 {
+		t.Fatalf("Expected 1 running task, got %d", len(running))
+	}
+	info := running[0]
+	if info.Checkpoints != 3 {
+		t.Errorf("Expected 3 checkpoints, got %d", info.Checkpoints)
+	}
+	if info.LastCheckpoint.IsZero() || info.LastCheckpoint.Before(info.Started) {
+		t.Errorf("Unexpected last checkpoint %v (started %v)", info.LastCheckpoint, info.Started)
+	}
+
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := future.Wait(ctx); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+}
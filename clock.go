@@ -0,0 +1,133 @@
+package fastscheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock 调度器的时间来源
+// 执行超时、重试等待、错开启动、排队超时、完成时限、对冲延迟、熔断冷却、限速、
+// 结果投递超时、慢任务检测和指标上报都通过 Clock 计时；
+// 测试中可以用 fastschedulertest.FakeClock 手动推进时间，代替真实的 sleep
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer 对应 time.Timer
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker 对应 time.Ticker
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// WithClock 设置调度器的时间来源，默认使用系统时间
+func WithClock(c Clock) Option {
+	return func(s *Scheduler) {
+		s.clock = c
+	}
+}
+
+// systemClock 使用系统时间
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTimer(d time.Duration) Timer { return systemTimer{time.NewTimer(d)} }
+
+func (systemClock) NewTicker(d time.Duration) Ticker { return systemTicker{time.NewTicker(d)} }
+
+type systemTimer struct{ t *time.Timer }
+
+func (t systemTimer) C() <-chan time.Time        { return t.t.C }
+func (t systemTimer) Stop() bool                 { return t.t.Stop() }
+func (t systemTimer) Reset(d time.Duration) bool { return t.t.Reset(d) }
+
+type systemTicker struct{ t *time.Ticker }
+
+func (t systemTicker) C() <-chan time.Time { return t.t.C }
+func (t systemTicker) Stop()               { t.t.Stop() }
+
+// now 返回调度器时钟的当前时间
+func (s *Scheduler) now() time.Time {
+	return s.clock.Now()
+}
+
+// since 返回调度器时钟上自 t 起经过的时间
+func (s *Scheduler) since(t time.Time) time.Duration {
+	return s.clock.Now().Sub(t)
+}
+
+// contextWithTimeout 按调度器时钟派生 d 后超时的上下文
+// 系统时钟直接使用 context.WithTimeout
+func (s *Scheduler) contextWithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := s.clock.(systemClock); ok {
+		return context.WithTimeout(ctx, d)
+	}
+	c := &clockContext{
+		Context:  ctx,
+		deadline: s.clock.Now().Add(d),
+		done:     make(chan struct{}),
+	}
+	timer := s.clock.NewTimer(d)
+	stop := make(chan struct{})
+	go func() {
+		defer timer.Stop()
+		select {
+		case <-timer.C():
+			c.cancel(context.DeadlineExceeded)
+		case <-ctx.Done():
+			c.cancel(ctx.Err())
+		case <-stop:
+		}
+	}()
+	var once sync.Once
+	return c, func() {
+		once.Do(func() { close(stop) })
+		c.cancel(context.Canceled)
+	}
+}
+
+// clockContext 按自定义时钟超时的上下文，语义与 context.WithTimeout 相同
+type clockContext struct {
+	context.Context
+	deadline time.Time
+	done     chan struct{}
+	mu       sync.Mutex
+	err      error
+}
+
+func (c *clockContext) Deadline() (time.Time, bool) {
+	if d, ok := c.Context.Deadline(); ok && d.Before(c.deadline) {
+		return d, true
+	}
+	return c.deadline, true
+}
+
+func (c *clockContext) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *clockContext) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// cancel 以 err 结束上下文，只有第一次调用生效
+func (c *clockContext) cancel(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		c.err = err
+		close(c.done)
+	}
+}
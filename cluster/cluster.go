@@ -0,0 +1,189 @@
+// Package cluster 让协调者调度器通过 gRPC 把已注册类型的任务分派给一组worker进程执行
+//
+// worker 进程通过 RegisterWorker 在 grpc.Server 上注册服务 fastscheduler.cluster.v1.Worker(定义见 workerpb/worker.proto)，
+// 协调者通过 WithExecutor(NewExecutor(...)) 接入。任务以 fastscheduler.MarshalTask 编码放入 ExecuteRequest，
+// 结果按 Codec 编码后以 ExecuteResponse 返回。本包基于 google.golang.org/grpc，是独立的Go模块，
+// 传输方式(TLS 或明文)由 grpc.Server 的选项和传给 NewExecutor 的 grpc.DialOption 决定。
+// 批次中的任务成功后，协调者取消同组其他任务的调用，worker 端任务的 ctx 随调用取消
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+	"github.com/hawkli-1994/fast-scheduler/cluster/workerpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MaxTaskSize worker 接受的最大任务编码长度，用作 grpc.MaxRecvMsgSize
+const MaxTaskSize = 10 << 20
+
+// maxResultSize 协调者接受的最大结果编码长度
+const maxResultSize = 64 << 20
+
+// RegisterWorker 在 s 上注册 Worker 服务，任务类型需要在worker进程中注册
+// codec 用于编码结果数据，为nil时使用 JSONCodec，需要与协调者一致
+func RegisterWorker(s grpc.ServiceRegistrar, codec fastscheduler.Codec) {
+	if codec == nil {
+		codec = fastscheduler.JSONCodec
+	}
+	workerpb.RegisterWorkerServer(s, &worker{codec: codec})
+}
+
+// worker Worker gRPC 服务的实现
+type worker struct {
+	workerpb.UnimplementedWorkerServer
+	codec fastscheduler.Codec
+}
+
+// Execute 执行一个任务，协调者取消调用时 ctx 被取消，任务随之中止
+func (wk *worker) Execute(ctx context.Context, req *workerpb.ExecuteRequest) (*workerpb.ExecuteResponse, error) {
+	task, err := fastscheduler.UnmarshalTask(req.GetTask())
+	if err != nil {
+		code := codes.InvalidArgument
+		if errors.Is(err, fastscheduler.ErrUnknownTaskType) {
+			code = codes.Unimplemented
+		}
+		return nil, status.Error(code, err.Error())
+	}
+
+	result, err := task.Execute(ctx)
+	if err != nil {
+		result.Err = err
+	}
+	result.TaskID = task.ID
+	wire, err := fastscheduler.EncodeResult(wk.codec, result)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return toResponse(wire), nil
+}
+
+// Executor 将可序列化任务轮流分派给worker，实现 fastscheduler.Executor
+// 不是由 SerializableTask 创建的任务(闭包)在协调者本地执行
+type Executor struct {
+	targets []string
+	conns   []*grpc.ClientConn
+	clients []workerpb.WorkerClient
+	codec   fastscheduler.Codec
+	next    atomic.Uint64
+}
+
+// NewExecutor 创建分派到 workers(gRPC 目标地址，例如 "10.0.0.5:9000" 或 "dns:///workers:9000")的执行器
+// opts 传给 grpc.NewClient，需要指定传输凭证，例如 grpc.WithTransportCredentials(insecure.NewCredentials())；
+// codec 为nil时使用 JSONCodec。不再使用时调用 Close 关闭连接
+func NewExecutor(workers []string, codec fastscheduler.Codec, opts ...grpc.DialOption) (*Executor, error) {
+	if codec == nil {
+		codec = fastscheduler.JSONCodec
+	}
+	opts = append([]grpc.DialOption{grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxResultSize))}, opts...)
+	e := &Executor{targets: workers, codec: codec}
+	for _, target := range workers {
+		conn, err := grpc.NewClient(target, opts...)
+		if err != nil {
+			e.Close()
+			return nil, fmt.Errorf("cluster: worker %s: %w", target, err)
+		}
+		e.conns = append(e.conns, conn)
+		e.clients = append(e.clients, workerpb.NewWorkerClient(conn))
+	}
+	return e, nil
+}
+
+// Close 关闭到各worker的连接
+func (e *Executor) Close() error {
+	var errs []error
+	for _, conn := range e.conns {
+		errs = append(errs, conn.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Execute 将任务发送给下一个worker，worker 不可达或未注册该任务类型时依次尝试其他worker
+// 结果数据解码为 interface{}(JSON 编码时为 map、切片等通用类型)
+func (e *Executor) Execute(ctx context.Context, t *fastscheduler.Task) (fastscheduler.TaskResult, error) {
+	if _, ok := t.Serializable(); !ok {
+		return t.Execute(ctx)
+	}
+	if len(e.clients) == 0 {
+		return fastscheduler.TaskResult{}, errors.New("cluster: no workers")
+	}
+	task, err := fastscheduler.MarshalTask(t)
+	if err != nil {
+		return fastscheduler.TaskResult{}, err
+	}
+	req := &workerpb.ExecuteRequest{Task: task}
+
+	start := e.next.Add(1)
+	var errs []error
+	for i := range e.clients {
+		k := int((start + uint64(i)) % uint64(len(e.clients)))
+		result, delivered, err := e.call(ctx, k, req)
+		if delivered || ctx.Err() != nil {
+			return result, err
+		}
+		errs = append(errs, err)
+	}
+	return fastscheduler.TaskResult{}, errors.Join(errs...)
+}
+
+// call 调用第 k 个worker的 Execute 方法，delivered 表示worker已接收任务(不应再发给其他worker)
+// Unavailable 表示连接不可用，InvalidArgument 和 Unimplemented 表示该worker无法解码任务(例如类型未注册)，
+// 这些情况下其他worker可能可以执行
+func (e *Executor) call(ctx context.Context, k int, req *workerpb.ExecuteRequest) (result fastscheduler.TaskResult, delivered bool, err error) {
+	resp, err := e.clients[k].Execute(ctx, req)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.Unavailable, codes.InvalidArgument, codes.Unimplemented:
+		default:
+			delivered = true
+		}
+		return fastscheduler.TaskResult{}, delivered, fmt.Errorf("cluster: worker %s: %w", e.targets[k], err)
+	}
+
+	var data interface{}
+	result, err = fastscheduler.DecodeResult(e.codec, fromResponse(resp), &data)
+	if err != nil {
+		return fastscheduler.TaskResult{}, true, fmt.Errorf("cluster: worker %s: decode result: %w", e.targets[k], err)
+	}
+	return result, true, result.Err
+}
+
+// toResponse 将结果转换为 ExecuteResponse
+func toResponse(w fastscheduler.WireResult) *workerpb.ExecuteResponse {
+	return &workerpb.ExecuteResponse{
+		TaskId:       w.TaskID,
+		HttpCode:     int32(w.HTTPCode),
+		BusinessCode: int32(w.BusinessCode),
+		Error:        w.Error,
+		Codec:        w.Codec,
+		Data:         w.Data,
+		Cost:         w.Cost,
+		Truncated:    w.Truncated,
+		Hints:        &workerpb.Hints{Region: w.Hints.Region, Provider: w.Hints.Provider, CostTier: w.Hints.CostTier},
+	}
+}
+
+// fromResponse 将 ExecuteResponse 转换回结果
+func fromResponse(r *workerpb.ExecuteResponse) fastscheduler.WireResult {
+	return fastscheduler.WireResult{
+		TaskID:       r.GetTaskId(),
+		HTTPCode:     int(r.GetHttpCode()),
+		BusinessCode: int(r.GetBusinessCode()),
+		Error:        r.GetError(),
+		Codec:        r.GetCodec(),
+		Data:         r.GetData(),
+		Cost:         r.GetCost(),
+		Truncated:    r.GetTruncated(),
+		Hints: fastscheduler.Hints{
+			Region:   r.GetHints().GetRegion(),
+			Provider: r.GetHints().GetProvider(),
+			CostTier: r.GetHints().GetCostTier(),
+		},
+	}
+}
@@ -0,0 +1,175 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+	"github.com/hawkli-1994/fast-scheduler/cluster/workerpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// cancelled 记录在worker端被取消的任务
+var cancelled = make(chan string, 10)
+
+func init() {
+	fastscheduler.RegisterTaskType("cluster.sleep", func(ctx context.Context, payload []byte) (fastscheduler.TaskResult, error) {
+		d, err := time.ParseDuration(string(payload))
+		if err != nil {
+			return fastscheduler.TaskResult{}, err
+		}
+		select {
+		case <-time.After(d):
+			return fastscheduler.TaskResult{HTTPCode: 200, BusinessCode: 0, Data: map[string]string{"slept": d.String()}}, nil
+		case <-ctx.Done():
+			cancelled <- d.String()
+			return fastscheduler.TaskResult{}, ctx.Err()
+		}
+	})
+}
+
+// newWorkerServer 以明文 gRPC 启动 worker，返回其地址
+func newWorkerServer(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	server := grpc.NewServer()
+	RegisterWorker(server, nil)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return lis.Addr().String()
+}
+
+func newExecutor(t *testing.T, workers ...string) *Executor {
+	t.Helper()
+	executor, err := NewExecutor(workers, nil, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewExecutor failed: %v", err)
+	}
+	t.Cleanup(func() { executor.Close() })
+	return executor
+}
+
+func sleepTask(t *testing.T, id, d string) *fastscheduler.Task {
+	task, err := fastscheduler.SerializableTask{ID: id, Type: "cluster.sleep", Payload: []byte(d)}.Task()
+	if err != nil {
+		t.Fatalf("Task failed: %v", err)
+	}
+	return task
+}
+
+func TestExecutor_FirstSuccessCancelsRemoteTasks(t *testing.T) {
+	executor := newExecutor(t, newWorkerServer(t), newWorkerServer(t))
+	scheduler := fastscheduler.NewScheduler(4, 10, fastscheduler.WithExecutor(executor))
+	defer scheduler.Stop()
+
+	batch, err := scheduler.SubmitBatch([]*fastscheduler.Task{
+		sleepTask(t, "slow", "10s"),
+		sleepTask(t, "fast", "10ms"),
+	})
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	batch.Wait()
+	if !batch.IsSuccess() {
+		t.Fatal("Expected batch to succeed")
+	}
+
+	select {
+	case d := <-cancelled:
+		if d != "10s" {
+			t.Errorf("Expected slow task to be cancelled, got %s", d)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Remote task was not cancelled after first success")
+	}
+}
+
+func TestExecutor_ResultAndFailover(t *testing.T) {
+	// 关闭监听后该地址的连接被拒绝
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	down := lis.Addr().String()
+	lis.Close()
+
+	executor := newExecutor(t, down, newWorkerServer(t))
+	scheduler := fastscheduler.NewScheduler(2, 10, fastscheduler.WithExecutor(executor))
+	defer scheduler.Stop()
+
+	for i := 0; i < 2; i++ {
+		future, err := scheduler.Submit(sleepTask(t, "task", "1ms"))
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		result := future.Result()
+		if result.Err != nil {
+			t.Fatalf("Expected success via failover, got %v", result.Err)
+		}
+		data, ok := result.Data.(map[string]interface{})
+		if !ok || data["slept"] != "1ms" {
+			t.Errorf("Unexpected result data %#v", result.Data)
+		}
+	}
+
+	// 闭包任务在协调者本地执行
+	future, err := scheduler.Submit(&fastscheduler.Task{
+		ID: "local",
+		Execute: func(ctx context.Context) (fastscheduler.TaskResult, error) {
+			return fastscheduler.TaskResult{HTTPCode: 200, BusinessCode: 0, Data: "local"}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if result := future.Result(); result.Data != "local" {
+		t.Errorf("Expected local execution, got %v", result.Data)
+	}
+}
+
+func TestExecutor_UnknownTaskTypeFailsOver(t *testing.T) {
+	executor := newExecutor(t, newWorkerServer(t))
+
+	task, err := fastscheduler.SerializableTask{ID: "x", Type: "cluster.sleep", Payload: []byte("1ms")}.Task()
+	if err != nil {
+		t.Fatalf("Task failed: %v", err)
+	}
+	data, err := fastscheduler.MarshalTask(task)
+	if err != nil {
+		t.Fatalf("MarshalTask failed: %v", err)
+	}
+	// 模拟未注册该类型的worker：改写类型名
+	data = bytes.Replace(data, []byte("cluster.sleep"), []byte("cluster.unknown"), 1)
+	_, delivered, err := executor.call(context.Background(), 0, &workerpb.ExecuteRequest{Task: data})
+	if delivered || status.Code(err) != codes.Unimplemented {
+		t.Errorf("Expected undelivered Unimplemented status, got delivered=%v err=%v", delivered, err)
+	}
+}
+
+func TestExecuteResponse_RoundTrip(t *testing.T) {
+	original := fastscheduler.WireResult{
+		TaskID:       "quote",
+		HTTPCode:     200,
+		BusinessCode: -3,
+		Error:        "partial",
+		Codec:        "msgpack",
+		Data:         []byte{0x81, 0xa1, 'a', 0x01},
+		Cost:         1.5,
+		Truncated:    true,
+		Hints:        fastscheduler.Hints{Region: "eu-west", CostTier: "spot"},
+	}
+	decoded := fromResponse(toResponse(original))
+	if !reflect.DeepEqual(decoded, original) {
+		t.Errorf("Round trip mismatch:\n got %+v\nwant %+v", decoded, original)
+	}
+}
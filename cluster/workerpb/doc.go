@@ -0,0 +1,4 @@
+// Package workerpb 是由 worker.proto 生成的 Worker gRPC 服务和消息，cluster 包基于它实现 worker 和协调者
+package workerpb
+
+//go:generate buf generate
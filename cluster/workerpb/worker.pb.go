@@ -0,0 +1,321 @@
+// cluster 包 worker 的 gRPC 服务定义，worker.pb.go 和 worker_grpc.pb.go 由本文件生成：
+//
+//	buf generate (或 protoc --go_out=. --go-grpc_out=. --go_opt=paths=source_relative --go-grpc_opt=paths=source_relative worker.proto)
+//
+// 其他语言可以由本文件生成与 cluster.RegisterWorker / cluster.NewExecutor 互通的服务端或客户端
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: worker.proto
+
+package workerpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ExecuteRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// fastscheduler.MarshalTask 的 JSON 编码：类型名、参数及可跨进程保留的任务字段
+	Task          []byte `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteRequest) Reset() {
+	*x = ExecuteRequest{}
+	mi := &file_worker_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteRequest) ProtoMessage() {}
+
+func (x *ExecuteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteRequest) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ExecuteRequest) GetTask() []byte {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+// fastscheduler.WireResult
+type ExecuteResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	TaskId       string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	HttpCode     int32                  `protobuf:"varint,2,opt,name=http_code,json=httpCode,proto3" json:"http_code,omitempty"`
+	BusinessCode int32                  `protobuf:"varint,3,opt,name=business_code,json=businessCode,proto3" json:"business_code,omitempty"`
+	// 任务返回的错误信息，成功时为空
+	Error string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// data 使用的编码名称，例如 "json"、"msgpack"
+	Codec         string  `protobuf:"bytes,5,opt,name=codec,proto3" json:"codec,omitempty"`
+	Data          []byte  `protobuf:"bytes,6,opt,name=data,proto3" json:"data,omitempty"`
+	Cost          float64 `protobuf:"fixed64,7,opt,name=cost,proto3" json:"cost,omitempty"`
+	Truncated     bool    `protobuf:"varint,8,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	Hints         *Hints  `protobuf:"bytes,9,opt,name=hints,proto3" json:"hints,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteResponse) Reset() {
+	*x = ExecuteResponse{}
+	mi := &file_worker_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteResponse) ProtoMessage() {}
+
+func (x *ExecuteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteResponse.ProtoReflect.Descriptor instead.
+func (*ExecuteResponse) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ExecuteResponse) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *ExecuteResponse) GetHttpCode() int32 {
+	if x != nil {
+		return x.HttpCode
+	}
+	return 0
+}
+
+func (x *ExecuteResponse) GetBusinessCode() int32 {
+	if x != nil {
+		return x.BusinessCode
+	}
+	return 0
+}
+
+func (x *ExecuteResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ExecuteResponse) GetCodec() string {
+	if x != nil {
+		return x.Codec
+	}
+	return ""
+}
+
+func (x *ExecuteResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ExecuteResponse) GetCost() float64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+func (x *ExecuteResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *ExecuteResponse) GetHints() *Hints {
+	if x != nil {
+		return x.Hints
+	}
+	return nil
+}
+
+type Hints struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Region        string                 `protobuf:"bytes,1,opt,name=region,proto3" json:"region,omitempty"`
+	Provider      string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	CostTier      string                 `protobuf:"bytes,3,opt,name=cost_tier,json=costTier,proto3" json:"cost_tier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Hints) Reset() {
+	*x = Hints{}
+	mi := &file_worker_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Hints) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hints) ProtoMessage() {}
+
+func (x *Hints) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hints.ProtoReflect.Descriptor instead.
+func (*Hints) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Hints) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Hints) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Hints) GetCostTier() string {
+	if x != nil {
+		return x.CostTier
+	}
+	return ""
+}
+
+var File_worker_proto protoreflect.FileDescriptor
+
+const file_worker_proto_rawDesc = "" +
+	"\n" +
+	"\fworker.proto\x12\x18fastscheduler.cluster.v1\"$\n" +
+	"\x0eExecuteRequest\x12\x12\n" +
+	"\x04task\x18\x01 \x01(\fR\x04task\"\x95\x02\n" +
+	"\x0fExecuteResponse\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1b\n" +
+	"\thttp_code\x18\x02 \x01(\x05R\bhttpCode\x12#\n" +
+	"\rbusiness_code\x18\x03 \x01(\x05R\fbusinessCode\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12\x14\n" +
+	"\x05codec\x18\x05 \x01(\tR\x05codec\x12\x12\n" +
+	"\x04data\x18\x06 \x01(\fR\x04data\x12\x12\n" +
+	"\x04cost\x18\a \x01(\x01R\x04cost\x12\x1c\n" +
+	"\ttruncated\x18\b \x01(\bR\ttruncated\x125\n" +
+	"\x05hints\x18\t \x01(\v2\x1f.fastscheduler.cluster.v1.HintsR\x05hints\"X\n" +
+	"\x05Hints\x12\x16\n" +
+	"\x06region\x18\x01 \x01(\tR\x06region\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x1b\n" +
+	"\tcost_tier\x18\x03 \x01(\tR\bcostTier2h\n" +
+	"\x06Worker\x12^\n" +
+	"\aExecute\x12(.fastscheduler.cluster.v1.ExecuteRequest\x1a).fastscheduler.cluster.v1.ExecuteResponseB8Z6github.com/hawkli-1994/fast-scheduler/cluster/workerpbb\x06proto3"
+
+var (
+	file_worker_proto_rawDescOnce sync.Once
+	file_worker_proto_rawDescData []byte
+)
+
+func file_worker_proto_rawDescGZIP() []byte {
+	file_worker_proto_rawDescOnce.Do(func() {
+		file_worker_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_worker_proto_rawDesc), len(file_worker_proto_rawDesc)))
+	})
+	return file_worker_proto_rawDescData
+}
+
+var file_worker_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_worker_proto_goTypes = []any{
+	(*ExecuteRequest)(nil),  // 0: fastscheduler.cluster.v1.ExecuteRequest
+	(*ExecuteResponse)(nil), // 1: fastscheduler.cluster.v1.ExecuteResponse
+	(*Hints)(nil),           // 2: fastscheduler.cluster.v1.Hints
+}
+var file_worker_proto_depIdxs = []int32{
+	2, // 0: fastscheduler.cluster.v1.ExecuteResponse.hints:type_name -> fastscheduler.cluster.v1.Hints
+	0, // 1: fastscheduler.cluster.v1.Worker.Execute:input_type -> fastscheduler.cluster.v1.ExecuteRequest
+	1, // 2: fastscheduler.cluster.v1.Worker.Execute:output_type -> fastscheduler.cluster.v1.ExecuteResponse
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_worker_proto_init() }
+func file_worker_proto_init() {
+	if File_worker_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_worker_proto_rawDesc), len(file_worker_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_worker_proto_goTypes,
+		DependencyIndexes: file_worker_proto_depIdxs,
+		MessageInfos:      file_worker_proto_msgTypes,
+	}.Build()
+	File_worker_proto = out.File
+	file_worker_proto_goTypes = nil
+	file_worker_proto_depIdxs = nil
+}
@@ -0,0 +1,210 @@
+package fastscheduler
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Codec 序列化 TaskResult.Data，在结果跨进程传递(远程提交、持久化、回调通知)时使用
+// 默认使用 JSONCodec，另外提供 GobCodec、BinaryCodec 和 ProtoCodec，MessagePack 由独立模块 msgpackcodec 提供；
+// 其他格式实现该接口后通过 WithCodec 接入
+type Codec interface {
+	// Name 返回编码名称，写入 WireResult.Codec 以便接收方校验
+	Name() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+var (
+	// JSONCodec 使用 encoding/json 编码
+	JSONCodec Codec = jsonCodec{}
+	// GobCodec 使用 encoding/gob 编码，适用于两端都是Go程序的场景
+	GobCodec Codec = gobCodec{}
+	// BinaryCodec 要求数据实现 encoding.BinaryMarshaler / BinaryUnmarshaler
+	BinaryCodec Codec = binaryCodec{}
+	// ProtoCodec 使用 Protocol Buffers 编码，要求数据实现 Marshal() ([]byte, error) 和 Unmarshal([]byte) error，
+	// gogo/protobuf 等生成的消息类型提供这两个方法；google.golang.org/protobuf 的消息通过 NewProtoCodec 接入
+	ProtoCodec Codec = NewProtoCodec(marshalProto, unmarshalProto)
+)
+
+// ErrCodecMismatch 表示解码时使用的编码与写入时不一致
+var ErrCodecMismatch = errors.New("fastscheduler: codec mismatch")
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string                               { return "json" }
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+type gobCodec struct{}
+
+func (gobCodec) Name() string { return "gob" }
+
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+type binaryCodec struct{}
+
+func (binaryCodec) Name() string { return "binary" }
+
+func (binaryCodec) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("fastscheduler: %T does not implement encoding.BinaryMarshaler", v)
+	}
+	return m.MarshalBinary()
+}
+
+func (binaryCodec) Unmarshal(data []byte, v interface{}) error {
+	u, ok := v.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return fmt.Errorf("fastscheduler: %T does not implement encoding.BinaryUnmarshaler", v)
+	}
+	return u.UnmarshalBinary(data)
+}
+
+// protoMessage gogo/protobuf 等生成代码提供的编解码方法
+type protoMessage interface {
+	Marshal() ([]byte, error)
+	Unmarshal(data []byte) error
+}
+
+func marshalProto(v interface{}) ([]byte, error) {
+	m, ok := v.(protoMessage)
+	if !ok {
+		return nil, fmt.Errorf("fastscheduler: %T does not implement Marshal/Unmarshal", v)
+	}
+	return m.Marshal()
+}
+
+func unmarshalProto(data []byte, v interface{}) error {
+	m, ok := v.(protoMessage)
+	if !ok {
+		return fmt.Errorf("fastscheduler: %T does not implement Marshal/Unmarshal", v)
+	}
+	return m.Unmarshal(data)
+}
+
+// NewProtoCodec 返回使用 marshal/unmarshal 编解码的 protobuf Codec，名称为 "proto"，例如接入 google.golang.org/protobuf：
+//
+//	fastscheduler.NewProtoCodec(
+//		func(v interface{}) ([]byte, error) { return proto.Marshal(v.(proto.Message)) },
+//		func(b []byte, v interface{}) error { return proto.Unmarshal(b, v.(proto.Message)) },
+//	)
+//
+// 消息类型通常以指针作为 Data，DecodeResult 传入指向消息指针的指针时，unmarshal 收到的是新分配的消息指针
+func NewProtoCodec(marshal func(v interface{}) ([]byte, error), unmarshal func(data []byte, v interface{}) error) Codec {
+	return protoCodec{marshal: marshal, unmarshal: unmarshal}
+}
+
+type protoCodec struct {
+	marshal   func(v interface{}) ([]byte, error)
+	unmarshal func(data []byte, v interface{}) error
+}
+
+func (protoCodec) Name() string { return "proto" }
+
+func (c protoCodec) Marshal(v interface{}) ([]byte, error) { return c.marshal(v) }
+
+func (c protoCodec) Unmarshal(data []byte, v interface{}) error {
+	// 指向消息指针的指针：分配消息后解码到消息本身
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && !rv.IsNil() && rv.Elem().Kind() == reflect.Pointer {
+		if rv.Elem().IsNil() {
+			rv.Elem().Set(reflect.New(rv.Elem().Type().Elem()))
+		}
+		v = rv.Elem().Interface()
+	}
+	return c.unmarshal(data, v)
+}
+
+// WireResult 是 TaskResult 的可序列化形式，Data 已按 Codec 编码
+type WireResult struct {
+	TaskID       string  `json:"task_id"`
+	HTTPCode     int     `json:"http_code"`
+	BusinessCode int     `json:"business_code"`
+	Error        string  `json:"error,omitempty"`
+	Codec        string  `json:"codec,omitempty"`
+	Data         []byte  `json:"data,omitempty"`
+	Cost         float64 `json:"cost,omitempty"`
+	Truncated    bool    `json:"truncated,omitempty"`
+	Hints        Hints   `json:"hints"`
+}
+
+// EncodeResult 将结果转换为可跨进程传递的形式，错误只保留错误信息
+func EncodeResult(c Codec, r TaskResult) (WireResult, error) {
+	w := WireResult{
+		TaskID:       r.TaskID,
+		HTTPCode:     r.HTTPCode,
+		BusinessCode: r.BusinessCode,
+		Codec:        c.Name(),
+		Cost:         r.Cost,
+		Truncated:    r.Truncated,
+		Hints:        r.Hints,
+	}
+	if r.Err != nil {
+		w.Error = r.Err.Error()
+	}
+	if r.Data != nil {
+		data, err := c.Marshal(r.Data)
+		if err != nil {
+			return WireResult{}, fmt.Errorf("fastscheduler: encode result data: %w", err)
+		}
+		w.Data = data
+	}
+	return w, nil
+}
+
+// DecodeResult 还原结果，Data 解码到 data 指向的值并以该值(非指针)填入 TaskResult.Data
+// data 为nil时不解码 Data
+func DecodeResult(c Codec, w WireResult, data interface{}) (TaskResult, error) {
+	if w.Codec != "" && w.Codec != c.Name() {
+		return TaskResult{}, fmt.Errorf("%w: encoded with %s, decoding with %s", ErrCodecMismatch, w.Codec, c.Name())
+	}
+	r := TaskResult{
+		TaskID:       w.TaskID,
+		HTTPCode:     w.HTTPCode,
+		BusinessCode: w.BusinessCode,
+		Cost:         w.Cost,
+		Truncated:    w.Truncated,
+		Hints:        w.Hints,
+	}
+	if w.Error != "" {
+		r.Err = errors.New(w.Error)
+	}
+	if data != nil && w.Data != nil {
+		if err := c.Unmarshal(w.Data, data); err != nil {
+			return TaskResult{}, fmt.Errorf("fastscheduler: decode result data: %w", err)
+		}
+		r.Data = reflect.ValueOf(data).Elem().Interface()
+	}
+	return r, nil
+}
+
+// WithCodec 设置结果跨进程传递时使用的编码，默认 JSONCodec
+func WithCodec(c Codec) Option {
+	return func(s *Scheduler) {
+		s.codec = c
+	}
+}
+
+// Codec 返回调度器使用的结果编码
+func (s *Scheduler) Codec() Codec {
+	if s.codec == nil {
+		return JSONCodec
+	}
+	return s.codec
+}
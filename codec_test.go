@@ -0,0 +1,88 @@
+package fastscheduler
+
+import (
+	"errors"
+	"testing"
+)
+
+type price struct {
+	Provider string
+	Cents    int
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	original := TaskResult{
+		TaskID:       "quote",
+		HTTPCode:     200,
+		BusinessCode: 0,
+		Data:         price{Provider: "a", Cents: 199},
+		Hints:        Hints{Region: "eu-west"},
+	}
+
+	for _, codec := range []Codec{JSONCodec, GobCodec} {
+		w, err := EncodeResult(codec, original)
+		if err != nil {
+			t.Fatalf("%s: encode failed: %v", codec.Name(), err)
+		}
+
+		var data price
+		decoded, err := DecodeResult(codec, w, &data)
+		if err != nil {
+			t.Fatalf("%s: decode failed: %v", codec.Name(), err)
+		}
+		if decoded.Data != original.Data || decoded.TaskID != "quote" || decoded.Hints.Region != "eu-west" {
+			t.Errorf("%s: round trip mismatch: %+v", codec.Name(), decoded)
+		}
+	}
+}
+
+func TestCodec_Mismatch(t *testing.T) {
+	w, err := EncodeResult(GobCodec, TaskResult{Data: 1, Err: errors.New("boom")})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if _, err := DecodeResult(JSONCodec, w, new(int)); !errors.Is(err, ErrCodecMismatch) {
+		t.Errorf("Expected ErrCodecMismatch, got %v", err)
+	}
+
+	decoded, err := DecodeResult(GobCodec, w, nil)
+	if err != nil || decoded.Err == nil || decoded.Err.Error() != "boom" {
+		t.Errorf("Expected error message to survive round trip, got %+v, %v", decoded, err)
+	}
+}
+
+// protoQuote 模拟生成代码中带 Marshal/Unmarshal 方法的消息
+type protoQuote struct {
+	Cents int
+}
+
+func (q *protoQuote) Marshal() ([]byte, error) {
+	return []byte{byte(q.Cents)}, nil
+}
+
+func (q *protoQuote) Unmarshal(data []byte) error {
+	if len(data) != 1 {
+		return errors.New("bad length")
+	}
+	q.Cents = int(data[0])
+	return nil
+}
+
+func TestCodec_Proto(t *testing.T) {
+	w, err := EncodeResult(ProtoCodec, TaskResult{TaskID: "quote", Data: &protoQuote{Cents: 42}})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	var msg *protoQuote
+	decoded, err := DecodeResult(ProtoCodec, w, &msg)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if got, ok := decoded.Data.(*protoQuote); !ok || got.Cents != 42 || w.Codec != "proto" {
+		t.Errorf("Unexpected decoded data %#v", decoded.Data)
+	}
+
+	if _, err := EncodeResult(ProtoCodec, TaskResult{Data: "not a message"}); err == nil {
+		t.Error("Expected error for data without Marshal method")
+	}
+}
@@ -0,0 +1,89 @@
+package fastscheduler
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// CostRecord 一次执行的成本记录，用于向内部使用方计费
+// 每次执行(包括重试)各产生一条记录，与 Stats().Cost 和 Batch.Cost() 的累计口径一致
+type CostRecord struct {
+	Time    time.Time `json:"time"`
+	TaskID  string    `json:"task_id"`
+	BatchID string    `json:"batch_id"`
+	Tenant  string    `json:"tenant,omitempty"`
+	Tags    []string  `json:"tags,omitempty"`
+	Cost    float64   `json:"cost"`
+	// Attempt 第几次执行，从1开始
+	Attempt int `json:"attempt"`
+	// Success 该次执行是否成功
+	Success bool `json:"success"`
+}
+
+// CostAuditor 接收成本记录，在执行任务的 worker 上同步调用，实现应当尽快返回且并发安全
+type CostAuditor interface {
+	AuditCost(record CostRecord)
+}
+
+// CostAuditorFunc 将函数适配为 CostAuditor
+type CostAuditorFunc func(record CostRecord)
+
+// AuditCost 实现 CostAuditor 接口
+func (f CostAuditorFunc) AuditCost(record CostRecord) {
+	f(record)
+}
+
+// WithCostAudit 每次执行产生非零成本时向 a 发送一条 CostRecord，成本为0的执行不记录
+func WithCostAudit(a CostAuditor) Option {
+	return func(s *Scheduler) {
+		s.costAudit = a
+	}
+}
+
+// CostAuditLog 以 JSON Lines 格式写出成本记录的 CostAuditor，每条记录一行，可直接导入计费系统
+type CostAuditLog struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+}
+
+// NewCostAuditLog 返回写入 w 的成本审计日志，w 通常是按天滚动的文件
+func NewCostAuditLog(w io.Writer) *CostAuditLog {
+	return &CostAuditLog{enc: json.NewEncoder(w)}
+}
+
+// AuditCost 实现 CostAuditor 接口，写入失败后不再写入，错误通过 Err 返回
+func (l *CostAuditLog) AuditCost(record CostRecord) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return
+	}
+	l.err = l.enc.Encode(record)
+}
+
+// Err 返回第一次写入失败的错误
+func (l *CostAuditLog) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// auditCost 记录一次执行的成本
+func (s *Scheduler) auditCost(task *Task, result TaskResult, err error) {
+	if s.costAudit == nil || result.Cost == 0 {
+		return
+	}
+	s.costAudit.AuditCost(CostRecord{
+		Time:    s.now(),
+		TaskID:  task.ID,
+		BatchID: task.group.id,
+		Tenant:  task.Tenant,
+		Tags:    task.Tags,
+		Cost:    result.Cost,
+		Attempt: task.attempts,
+		Success: err == nil && isSuccess(result),
+	})
+}
@@ -0,0 +1,75 @@
+package fastscheduler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestScheduler_CostAuditLog(t *testing.T) {
+	var buf bytes.Buffer
+	audit := NewCostAuditLog(&buf)
+	scheduler := NewScheduler(2, 10, WithCostAudit(audit))
+	defer scheduler.Stop()
+
+	var calls atomic.Int32
+	batch, err := scheduler.SubmitBatch([]*Task{
+		{
+			ID:     "llm-call",
+			Cost:   2,
+			Tenant: "team-a",
+			Tags:   []string{"llm"},
+			Retry:  &RetryPolicy{MaxAttempts: 2},
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				if calls.Add(1) == 1 {
+					return TaskResult{HTTPCode: 503}, errors.New("unavailable")
+				}
+				return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+			},
+		},
+		{
+			ID: "free",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 500}, nil
+			},
+		},
+	}, WithBatchID("billing"))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+	if err := audit.Err(); err != nil {
+		t.Fatalf("Audit log failed: %v", err)
+	}
+
+	var records []CostRecord
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var r CostRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("Invalid audit line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, r)
+	}
+	// 每次执行各一条记录，成本为0的任务不记录
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 cost records, got %+v", records)
+	}
+	var total float64
+	for i, r := range records {
+		if r.TaskID != "llm-call" || r.BatchID != "billing" || r.Tenant != "team-a" || r.Attempt != i+1 {
+			t.Errorf("Unexpected record %+v", r)
+		}
+		total += r.Cost
+	}
+	if records[0].Success || !records[1].Success {
+		t.Errorf("Expected failed then successful attempt, got %+v", records)
+	}
+	if stats := scheduler.Stats().Cost; total != stats.Total {
+		t.Errorf("Expected audit total %v to match Stats total %v", total, stats.Total)
+	}
+}
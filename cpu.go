@@ -0,0 +1,33 @@
+package fastscheduler
+
+import (
+	"math"
+	"runtime"
+)
+
+// WithCPUBound 用于CPU密集型任务的调度器，worker数不超过可用CPU数
+// 可用CPU数取 GOMAXPROCS 与容器cgroup CPU配额中的较小值，避免在受限容器中过度订阅
+func WithCPUBound() Option {
+	return func(s *Scheduler) {
+		s.cpuBound = true
+	}
+}
+
+// availableCPUs 返回可用CPU数
+func availableCPUs() int {
+	n := runtime.GOMAXPROCS(0)
+	if quota, ok := cgroupCPUQuota(); ok {
+		if limit := int(math.Ceil(quota)); limit > 0 && limit < n {
+			n = limit
+		}
+	}
+	return n
+}
+
+// capToCPUs 将池大小限制在可用CPU数以内
+func capToCPUs(poolSize int) int {
+	if n := availableCPUs(); poolSize <= 0 || poolSize > n {
+		return n
+	}
+	return poolSize
+}
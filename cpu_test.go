@@ -0,0 +1,18 @@
+package fastscheduler
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestScheduler_CPUBoundPoolSize(t *testing.T) {
+	scheduler := NewScheduler(runtime.GOMAXPROCS(0)*4, 10, WithCPUBound())
+	defer scheduler.Stop()
+
+	if _, got := scheduler.workerPool.usage(); got != availableCPUs() {
+		t.Errorf("Expected CPU-bound pool capped at %d, got %d", availableCPUs(), got)
+	}
+	if capToCPUs(1) != 1 {
+		t.Error("Pool smaller than CPU count should not be changed")
+	}
+}
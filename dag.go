@@ -0,0 +1,152 @@
+package fastscheduler
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrInvalidDependencies 表示批次内的 DependsOn 引用了不存在或重复的任务ID，或存在循环依赖
+var ErrInvalidDependencies = errors.New("fastscheduler: invalid task dependencies")
+
+// ErrDependencyFailed 表示任务因依赖的任务失败而未执行
+var ErrDependencyFailed = errors.New("fastscheduler: dependency failed")
+
+// dagState 批次内任务的依赖关系
+type dagState struct {
+	mu sync.Mutex
+	// dependents 每个任务完成后需要通知的任务
+	dependents map[*Task][]*Task
+	// pending 每个任务尚未成功完成的依赖数
+	pending map[*Task]int
+	// finished 已完成(包括因依赖失败被跳过)的任务
+	finished map[*Task]bool
+}
+
+// resolveDependencies 校验 DependsOn 并返回每个任务依赖的任务下标，没有依赖时返回nil
+func resolveDependencies(tasks []*Task) ([][]int, error) {
+	hasDeps := false
+	for _, t := range tasks {
+		if len(t.DependsOn) > 0 {
+			hasDeps = true
+			break
+		}
+	}
+	if !hasDeps {
+		return nil, nil
+	}
+
+	index := make(map[string]int, len(tasks))
+	for i, t := range tasks {
+		if _, dup := index[t.ID]; dup {
+			return nil, fmt.Errorf("%w: duplicate task id %q", ErrInvalidDependencies, t.ID)
+		}
+		index[t.ID] = i
+	}
+
+	deps := make([][]int, len(tasks))
+	indegree := make([]int, len(tasks))
+	dependents := make([][]int, len(tasks))
+	for i, t := range tasks {
+		for _, id := range t.DependsOn {
+			j, ok := index[id]
+			if !ok {
+				return nil, fmt.Errorf("%w: task %q depends on unknown task %q", ErrInvalidDependencies, t.ID, id)
+			}
+			deps[i] = append(deps[i], j)
+			dependents[j] = append(dependents[j], i)
+			indegree[i]++
+		}
+	}
+
+	// 拓扑排序检测循环依赖
+	var ready []int
+	for i, d := range indegree {
+		if d == 0 {
+			ready = append(ready, i)
+		}
+	}
+	visited := 0
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		visited++
+		for _, j := range dependents[i] {
+			indegree[j]--
+			if indegree[j] == 0 {
+				ready = append(ready, j)
+			}
+		}
+	}
+	if visited != len(tasks) {
+		return nil, fmt.Errorf("%w: dependency cycle detected", ErrInvalidDependencies)
+	}
+	return deps, nil
+}
+
+// newDAGState 根据依赖下标建立任务副本之间的依赖关系，并标记需要等待的任务
+func newDAGState(queued []*Task, deps [][]int) *dagState {
+	d := &dagState{
+		dependents: make(map[*Task][]*Task),
+		pending:    make(map[*Task]int),
+		finished:   make(map[*Task]bool),
+	}
+	for i, ds := range deps {
+		if len(ds) == 0 {
+			continue
+		}
+		t := queued[i]
+		t.blocked = true
+		d.pending[t] = len(ds)
+		for _, j := range ds {
+			d.dependents[queued[j]] = append(d.dependents[queued[j]], t)
+		}
+	}
+	return d
+}
+
+// resolveDependents 任务完成后，成功时启动依赖已全部满足的任务，失败时使依赖它的任务失败
+func (s *Scheduler) resolveDependents(task *Task, succeeded bool) {
+	d := task.group.dag
+	d.mu.Lock()
+	d.finished[task] = true
+	var ready, failed []*Task
+	for _, dep := range d.dependents[task] {
+		if d.finished[dep] {
+			continue
+		}
+		if !succeeded {
+			d.finished[dep] = true
+			failed = append(failed, dep)
+			continue
+		}
+		d.pending[dep]--
+		if d.pending[dep] == 0 {
+			ready = append(ready, dep)
+		}
+	}
+	d.mu.Unlock()
+
+	for _, dep := range failed {
+		s.skipTask(dep, fmt.Errorf("%w: %s", ErrDependencyFailed, task.ID))
+	}
+	for _, dep := range ready {
+		dep.reason = "dependencies satisfied"
+		s.admitReady(dep)
+	}
+}
+
+// admitReady 依赖已满足的任务与提交时走相同的准入路径：视图限制和启动延迟
+// 同步模式下直接入队以保持执行顺序确定，否则在新的goroutine中等待准入，避免阻塞完成路径
+func (s *Scheduler) admitReady(t *Task) {
+	admit := func() {
+		if err := s.admitTask(t); err != nil {
+			s.skipTask(t, err)
+		}
+	}
+	if s.synchronous() {
+		admit()
+		return
+	}
+	go admit()
+}
@@ -0,0 +1,160 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatch_DependsOn(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	var mu sync.Mutex
+	var order []string
+	step := func(id string, ok bool, deps ...string) *Task {
+		return &Task{
+			ID:        id,
+			DependsOn: deps,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				mu.Lock()
+				order = append(order, id)
+				mu.Unlock()
+				if !ok {
+					return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+				}
+				return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+			},
+		}
+	}
+
+	tasks := []*Task{
+		step("load", true, "auth"),
+		step("auth", true),
+		step("render", true, "load", "auth"),
+		step("broken", false),
+		step("after-broken", true, "broken"),
+		step("after-after", true, "after-broken"),
+	}
+	batch, err := scheduler.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	pos := make(map[string]int)
+	for i, id := range order {
+		pos[id] = i
+	}
+	if pos["auth"] > pos["load"] || pos["load"] > pos["render"] {
+		t.Errorf("Dependencies ran out of order: %v", order)
+	}
+	if _, ran := pos["after-broken"]; ran {
+		t.Error("Task with failed dependency should not run")
+	}
+	if _, ran := pos["after-after"]; ran {
+		t.Error("Transitive dependent of failed task should not run")
+	}
+
+	var dependencyFailures int
+	for r := range batch.ResultsChan() {
+		if errors.Is(r.Err, ErrDependencyFailed) {
+			dependencyFailures++
+		}
+	}
+	if dependencyFailures != 2 {
+		t.Errorf("Expected 2 dependency failures, got %d", dependencyFailures)
+	}
+}
+
+func TestBatch_InvalidDependencies(t *testing.T) {
+	scheduler := NewScheduler(2, 10)
+	defer scheduler.Stop()
+
+	noop := func(ctx context.Context) (TaskResult, error) {
+		return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+	}
+	cases := map[string][]*Task{
+		"unknown": {{ID: "a", Execute: noop, DependsOn: []string{"missing"}}},
+		"cycle": {
+			{ID: "a", Execute: noop, DependsOn: []string{"b"}},
+			{ID: "b", Execute: noop, DependsOn: []string{"a"}},
+		},
+		"duplicate": {
+			{ID: "a", Execute: noop},
+			{ID: "a", Execute: noop},
+			{ID: "b", Execute: noop, DependsOn: []string{"a"}},
+		},
+	}
+	for name, tasks := range cases {
+		if _, err := scheduler.SubmitBatch(tasks); !errors.Is(err, ErrInvalidDependencies) {
+			t.Errorf("%s: expected ErrInvalidDependencies, got %v", name, err)
+		}
+	}
+}
+
+func TestBatch_DependentsGoThroughScopeAdmission(t *testing.T) {
+	scheduler := NewScheduler(4, 10)
+	defer scheduler.Stop()
+	scope := scheduler.WithLimits(1, 0)
+
+	var running peakCounter
+	step := func(id string, deps ...string) *Task {
+		return &Task{
+			ID:        id,
+			DependsOn: deps,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				running.enter(1)
+				defer running.exit(1)
+				time.Sleep(5 * time.Millisecond)
+				return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+			},
+		}
+	}
+
+	batch, err := scope.SubmitBatch([]*Task{
+		step("root"),
+		step("left", "root"),
+		step("right", "root"),
+		step("middle", "root"),
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+	if peak := running.peak.Load(); peak != 1 {
+		t.Errorf("Expected dependents to respect the scope limit of 1, peak was %d", peak)
+	}
+}
+
+func TestBatch_DependentsKeepStartDelay(t *testing.T) {
+	scheduler := NewScheduler(4, 10)
+	defer scheduler.Stop()
+
+	var mu sync.Mutex
+	var rootDone, childStart time.Time
+	tasks := []*Task{
+		{ID: "root", Execute: func(ctx context.Context) (TaskResult, error) {
+			mu.Lock()
+			rootDone = time.Now()
+			mu.Unlock()
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		}},
+		{ID: "child", DependsOn: []string{"root"}, Execute: func(ctx context.Context) (TaskResult, error) {
+			mu.Lock()
+			childStart = time.Now()
+			mu.Unlock()
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		}},
+	}
+	batch, err := scheduler.SubmitBatch(tasks, Stagger(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+	if childStart.Sub(rootDone) < 40*time.Millisecond {
+		t.Errorf("Expected the dependent to wait for its start delay, started %v after its dependency", childStart.Sub(rootDone))
+	}
+}
@@ -0,0 +1,98 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// FailedTask 最终失败的任务及其最后一次结果
+type FailedTask struct {
+	// Task 失败任务的副本，可以直接重新提交
+	Task *Task
+	// Result 任务最后一次的结果，Attempts 为实际执行次数
+	Result TaskResult
+}
+
+// WithDeadLetterQueue 启用容量为 size 的死信队列
+// 重试耗尽后仍失败的任务会连同最后一次结果放入队列，通过 DeadLetters 读取；
+// 队列已满时丢弃最早的死信。因取消(例如同组任务已成功)或预算用完而结束的任务不会进入死信队列
+func WithDeadLetterQueue(size int) Option {
+	return func(s *Scheduler) {
+		if size > 0 {
+			s.deadLetters = make(chan FailedTask, size)
+		}
+	}
+}
+
+// WithDeadLetterHandler 将最终失败的任务交给 handler 处理，而不是放入死信队列
+// handler 在完成任务的goroutine中同步调用，应尽快返回
+func WithDeadLetterHandler(handler func(FailedTask)) Option {
+	return func(s *Scheduler) {
+		s.deadLetterHandler = handler
+	}
+}
+
+// DeadLetters 返回死信队列，未通过 WithDeadLetterQueue 启用时返回nil
+func (s *Scheduler) DeadLetters() <-chan FailedTask {
+	return s.deadLetters
+}
+
+// deadLetter 记录最终失败的任务
+func (s *Scheduler) deadLetter(task *Task, result TaskResult) {
+	if s.deadLetters == nil && s.deadLetterHandler == nil {
+		return
+	}
+	if errors.Is(result.Err, context.Canceled) || errors.Is(result.Err, ErrBudgetExceeded) {
+		return
+	}
+
+	failed := FailedTask{Task: task.detached(), Result: result}
+	if s.deadLetterHandler != nil {
+		s.deadLetterHandler(failed)
+		return
+	}
+	for {
+		select {
+		case s.deadLetters <- failed:
+			return
+		default:
+		}
+		select {
+		case <-s.deadLetters:
+		default:
+		}
+	}
+}
+
+// detached 返回只包含公开字段的任务副本
+func (t *Task) detached() *Task {
+	// 内部字段 taskCancel/cancelled 可能被 CancelTask 并发修改
+	if t.group != nil {
+		t.group.taskMu.Lock()
+	}
+	c := *t
+	if t.group != nil {
+		t.group.taskMu.Unlock()
+	}
+	c.lane = 0
+	c.release = nil
+	c.enqueuedAt = time.Time{}
+	c.startDelay = 0
+	c.keyHeld = false
+	c.hostHeld = false
+	c.bulkhead = nil
+	c.tenantHeld = false
+	c.preempted = false
+	c.extraSlots = 0
+	c.blocked = false
+	c.attempts = 0
+	c.startedAt = time.Time{}
+	c.reason = ""
+	c.group = nil
+	c.cancelFunc = nil
+	c.taskCancel = nil
+	c.cancelled = false
+	c.resolved = nil
+	return &c
+}
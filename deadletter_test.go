@@ -0,0 +1,90 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScheduler_DeadLetterQueue(t *testing.T) {
+	scheduler := NewScheduler(2, 10, WithDeadLetterQueue(10))
+	defer scheduler.Stop()
+
+	boom := errors.New("upstream unavailable")
+	batch, err := scheduler.SubmitBatch([]*Task{
+		{
+			ID:    "failing",
+			Retry: &RetryPolicy{MaxAttempts: 2},
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{}, boom
+			},
+		},
+		{
+			ID: "cancelled",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				<-ctx.Done()
+				return TaskResult{}, ctx.Err()
+			},
+		},
+		{
+			ID: "winner",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				time.Sleep(20 * time.Millisecond)
+				return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	select {
+	case failed := <-scheduler.DeadLetters():
+		if failed.Task.ID != "failing" || failed.Result.Attempts != 2 || !errors.Is(failed.Result.Err, boom) {
+			t.Errorf("Unexpected dead letter: %+v", failed)
+		}
+		// 死信中的任务可以重新提交
+		if _, err := scheduler.SubmitBatch([]*Task{failed.Task}); err != nil {
+			t.Errorf("Resubmit dead letter failed: %v", err)
+		}
+	default:
+		t.Fatal("Expected failed task in dead-letter queue")
+	}
+
+	select {
+	case failed := <-scheduler.DeadLetters():
+		t.Errorf("Cancelled task should not be dead-lettered: %+v", failed)
+	default:
+	}
+}
+
+func TestScheduler_DeadLetterHandler(t *testing.T) {
+	handled := make(chan FailedTask, 1)
+	scheduler := NewScheduler(2, 10, WithDeadLetterHandler(func(f FailedTask) {
+		handled <- f
+	}))
+	defer scheduler.Stop()
+
+	if _, err := scheduler.Submit(&Task{
+		ID: "failing",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+		},
+	}); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	select {
+	case f := <-handled:
+		if f.Task.ID != "failing" {
+			t.Errorf("Unexpected dead letter: %+v", f)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Dead-letter handler was not called")
+	}
+	if scheduler.DeadLetters() != nil {
+		t.Error("DeadLetters should be nil when only a handler is configured")
+	}
+}
@@ -0,0 +1,105 @@
+package fastscheduler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Decision 一次调度决策：某个任务在何时、因为什么原因被分派给worker
+type Decision struct {
+	// Time 分派时间
+	Time time.Time
+	// TaskID 被分派的任务
+	TaskID string
+	// Lane 任务所在的优先级通道
+	Lane Lane
+	// Attempt 本次是任务的第几次执行
+	Attempt int
+	// QueueWait 任务从提交到分派的时长
+	QueueWait time.Duration
+	// Reason 选择该任务的原因，例如更高优先级通道为空或达到配额、重试、Key 释放
+	Reason string
+}
+
+// WithDecisionLog 记录最近 size 次调度决策，用于排查调度顺序
+// 记录通过 Decisions 或 DebugDump 查看，未启用时没有额外开销
+func WithDecisionLog(size int) Option {
+	return func(s *Scheduler) {
+		if size > 0 {
+			s.decisions = newRingBuffer[Decision](size)
+		}
+	}
+}
+
+// Decisions 返回最近的调度决策(从旧到新)，未通过 WithDecisionLog 启用时返回nil
+func (s *Scheduler) Decisions() []Decision {
+	if s.decisions == nil {
+		return nil
+	}
+	return s.decisions.snapshot()
+}
+
+// recordDecision 记录任务被分派的原因，idle 表示调度goroutine在所有通道为空时等到了该任务
+func (s *Scheduler) recordDecision(task *Task, lane Lane, idle bool) {
+	if s.decisions == nil {
+		return
+	}
+
+	var reasons []string
+	if task.bulkhead != nil {
+		reasons = append(reasons, task.bulkhead.label)
+	} else if idle {
+		reasons = append(reasons, "dispatched on arrival")
+	} else {
+		for _, higher := range laneOrder {
+			if higher == lane {
+				break
+			}
+			if !s.laneOpen(higher) {
+				reasons = append(reasons, higher.String()+" at quota")
+			} else {
+				reasons = append(reasons, higher.String()+" empty")
+			}
+		}
+		if len(reasons) == 0 {
+			reasons = append(reasons, "highest priority")
+		}
+	}
+	if task.reason != "" {
+		reasons = append(reasons, task.reason)
+		task.reason = ""
+	}
+
+	now := s.now()
+	s.decisions.add(Decision{
+		Time:      now,
+		TaskID:    task.ID,
+		Lane:      lane,
+		Attempt:   task.attempts + 1,
+		QueueWait: now.Sub(task.enqueuedAt),
+		Reason:    strings.Join(reasons, "; "),
+	})
+}
+
+// DebugDump 输出调度器状态、各通道排队和执行数以及最近的调度决策，用于排查问题
+func (s *Scheduler) DebugDump(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "state: %s\n", s.State())
+	busy, size := s.workerPool.usage()
+	fmt.Fprintf(&b, "workers: %d/%d busy\n", busy, size)
+	for _, lane := range laneOrder {
+		fmt.Fprintf(&b, "lane %s: queued=%d inflight=%d quota=%d\n",
+			lane, s.queues[lane].Len(), s.laneInflight[lane].Load(), s.laneQuota[lane])
+	}
+	if s.decisions != nil {
+		b.WriteString("recent decisions:\n")
+		for _, d := range s.decisions.snapshot() {
+			fmt.Fprintf(&b, "  %s task=%s lane=%s attempt=%d wait=%s reason=%s\n",
+				d.Time.Format(time.RFC3339Nano), d.TaskID, d.Lane, d.Attempt, d.QueueWait, d.Reason)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,53 @@
+package fastscheduler
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestScheduler_DecisionLog(t *testing.T) {
+	scheduler := NewScheduler(1, 10, WithDecisionLog(2), WithManualStart())
+	defer scheduler.Stop()
+
+	noop := func(ctx context.Context) (TaskResult, error) {
+		return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+	}
+	background, err := scheduler.SubmitBackground([]*Task{{ID: "report", Execute: noop}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	normal, err := scheduler.SubmitBatch([]*Task{{ID: "search", Execute: noop}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	interactive, err := scheduler.SubmitInteractive([]*Task{{ID: "click", Execute: noop}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	scheduler.Start()
+	interactive.Wait()
+	normal.Wait()
+	background.Wait()
+
+	// 容量为2，只保留最近两次决策
+	decisions := scheduler.Decisions()
+	if len(decisions) != 2 {
+		t.Fatalf("Expected 2 decisions, got %d", len(decisions))
+	}
+	if decisions[0].TaskID != "search" || decisions[1].TaskID != "report" {
+		t.Errorf("Unexpected decision order: %+v", decisions)
+	}
+	if !strings.Contains(decisions[1].Reason, "interactive empty; normal empty") {
+		t.Errorf("Unexpected reason: %q", decisions[1].Reason)
+	}
+
+	var dump bytes.Buffer
+	if err := scheduler.DebugDump(&dump); err != nil {
+		t.Fatalf("DebugDump failed: %v", err)
+	}
+	if !strings.Contains(dump.String(), "task=report lane=background") {
+		t.Errorf("Dump missing decision:\n%s", dump.String())
+	}
+}
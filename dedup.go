@@ -0,0 +1,70 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// flightGroup 按 IdempotencyKey 合并正在执行的任务
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight 一次正在进行的执行，followers 等待 leader 的结果
+type flight struct {
+	leader    *Task
+	followers []*Task
+}
+
+// join 登记任务，返回该任务是否需要自己执行
+// 相同 key 已有其他任务在执行时，任务加入等待列表，由执行者完成时一并完成
+func (fg *flightGroup) join(task *Task) bool {
+	fg.mu.Lock()
+	defer fg.mu.Unlock()
+
+	f, ok := fg.flights[task.IdempotencyKey]
+	if !ok {
+		if fg.flights == nil {
+			fg.flights = make(map[string]*flight)
+		}
+		fg.flights[task.IdempotencyKey] = &flight{leader: task}
+		return true
+	}
+	if f.leader == task {
+		// 执行者的重试
+		return true
+	}
+	f.followers = append(f.followers, task)
+	return false
+}
+
+// leave 执行者完成时移除 flight，返回等待其结果的任务
+func (fg *flightGroup) leave(task *Task) []*Task {
+	fg.mu.Lock()
+	defer fg.mu.Unlock()
+
+	f, ok := fg.flights[task.IdempotencyKey]
+	if !ok || f.leader != task {
+		return nil
+	}
+	delete(fg.flights, task.IdempotencyKey)
+	return f.followers
+}
+
+// shareResult 将执行者的结果分发给等待的任务
+// 执行者因所在批次被取消而结束时，等待者所在批次仍有效的任务重新入队执行
+func (s *Scheduler) shareResult(task *Task, result TaskResult, err error) {
+	for _, follower := range s.flights.leave(task) {
+		if errors.Is(err, context.Canceled) && follower.group.ctx.Err() == nil {
+			follower.reason = "idempotency leader cancelled"
+			s.requeue(follower)
+			continue
+		}
+		// 结果只计一次成本
+		shared := result
+		shared.Cost = 0
+		s.finishTask(follower, shared, err)
+	}
+}
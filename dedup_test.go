@@ -0,0 +1,96 @@
+package fastscheduler
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTask_IdempotencyKey(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	var calls atomic.Int32
+	resultChan := make(chan TaskResult, 3)
+	var tasks []*Task
+	for i := 0; i < 3; i++ {
+		tasks = append(tasks, &Task{
+			ID:             fmt.Sprintf("profile-%d", i),
+			IdempotencyKey: "GET /users/42",
+			ResultChan:     resultChan,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				calls.Add(1)
+				time.Sleep(50 * time.Millisecond)
+				return TaskResult{HTTPCode: 200, BusinessCode: 1, Data: "user-42"}, nil
+			},
+		})
+	}
+
+	batch, err := scheduler.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+	close(resultChan)
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected duplicate calls to execute once, got %d", calls.Load())
+	}
+	ids := make(map[string]bool)
+	for r := range resultChan {
+		if r.Data != "user-42" {
+			t.Errorf("Unexpected shared result: %+v", r)
+		}
+		ids[r.TaskID] = true
+	}
+	if len(ids) != 3 {
+		t.Errorf("Expected a result for each task, got %v", ids)
+	}
+}
+
+func TestTask_IdempotencyKeyLeaderCancelled(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	var calls atomic.Int32
+	started := make(chan struct{})
+	dup := func(id string) *Task {
+		return &Task{
+			ID:             id,
+			IdempotencyKey: "shared",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				if calls.Add(1) == 1 {
+					close(started)
+				}
+				select {
+				case <-ctx.Done():
+					return TaskResult{}, ctx.Err()
+				case <-time.After(50 * time.Millisecond):
+					return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+				}
+			},
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first, err := scheduler.SubmitBatch([]*Task{dup("first")}, withParentContext(ctx))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	<-started
+	second, err := scheduler.SubmitBatch([]*Task{dup("second")})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	first.Wait()
+	second.Wait()
+	// 执行者被取消后，等待者所在批次仍有效，应重新执行而不是继承取消结果
+	if !second.IsSuccess() {
+		t.Errorf("Follower should re-execute after leader cancellation: %v", second.Err())
+	}
+}
@@ -0,0 +1,106 @@
+package fastscheduler
+
+import "time"
+
+// DeliveryPolicy 结果发送到调用方 ResultChan 的方式
+type DeliveryPolicy int
+
+const (
+	// DeliverBlock 阻塞直到调用方接收(默认)，无缓冲或无人接收的通道会一直占用worker
+	DeliverBlock DeliveryPolicy = iota
+	// DeliverNonBlocking 尽力发送，通道没有空位时立即丢弃结果
+	DeliverNonBlocking
+	// DeliverTimeout 最多等待指定时长，超时后丢弃结果
+	DeliverTimeout
+	// DeliverDetached 在独立的goroutine中阻塞发送，worker 不等待；调用方一直不接收时该goroutine不会退出
+	DeliverDetached
+)
+
+// String 返回策略名称
+func (p DeliveryPolicy) String() string {
+	switch p {
+	case DeliverBlock:
+		return "block"
+	case DeliverNonBlocking:
+		return "non_blocking"
+	case DeliverTimeout:
+		return "timeout"
+	case DeliverDetached:
+		return "detached"
+	default:
+		return "unknown"
+	}
+}
+
+// WithResultDelivery 设置结果发送到 ResultChan 的方式，timeout 仅用于 DeliverTimeout
+// 丢弃的结果(包括通道已关闭和 WithResultBuffer 溢出丢弃的)计入 Stats().DroppedResults，
+// 批次结果不受影响。同时使用 WithResultBuffer 时结果先进入缓冲，该策略不再生效
+func WithResultDelivery(policy DeliveryPolicy, timeout time.Duration) Option {
+	return func(s *Scheduler) {
+		s.delivery = policy
+		s.deliveryTimeout = timeout
+	}
+}
+
+// deliverResult 按发送策略将结果发送到任务的 ResultChan
+func (s *Scheduler) deliverResult(ch chan<- TaskResult, result TaskResult) {
+	if s.resultBuffers != nil {
+		s.resultBuffers.push(ch, result)
+		return
+	}
+	var sent bool
+	switch s.delivery {
+	case DeliverNonBlocking:
+		sent = s.tryDeliver(ch, result, 0)
+	case DeliverTimeout:
+		sent = s.tryDeliver(ch, result, s.deliveryTimeout)
+	case DeliverDetached:
+		go func() {
+			if !deliver(ch, result) {
+				s.droppedResults.Add(1)
+			}
+		}()
+		return
+	default:
+		sent = deliver(ch, result)
+	}
+	if !sent {
+		s.droppedResults.Add(1)
+	}
+}
+
+// deliver 发送结果到调用方的通道，通道已关闭时丢弃结果而不是panic，返回是否发送成功
+func deliver(ch chan<- TaskResult, result TaskResult) (sent bool) {
+	defer func() {
+		if recover() != nil {
+			sent = false
+		}
+	}()
+	ch <- result
+	return true
+}
+
+// tryDeliver 最多等待 timeout 发送结果，timeout 为0时不等待，返回是否发送成功
+func (s *Scheduler) tryDeliver(ch chan<- TaskResult, result TaskResult, timeout time.Duration) (sent bool) {
+	defer func() {
+		if recover() != nil {
+			sent = false
+		}
+	}()
+	if timeout <= 0 {
+		select {
+		case ch <- result:
+			return true
+		default:
+			return false
+		}
+	}
+	timer := s.clock.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case ch <- result:
+		return true
+	case <-timer.C():
+		return false
+	}
+}
@@ -0,0 +1,50 @@
+package fastscheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestScheduler_ResultDelivery(t *testing.T) {
+	ok := func(ctx context.Context) (TaskResult, error) {
+		return TaskResult{HTTPCode: 200}, nil
+	}
+
+	tests := []struct {
+		name    string
+		policy  DeliveryPolicy
+		timeout time.Duration
+		dropped int64
+	}{
+		{"NonBlocking", DeliverNonBlocking, 0, 1},
+		{"Timeout", DeliverTimeout, 10 * time.Millisecond, 1},
+		{"Detached", DeliverDetached, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler := NewScheduler(1, 10, WithResultDelivery(tt.policy, tt.timeout))
+			defer scheduler.Stop()
+
+			// 无缓冲且暂时无人接收的通道不能阻塞worker
+			unread := make(chan TaskResult)
+			batch, err := scheduler.SubmitBatch([]*Task{{ID: "a", Execute: ok, ResultChan: unread}})
+			if err != nil {
+				t.Fatalf("SubmitBatch failed: %v", err)
+			}
+			select {
+			case <-batch.group.done:
+			case <-time.After(time.Second):
+				t.Fatal("Worker blocked on unread ResultChan")
+			}
+			if got := scheduler.Stats().DroppedResults; got != tt.dropped {
+				t.Errorf("DroppedResults = %d, want %d", got, tt.dropped)
+			}
+			if tt.policy == DeliverDetached {
+				if r := <-unread; r.TaskID != "a" {
+					t.Errorf("Unexpected detached result %+v", r)
+				}
+			}
+		})
+	}
+}
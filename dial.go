@@ -0,0 +1,73 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"time"
+)
+
+// Stagger 错开批次内任务的启动时间：第 i 个任务在提交 i*d 后才入队
+// 排在前面的任务在此之前成功时，后面的任务不会执行
+func Stagger(d time.Duration) BatchOption {
+	return func(g *taskGroup) {
+		g.stagger = d
+	}
+}
+
+// DialRace 以 Happy Eyeballs 的方式依次间隔 stagger 向 addrs 发起连接，返回最先建立的连接
+// addrs 按优先顺序排列(例如IPv6与IPv4交替)，d 为nil时使用默认的 net.Dialer。
+// 其余连接尝试被取消，取消前已建立的多余连接会被关闭；全部失败时返回各地址错误的合并，
+// 获胜结果的 Data 被结果处理函数替换为非连接类型时返回 *ResultTypeError
+func (s *Scheduler) DialRace(ctx context.Context, d *net.Dialer, network string, addrs []string, stagger time.Duration) (net.Conn, error) {
+	if d == nil {
+		d = &net.Dialer{}
+	}
+	tasks := make([]*Task, len(addrs))
+	for i, addr := range addrs {
+		tasks[i] = &Task{
+			ID: addr,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				conn, err := d.DialContext(ctx, network, addr)
+				if err != nil {
+					return TaskResult{}, err
+				}
+				return TaskResult{HTTPCode: 200, BusinessCode: 0, Data: conn}, nil
+			},
+		}
+	}
+
+	batch, err := s.SubmitBatch(tasks, Stagger(stagger), withParentContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for result := range batch.ResultsChan() {
+		if !isSuccess(result) {
+			errs = append(errs, result.Err)
+			continue
+		}
+		// 在后台回收落败的连接
+		go closeDialLosers(batch.ResultsChan())
+		conn, ok := result.Data.(net.Conn)
+		if !ok {
+			return nil, &ResultTypeError{TaskID: result.TaskID, Data: result.Data, Want: reflect.TypeFor[net.Conn]()}
+		}
+		return conn, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return nil, errors.Join(append([]error{ErrAllFailed}, errs...)...)
+}
+
+// closeDialLosers 关闭获胜连接之后才建立的连接
+func closeDialLosers(results <-chan TaskResult) {
+	for result := range results {
+		if conn, ok := result.Data.(net.Conn); ok {
+			conn.Close()
+		}
+	}
+}
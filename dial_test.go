@@ -0,0 +1,86 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestScheduler_DialRace(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	listen := func() net.Listener {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Listen failed: %v", err)
+		}
+		return ln
+	}
+	primary, backup := listen(), listen()
+	defer primary.Close()
+	defer backup.Close()
+
+	// 已关闭的端口，连接会被拒绝
+	closed := listen()
+	refused := closed.Addr().String()
+	closed.Close()
+
+	conn, err := scheduler.DialRace(context.Background(), nil, "tcp",
+		[]string{refused, primary.Addr().String(), backup.Addr().String()}, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("DialRace failed: %v", err)
+	}
+	defer conn.Close()
+	if got := conn.RemoteAddr().String(); got != primary.Addr().String() {
+		t.Errorf("Expected connection to primary, got %s", got)
+	}
+
+	// 主地址在错开时间内连接成功，备用地址不应被连接
+	backup.(*net.TCPListener).SetDeadline(time.Now().Add(300 * time.Millisecond))
+	if c, err := backup.Accept(); err == nil {
+		c.Close()
+		t.Error("Backup address should not be dialed")
+	}
+}
+
+func TestScheduler_DialRaceAllFailed(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	if _, err := scheduler.DialRace(context.Background(), nil, "tcp", []string{addr, addr}, time.Millisecond); !errors.Is(err, ErrAllFailed) {
+		t.Errorf("Expected ErrAllFailed, got %v", err)
+	}
+}
+
+func TestScheduler_DialRaceUnexpectedResultType(t *testing.T) {
+	scheduler := NewScheduler(5, 10, WithResultTransformers(func(r TaskResult) TaskResult {
+		if conn, ok := r.Data.(net.Conn); ok {
+			conn.Close()
+			r.Data = conn.RemoteAddr().String()
+		}
+		return r
+	}))
+	defer scheduler.Stop()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer ln.Close()
+
+	_, err = scheduler.DialRace(context.Background(), nil, "tcp", []string{ln.Addr().String()}, 0)
+	var typeErr *ResultTypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("Expected *ResultTypeError, got %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+package fastscheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 持久化队列记录的任务状态
+const (
+	stateQueued  byte = 'q'
+	stateRunning byte = 'r'
+)
+
+// DurableQueueConfig 持久化队列配置
+type DurableQueueConfig struct {
+	// Prefix 任务记录的键前缀，多个队列共享一个 Store 时用于区分，默认 "queue/"
+	Prefix string
+	// Capacity 最大排队任务数，0表示不限制
+	Capacity int
+	// Marshal 序列化任务，默认 MarshalTask，只能持久化由 SerializableTask 创建的任务
+	Marshal func(t *Task) ([]byte, error)
+	// Unmarshal 由 Marshal 的结果重建任务，用于重启后恢复的任务，默认 UnmarshalTask
+	Unmarshal func(data []byte) (*Task, error)
+	// Codec 设置后任务确认时按该编码持久化其最终结果，可通过 Result 读取，重启后仍然保留；
+	// nil 表示不保存结果。结果记录不会自动删除，读取后调用 DeleteResult 清理
+	Codec Codec
+}
+
+// DurableQueue 基于 Store 的持久化队列，实现 Queue、Acker 和 ResultAcker
+// 任务放入时写入日志，取出时记录为执行中，确认后删除；
+// 重新打开时未确认的任务(包括崩溃时执行中的任务)按放入顺序再次投递，提供至少一次语义
+type DurableQueue struct {
+	store Store
+	cfg   DurableQueueConfig
+
+	mu      sync.Mutex
+	seq     uint64
+	pending []durableEntry
+	// inflight 已取出未确认的任务对应的键
+	inflight map[*Task]string
+	// recovered 打开时处于执行中状态的任务数
+	recovered int
+}
+
+// durableEntry 排队中的任务，task 为nil时在取出时由 payload 重建
+type durableEntry struct {
+	key     string
+	task    *Task
+	payload []byte
+}
+
+// NewDurableQueue 在 store 上打开持久化队列，重放之前未确认的任务
+func NewDurableQueue(store Store, cfg DurableQueueConfig) (*DurableQueue, error) {
+	if cfg.Marshal == nil {
+		cfg.Marshal = MarshalTask
+	}
+	if cfg.Unmarshal == nil {
+		cfg.Unmarshal = UnmarshalTask
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = "queue/"
+	}
+	q := &DurableQueue{
+		store:    store,
+		cfg:      cfg,
+		inflight: make(map[*Task]string),
+	}
+
+	// 键由定长序号组成，字典序即放入顺序
+	err := store.Scan(func(key string, value []byte) error {
+		seq, ok := strings.CutPrefix(key, cfg.Prefix)
+		if !ok || len(value) == 0 {
+			return nil
+		}
+		n, err := strconv.ParseUint(seq, 10, 64)
+		if err != nil {
+			return nil
+		}
+		q.seq = max(q.seq, n)
+		if value[0] == stateRunning {
+			q.recovered++
+		}
+		q.pending = append(q.pending, durableEntry{key: key, payload: value[1:]})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Push 写入日志后放入任务，达到 Capacity 时返回 ErrQueueFull
+// 已取出未确认的任务再次放入(例如重试)时，替换其原有记录
+func (q *DurableQueue) Push(t *Task) error {
+	payload, err := q.cfg.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("fastscheduler: marshal task %s: %w", t.ID, err)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.cfg.Capacity > 0 && len(q.pending) >= q.cfg.Capacity {
+		return ErrQueueFull
+	}
+	q.seq++
+	key := q.cfg.Prefix + fmt.Sprintf("%020d", q.seq)
+	if err := q.store.Put(key, append([]byte{stateQueued}, payload...)); err != nil {
+		return err
+	}
+	if previous, ok := q.inflight[t]; ok {
+		// 新记录写入后再删除旧记录，删除失败只会导致重启后重复投递
+		_ = q.store.Delete(previous)
+		delete(q.inflight, t)
+	}
+	q.pending = append(q.pending, durableEntry{key: key, task: t, payload: payload})
+	return nil
+}
+
+// Pop 取出最早放入的任务并记录为执行中
+func (q *DurableQueue) Pop() (*Task, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.pending) > 0 {
+		e := q.pending[0]
+		q.pending[0] = durableEntry{}
+		q.pending = q.pending[1:]
+
+		t := e.task
+		if t == nil {
+			var err error
+			if t, err = q.cfg.Unmarshal(e.payload); err != nil {
+				// 无法重建的任务永远无法执行，删除以免每次重启都重放
+				_ = q.store.Delete(e.key)
+				continue
+			}
+		}
+		// 状态只用于排查，写入失败不影响重放
+		_ = q.store.Put(e.key, append([]byte{stateRunning}, e.payload...))
+		q.inflight[t] = e.key
+		return t, true
+	}
+	return nil, false
+}
+
+// Len 返回排队中的任务数
+func (q *DurableQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// Ack 确认任务已完成，删除其记录
+func (q *DurableQueue) Ack(t *Task) error {
+	q.mu.Lock()
+	key, ok := q.inflight[t]
+	delete(q.inflight, t)
+	q.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return q.store.Delete(key)
+}
+
+// AckResult 实现 ResultAcker：设置了 Codec 时先持久化任务的最终结果，再删除任务记录
+// Data 无法编码时只保存结果的其余字段并标记 Truncated
+func (q *DurableQueue) AckResult(t *Task, result TaskResult) error {
+	if q.cfg.Codec != nil {
+		if err := q.putResult(result); err != nil {
+			return err
+		}
+	}
+	return q.Ack(t)
+}
+
+// putResult 以任务ID为键保存编码后的结果
+func (q *DurableQueue) putResult(result TaskResult) error {
+	w, err := EncodeResult(q.cfg.Codec, result)
+	if err != nil {
+		stripped := result
+		stripped.Data = nil
+		stripped.Truncated = true
+		if w, err = EncodeResult(q.cfg.Codec, stripped); err != nil {
+			return err
+		}
+	}
+	value, err := json.Marshal(w)
+	if err != nil {
+		return err
+	}
+	return q.store.Put(q.resultKey(result.TaskID), value)
+}
+
+// Result 读取 AckResult 保存的任务结果，Data 按 Codec 解码到 data 指向的值，data 为nil时不解码
+// 没有该任务的结果时返回 ErrNotFound，保存时的编码与当前 Codec 不同时返回 ErrCodecMismatch
+func (q *DurableQueue) Result(taskID string, data interface{}) (TaskResult, error) {
+	if q.cfg.Codec == nil {
+		return TaskResult{}, ErrNotFound
+	}
+	value, err := q.store.Get(q.resultKey(taskID))
+	if err != nil {
+		return TaskResult{}, err
+	}
+	var w WireResult
+	if err := json.Unmarshal(value, &w); err != nil {
+		return TaskResult{}, fmt.Errorf("fastscheduler: decode stored result %s: %w", taskID, err)
+	}
+	return DecodeResult(q.cfg.Codec, w, data)
+}
+
+// DeleteResult 删除保存的任务结果
+func (q *DurableQueue) DeleteResult(taskID string) error {
+	return q.store.Delete(q.resultKey(taskID))
+}
+
+// resultKey 结果记录的键，不是序号，打开队列时不会被当作任务重放
+func (q *DurableQueue) resultKey(taskID string) string {
+	return q.cfg.Prefix + "results/" + taskID
+}
+
+// Recovered 返回打开队列时处于执行中状态(上次运行时崩溃中断)并被重新投递的任务数
+func (q *DurableQueue) Recovered() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.recovered
+}
@@ -0,0 +1,193 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mapStore 内存中的 Store，跨队列实例共享以模拟重启
+type mapStore struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func (s *mapStore) Put(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = append([]byte(nil), value...)
+	return nil
+}
+
+func (s *mapStore) Get(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.data[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return v, nil
+}
+
+func (s *mapStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	return nil
+}
+
+func (s *mapStore) Scan(fn func(key string, value []byte) error) error {
+	s.mu.Lock()
+	keys := make([]string, 0, len(s.data))
+	for k := range s.data {
+		keys = append(keys, k)
+	}
+	s.mu.Unlock()
+	sort.Strings(keys)
+	for _, k := range keys {
+		v, err := s.Get(k)
+		if err != nil {
+			continue
+		}
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *mapStore) Close() error { return nil }
+
+func TestDurableQueue_ReplaysAfterCrash(t *testing.T) {
+	store := &mapStore{data: make(map[string][]byte)}
+	executed := make(chan string, 2)
+	cfg := DurableQueueConfig{
+		Marshal: func(t *Task) ([]byte, error) { return []byte(t.ID), nil },
+		Unmarshal: func(data []byte) (*Task, error) {
+			id := string(data)
+			return &Task{
+				ID: id,
+				Execute: func(ctx context.Context) (TaskResult, error) {
+					executed <- id
+					return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+				},
+			}, nil
+		},
+	}
+
+	// 上一次运行：两个任务入队，其中一个执行到一半时进程崩溃
+	before, err := NewDurableQueue(store, cfg)
+	if err != nil {
+		t.Fatalf("NewDurableQueue failed: %v", err)
+	}
+	for _, id := range []string{"running", "queued"} {
+		if err := before.Push(&Task{ID: id}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+	}
+	if _, ok := before.Pop(); !ok {
+		t.Fatal("Pop returned false")
+	}
+
+	after, err := NewDurableQueue(store, cfg)
+	if err != nil {
+		t.Fatalf("NewDurableQueue failed: %v", err)
+	}
+	if got := after.Recovered(); got != 1 {
+		t.Errorf("Expected 1 recovered task, got %d", got)
+	}
+	scheduler := NewScheduler(1, 10, WithQueue(func(lane Lane, capacity int) Queue {
+		if lane == LaneNormal {
+			return after
+		}
+		return NewFIFOQueue(capacity)
+	}))
+	defer scheduler.Stop()
+
+	var order []string
+	for len(order) < 2 {
+		select {
+		case id := <-executed:
+			order = append(order, id)
+		case <-time.After(time.Second):
+			t.Fatalf("Replayed tasks not executed, got %v", order)
+		}
+	}
+	if order[0] != "running" || order[1] != "queued" {
+		t.Errorf("Expected replay in submission order, got %v", order)
+	}
+
+	// 完成的任务被确认，日志清空
+	scheduler.Wait()
+	deadline := time.Now().Add(time.Second)
+	for {
+		store.mu.Lock()
+		n := len(store.data)
+		store.mu.Unlock()
+		if n == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected journal to be empty, %d entries left", n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestDurableQueue_PersistsResults(t *testing.T) {
+	store := &mapStore{data: make(map[string][]byte)}
+	cfg := DurableQueueConfig{
+		Marshal: func(t *Task) ([]byte, error) { return []byte(t.ID), nil },
+		Codec:   GobCodec,
+	}
+	q, err := NewDurableQueue(store, cfg)
+	if err != nil {
+		t.Fatalf("NewDurableQueue failed: %v", err)
+	}
+	scheduler := NewScheduler(1, 10, WithQueue(func(lane Lane, capacity int) Queue {
+		if lane == LaneNormal {
+			return q
+		}
+		return NewFIFOQueue(capacity)
+	}))
+	defer scheduler.Stop()
+
+	batch, err := scheduler.SubmitBatch([]*Task{{
+		ID: "quote",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: 200, BusinessCode: 0, Data: price{Provider: "a", Cents: 199}}, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	// 重新打开后结果仍可读取，且结果记录不会被当作任务重放
+	reopened, err := NewDurableQueue(store, cfg)
+	if err != nil {
+		t.Fatalf("NewDurableQueue failed: %v", err)
+	}
+	if n := reopened.Len(); n != 0 {
+		t.Errorf("Expected no replayed tasks, got %d", n)
+	}
+	var data price
+	waitFor(t, func() bool {
+		_, err := reopened.Result("quote", &data)
+		return err == nil
+	})
+	result, err := reopened.Result("quote", &data)
+	if err != nil || result.HTTPCode != 200 || result.Data != (price{Provider: "a", Cents: 199}) {
+		t.Errorf("Unexpected stored result %+v, %v", result, err)
+	}
+
+	if err := reopened.DeleteResult("quote"); err != nil {
+		t.Fatalf("DeleteResult failed: %v", err)
+	}
+	if _, err := reopened.Result("quote", nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+}
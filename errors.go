@@ -0,0 +1,145 @@
+package fastscheduler
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrAllFailed 表示批次中没有任何任务成功
+var ErrAllFailed = errors.New("fastscheduler: all tasks failed")
+
+// ErrSchedulerStopped 表示调度器已停止，不再接受任务
+var ErrSchedulerStopped = errors.New("fastscheduler: scheduler stopped")
+
+// ErrQueueTTLExpired 表示任务排队时间超过 QueueTTL，未被执行
+var ErrQueueTTLExpired = errors.New("fastscheduler: queue ttl expired")
+
+// ErrQueueFull 表示队列已满，任务按 OverflowReject 策略被拒绝
+var ErrQueueFull = errors.New("fastscheduler: queue full")
+
+// ErrTaskDropped 表示任务按 OverflowDropOldest 策略被丢弃
+var ErrTaskDropped = errors.New("fastscheduler: task dropped by overflow policy")
+
+// ErrBudgetExceeded 表示批次成本预算已用完，任务未执行或被取消
+var ErrBudgetExceeded = errors.New("fastscheduler: batch cost budget exceeded")
+
+// ErrRetryBudgetExhausted 表示批次的重试预算已用完，任务失败后没有再重试
+var ErrRetryBudgetExhausted = errors.New("fastscheduler: batch retry budget exhausted")
+
+// ErrCircuitOpen 表示任务的 Key 处于熔断状态，任务未执行
+var ErrCircuitOpen = errors.New("fastscheduler: circuit open")
+
+// ErrNoLeaseScope 表示 Lease 不是在调度器执行的任务中调用
+var ErrNoLeaseScope = errors.New("fastscheduler: lease requested outside a running task")
+
+// ErrUnknownLease 表示没有通过 WithLeasePool 注册该名称的租约池
+var ErrUnknownLease = errors.New("fastscheduler: unknown lease pool")
+
+// ErrUnknownBulkhead 表示任务的 Bulkhead 没有通过 WithBulkhead 注册
+var ErrUnknownBulkhead = errors.New("fastscheduler: unknown bulkhead")
+
+// ErrNotFound 表示 Store 中不存在该键
+var ErrNotFound = errors.New("fastscheduler: not found")
+
+// ErrBatchClosed 表示批次已关闭，不能再加入任务
+var ErrBatchClosed = errors.New("fastscheduler: batch closed")
+
+// ErrNoRequestBatch 表示上下文中没有 Middleware 创建的请求批次
+var ErrNoRequestBatch = errors.New("fastscheduler: no request batch in context")
+
+// ErrDuplicateBatchID 表示 WithBatchID 指定的ID与未完成的批次重复
+var ErrDuplicateBatchID = errors.New("fastscheduler: duplicate batch id")
+
+// ErrTaskPanicked 表示任务执行时发生 panic，panic 的值附在错误信息中
+var ErrTaskPanicked = errors.New("fastscheduler: task panicked")
+
+// ErrTaskNotFound 表示 CancelTask 指定的批次不存在、已完成或其中没有该任务
+var ErrTaskNotFound = errors.New("fastscheduler: task not found")
+
+// ErrUnknownTaskType 表示任务类型没有通过 RegisterTaskType 注册
+var ErrUnknownTaskType = errors.New("fastscheduler: unknown task type")
+
+// ErrNotSerializable 表示任务不是由 SerializableTask 创建，无法序列化
+var ErrNotSerializable = errors.New("fastscheduler: task is not serializable")
+
+// ErrChaosInjected 表示 WithChaos 注入的失败，任务没有执行
+var ErrChaosInjected = errors.New("fastscheduler: chaos injected failure")
+
+// ErrChaosDropped 表示 WithChaos 丢弃了任务的结果，任务已经执行
+var ErrChaosDropped = errors.New("fastscheduler: chaos dropped result")
+
+// ErrNotEnoughSuccesses 表示 WithTopK 的批次完成时成功的任务数不足 k
+var ErrNotEnoughSuccesses = errors.New("fastscheduler: not enough successful results")
+
+// TaskError 描述单个任务的失败原因
+type TaskError struct {
+	TaskID       string
+	HTTPCode     int
+	BusinessCode int
+	// Err 任务返回的错误，任务仅以状态码表示失败时为nil
+	Err error
+}
+
+// Error 实现 error 接口
+func (e *TaskError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("task %s: %v", e.TaskID, e.Err)
+	}
+	return fmt.Sprintf("task %s: http code %d, business code %d", e.TaskID, e.HTTPCode, e.BusinessCode)
+}
+
+// Unwrap 返回任务的原始错误，支持 errors.Is/As
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// ResultTypeError 表示任务结果的 Data 不是期望的类型，通常是结果处理函数替换了 Data
+type ResultTypeError struct {
+	TaskID string
+	// Data 任务结果中的实际数据
+	Data interface{}
+	// Want 期望的类型
+	Want reflect.Type
+}
+
+// Error 实现 error 接口
+func (e *ResultTypeError) Error() string {
+	return fmt.Sprintf("fastscheduler: task %s result data is %T, want %v", e.TaskID, e.Data, e.Want)
+}
+
+// resultData 将结果的 Data 转换为 T，T 为接口类型且 Data 为nil时返回零值
+func resultData[T any](result TaskResult) (T, error) {
+	if v, ok := result.Data.(T); ok {
+		return v, nil
+	}
+	var zero T
+	want := reflect.TypeFor[T]()
+	if result.Data == nil && want.Kind() == reflect.Interface {
+		return zero, nil
+	}
+	return zero, &ResultTypeError{TaskID: result.TaskID, Data: result.Data, Want: want}
+}
+
+// recordFailure 记录失败任务的错误
+func (g *taskGroup) recordFailure(result TaskResult) {
+	g.errMu.Lock()
+	defer g.errMu.Unlock()
+	g.errs = append(g.errs, &TaskError{
+		TaskID:       result.TaskID,
+		HTTPCode:     result.HTTPCode,
+		BusinessCode: result.BusinessCode,
+		Err:          result.Err,
+	})
+}
+
+// Err 返回批次失败的原因，批次有任务成功时返回nil
+// 否则使用 errors.Join 合并每个失败任务的 *TaskError；批次未完成时仅包含已失败的任务
+func (b *Batch) Err() error {
+	if b.IsSuccess() {
+		return nil
+	}
+	b.group.errMu.Lock()
+	defer b.group.errMu.Unlock()
+	return errors.Join(b.group.errs...)
+}
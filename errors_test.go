@@ -0,0 +1,72 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBatch_Err(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	errTimeout := errors.New("upstream timeout")
+	batch, err := scheduler.SubmitBatch([]*Task{
+		{
+			ID: "err-task",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{}, errTimeout
+			},
+		},
+		{
+			ID: "code-task",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 503}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	err = batch.Err()
+	if !errors.Is(err, errTimeout) {
+		t.Errorf("Expected joined error to match upstream timeout, got %v", err)
+	}
+
+	var taskErr *TaskError
+	if !errors.As(err, &taskErr) {
+		t.Fatalf("Expected *TaskError in %v", err)
+	}
+
+	var codes int
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		if te := e.(*TaskError); te.TaskID == "code-task" && te.HTTPCode == 503 {
+			codes++
+		}
+	}
+	if codes != 1 {
+		t.Errorf("Expected status-code failure of code-task in %v", err)
+	}
+}
+
+func TestBatch_ErrNilOnSuccess(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	batch, err := scheduler.SubmitBatch([]*Task{{
+		ID: "ok",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	if err := batch.Err(); err != nil {
+		t.Errorf("Expected nil error for successful batch, got %v", err)
+	}
+}
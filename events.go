@@ -0,0 +1,165 @@
+package fastscheduler
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EventType 调度器事件类型
+type EventType int
+
+const (
+	// EventTaskStarted 任务开始执行，每次重试都会产生一次
+	EventTaskStarted EventType = iota + 1
+	// EventTaskFinished 任务结束(成功、失败、取消或未执行就结束)，Result 为最终结果
+	EventTaskFinished
+	// EventBatchCompleted 批次所有任务完成，Outcome 为批次最终状态
+	EventBatchCompleted
+	// EventQueueFull 任务入队时所在通道的队列已满
+	EventQueueFull
+	// EventWorkerPanic 任务执行时发生 panic，Panic 为 panic 的值
+	EventWorkerPanic
+	// EventSlowTask 任务单次执行超过 WithWatchdog 的阈值，Runtime 为已运行时长，Stack 为执行该任务的goroutine栈
+	EventSlowTask
+	// EventTaskPreempted 任务的执行被更高优先级的任务抢占，任务已重新入队
+	EventTaskPreempted
+)
+
+// String 返回事件类型名称
+func (et EventType) String() string {
+	switch et {
+	case EventTaskStarted:
+		return "task_started"
+	case EventTaskFinished:
+		return "task_finished"
+	case EventBatchCompleted:
+		return "batch_completed"
+	case EventQueueFull:
+		return "queue_full"
+	case EventWorkerPanic:
+		return "worker_panic"
+	case EventSlowTask:
+		return "slow_task"
+	case EventTaskPreempted:
+		return "task_preempted"
+	default:
+		return "unknown"
+	}
+}
+
+// Event 调度器事件，未涉及的字段为零值
+type Event struct {
+	Type EventType
+	// Time 事件发生的时间
+	Time    time.Time
+	BatchID string
+	// TaskID 批次事件为空
+	TaskID string
+	Lane   Lane
+	// Attempt 任务第几次执行
+	Attempt int
+	// Result 任务结果，仅 EventTaskFinished
+	Result TaskResult
+	// Outcome 批次最终状态，仅 EventBatchCompleted
+	Outcome Outcome
+	// Panic panic 的值，仅 EventWorkerPanic
+	Panic interface{}
+	// Runtime 已运行时长，Stack 执行任务的goroutine栈，仅 EventSlowTask
+	Runtime time.Duration
+	Stack   string
+}
+
+// EventFilter 选择订阅的事件，返回 true 的事件才会发送，nil 表示订阅全部事件
+type EventFilter func(Event) bool
+
+// EventTypes 返回只订阅指定类型事件的过滤器
+func EventTypes(types ...EventType) EventFilter {
+	return func(e Event) bool {
+		for _, t := range types {
+			if e.Type == t {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// eventBufferSize 每个订阅通道的缓冲大小
+const eventBufferSize = 256
+
+// subscriber 一个事件订阅
+type subscriber struct {
+	ch     chan Event
+	filter EventFilter
+}
+
+// eventBus 事件订阅者
+type eventBus struct {
+	mu   sync.RWMutex
+	subs []*subscriber
+	// count 订阅者数量，为0时发布事件没有额外开销
+	count atomic.Int32
+}
+
+// Subscribe 订阅调度器事件，返回的通道带有缓冲
+// 事件在调度器内部同步产生，订阅方消费过慢、缓冲已满时新事件被丢弃而不会阻塞worker；
+// 不再需要时调用 Unsubscribe 释放订阅
+func (s *Scheduler) Subscribe(filter EventFilter) <-chan Event {
+	sub := &subscriber{ch: make(chan Event, eventBufferSize), filter: filter}
+	s.events.mu.Lock()
+	s.events.subs = append(s.events.subs, sub)
+	s.events.count.Add(1)
+	s.events.mu.Unlock()
+	return sub.ch
+}
+
+// Unsubscribe 取消订阅并关闭通道，通道不是由 Subscribe 返回或已取消时不做任何操作
+func (s *Scheduler) Unsubscribe(ch <-chan Event) {
+	s.events.mu.Lock()
+	defer s.events.mu.Unlock()
+	for i, sub := range s.events.subs {
+		if sub.ch == ch {
+			s.events.subs = append(s.events.subs[:i], s.events.subs[i+1:]...)
+			s.events.count.Add(-1)
+			close(sub.ch)
+			return
+		}
+	}
+}
+
+// subscribed 返回是否有订阅者
+func (s *Scheduler) subscribed() bool {
+	return s.events.count.Load() > 0
+}
+
+// publish 将事件发送给匹配的订阅者
+func (s *Scheduler) publish(e Event) {
+	e.Time = s.now()
+	s.events.mu.RLock()
+	defer s.events.mu.RUnlock()
+	for _, sub := range s.events.subs {
+		if sub.filter != nil && !sub.filter(e) {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+		}
+	}
+}
+
+// publishTask 发布任务事件
+func (s *Scheduler) publishTask(et EventType, t *Task, fill func(*Event)) {
+	if !s.subscribed() {
+		return
+	}
+	e := Event{Type: et, TaskID: t.ID, Lane: t.lane, Attempt: t.attempts}
+	if t.group != nil {
+		e.BatchID = t.group.id
+	}
+	if fill != nil {
+		fill(&e)
+	}
+	s.publish(e)
+}
@@ -0,0 +1,83 @@
+package fastscheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestScheduler_Subscribe(t *testing.T) {
+	scheduler := NewScheduler(2, 10)
+	defer scheduler.Stop()
+
+	all := scheduler.Subscribe(nil)
+	completed := scheduler.Subscribe(EventTypes(EventBatchCompleted))
+
+	batch, err := scheduler.SubmitBatch([]*Task{
+		{
+			ID: "broken",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				panic("boom")
+			},
+		},
+	}, WithBatchID("events"))
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	batch.Wait()
+
+	select {
+	case e := <-completed:
+		if e.BatchID != "events" || e.Outcome != OutcomeAllFailed {
+			t.Errorf("Unexpected batch event %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for batch completed event")
+	}
+
+	var types []EventType
+	for len(types) < 4 {
+		select {
+		case e := <-all:
+			if e.BatchID != "events" {
+				t.Errorf("Unexpected batch ID in %+v", e)
+			}
+			types = append(types, e.Type)
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out, got events %v", types)
+		}
+	}
+	want := []EventType{EventTaskStarted, EventWorkerPanic, EventTaskFinished, EventBatchCompleted}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("Event %d = %s, want %s", i, types[i], want[i])
+		}
+	}
+
+	scheduler.Unsubscribe(all)
+	if _, ok := <-all; ok {
+		t.Error("Expected channel to be closed after Unsubscribe")
+	}
+}
+
+func TestScheduler_SubscribeQueueFull(t *testing.T) {
+	scheduler := NewScheduler(1, 1, WithManualStart(), WithOverflowPolicy(OverflowReject))
+	events := scheduler.Subscribe(EventTypes(EventQueueFull))
+
+	noop := func(ctx context.Context) (TaskResult, error) {
+		return TaskResult{HTTPCode: 200}, nil
+	}
+	if _, err := scheduler.SubmitBatch([]*Task{{ID: "a", Execute: noop}, {ID: "b", Execute: noop}}); err != ErrQueueFull {
+		t.Fatalf("Expected ErrQueueFull, got %v", err)
+	}
+	select {
+	case e := <-events:
+		if e.TaskID != "b" || e.Lane != LaneNormal {
+			t.Errorf("Unexpected queue full event %+v", e)
+		}
+	default:
+		t.Fatal("Expected a queue full event")
+	}
+	scheduler.Start()
+	scheduler.Stop()
+}
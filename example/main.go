@@ -45,7 +45,11 @@ func main() {
 	}
 
 	// 提交任务批次
-	batch := scheduler.SubmitBatch(tasks)
+	batch, err := scheduler.SubmitBatch(tasks)
+	if err != nil {
+		fmt.Println("提交任务失败:", err)
+		return
+	}
 
 	// 等待批次完成
 	batch.Wait()
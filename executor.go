@@ -0,0 +1,42 @@
+package fastscheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+)
+
+// Executor 执行任务，调度器默认在本进程中调用 Task.Execute
+// 通过 WithExecutor 可以把任务交给远程worker等其他执行方式，
+// ctx 为任务的组上下文派生的上下文，批次取消(例如其他任务已成功)时 ctx 被取消，实现应随之中止执行
+type Executor interface {
+	Execute(ctx context.Context, t *Task) (TaskResult, error)
+}
+
+// WithExecutor 设置任务的执行方式，对冲的每次尝试同样经过 e
+func WithExecutor(e Executor) Option {
+	return func(s *Scheduler) {
+		s.executor = e
+	}
+}
+
+// execute 使用配置的 Executor 执行任务，执行中的 panic 转换为 ErrTaskPanicked 错误
+func (s *Scheduler) execute(ctx context.Context, t *Task) (result TaskResult, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			s.logTask(slog.LevelError, "task panicked", t, slog.Any("panic", v), slog.String("stack", string(debug.Stack())))
+			s.publishTask(EventWorkerPanic, t, func(e *Event) { e.Panic = v })
+			result, err = TaskResult{}, fmt.Errorf("%w: %v", ErrTaskPanicked, v)
+		}
+	}()
+	s.withLabels(ctx, t, func(ctx context.Context) {
+		result, err = s.withChaos(ctx, t, func() (TaskResult, error) {
+			if s.executor != nil {
+				return s.executor.Execute(ctx, t)
+			}
+			return t.Execute(ctx)
+		})
+	})
+	return result, err
+}
@@ -0,0 +1,217 @@
+package fastscheduler
+
+import (
+	"math"
+	"sync"
+)
+
+// WithBatchFairness 在同一通道内按批次轮流调度，而不是先进先出：
+// 先提交的大批次不会让之后提交的小批次一直等待，各批次按 WithBatchWeight 的权重分配执行机会。
+// 同时启用 WithTenantPolicy 时先按租户分配，再在租户内按批次轮流。
+// 启用后各通道使用内置的公平队列，WithQueue 不生效
+func WithBatchFairness() Option {
+	return func(s *Scheduler) {
+		s.batchFair = true
+	}
+}
+
+// WithBatchWeight 设置批次在 WithBatchFairness 调度中的权重，默认1
+// 例如延迟敏感的批次设置更高的权重，在与大批次竞争时获得更多执行机会
+func WithBatchWeight(w int) BatchOption {
+	return func(g *taskGroup) {
+		g.weight = w
+	}
+}
+
+// strideSet 步进调度的一组流
+// 每个流有一个通行值，出队时选择通行值最小的可调度流，出队后其通行值增加 1/权重；
+// 重新开始排队的流从当前虚拟时间起算，不会积攒空闲期间的份额
+type strideSet[K comparable, F any] struct {
+	vtime float64
+	seq   uint64
+	flows map[K]*strideFlow[F]
+}
+
+// strideFlow 步进调度中的一个流
+type strideFlow[F any] struct {
+	pass float64
+	// seq 流加入的顺序，通行值相同时先加入的优先，保证调度顺序确定
+	seq uint64
+	val F
+}
+
+// get 返回 k 对应的流，不存在时用 newVal 创建
+func (s *strideSet[K, F]) get(k K, newVal func() F) *strideFlow[F] {
+	if s.flows == nil {
+		s.flows = make(map[K]*strideFlow[F])
+	}
+	f, ok := s.flows[k]
+	if !ok {
+		s.seq++
+		f = &strideFlow[F]{pass: s.vtime, seq: s.seq, val: newVal()}
+		s.flows[k] = f
+	}
+	return f
+}
+
+// activate 流从空变为非空时调用，通行值不低于当前虚拟时间
+func (s *strideSet[K, F]) activate(f *strideFlow[F]) {
+	f.pass = max(f.pass, s.vtime)
+}
+
+// pick 选择通行值最小的可调度流；empty 判断流是否为空，
+// 为空且没有未偿还份额(通行值不超过虚拟时间)的流被移除
+func (s *strideSet[K, F]) pick(empty func(F) bool, eligible func(K, F) bool) (K, *strideFlow[F], bool) {
+	var key K
+	var next *strideFlow[F]
+	for k, f := range s.flows {
+		if empty(f.val) {
+			if f.pass <= s.vtime {
+				delete(s.flows, k)
+			}
+			continue
+		}
+		if !eligible(k, f.val) {
+			continue
+		}
+		if next == nil || f.pass < next.pass || (f.pass == next.pass && f.seq < next.seq) {
+			key, next = k, f
+		}
+	}
+	return key, next, next != nil
+}
+
+// advance 流出队一次后推进虚拟时间和流的通行值
+func (s *strideSet[K, F]) advance(f *strideFlow[F], weight int) {
+	s.vtime = f.pass
+	f.pass += 1 / float64(max(weight, 1))
+}
+
+// fairQueue 按租户和批次加权公平出队的队列
+type fairQueue struct {
+	mu       sync.Mutex
+	capacity int
+	size     int
+	tenants  strideSet[string, *tenantFlow]
+	// state 多租户策略，未启用时所有任务属于同一个租户
+	state *tenantState
+	// byBatch 租户内是否按批次轮流
+	byBatch bool
+	// policy 租户或批次内部的出队顺序，nil表示先进先出
+	policy SchedulingPolicy
+}
+
+// tenantFlow 单个租户排队中的任务
+type tenantFlow struct {
+	queued int
+	// tasks 不按批次轮流时的队列
+	tasks taskList
+	// batches 按批次轮流时各批次的先进先出队列
+	batches strideSet[*taskGroup, *batchFlow]
+}
+
+// batchFlow 单个批次排队中的任务
+type batchFlow struct {
+	tasks taskList
+}
+
+// newFairQueue 创建容量为 capacity 的公平队列
+func newFairQueue(capacity int, state *tenantState, byBatch bool, policy SchedulingPolicy) *fairQueue {
+	return &fairQueue{capacity: max(capacity, 1), state: state, byBatch: byBatch, policy: policy}
+}
+
+// tenantOf 返回任务在队列中所属的租户
+func (q *fairQueue) tenantOf(t *Task) string {
+	if q.state == nil {
+		return ""
+	}
+	return t.Tenant
+}
+
+func (q *fairQueue) Push(t *Task) error {
+	var quota TenantQuota
+	if q.state != nil {
+		quota = q.state.quota(t.Tenant)
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.size >= q.capacity {
+		return ErrQueueFull
+	}
+	tf := q.tenants.get(q.tenantOf(t), func() *tenantFlow {
+		return &tenantFlow{tasks: taskList{policy: q.policy}}
+	})
+	if quota.QueueShare > 0 && tf.val.queued >= max(int(math.Ceil(quota.QueueShare*float64(q.capacity))), 1) {
+		return ErrQueueFull
+	}
+	if tf.val.queued == 0 {
+		q.tenants.activate(tf)
+	}
+	tf.val.queued++
+	q.size++
+
+	if !q.byBatch {
+		tf.val.tasks.push(t)
+		return nil
+	}
+	bf := tf.val.batches.get(t.group, func() *batchFlow {
+		return &batchFlow{tasks: taskList{policy: q.policy}}
+	})
+	if bf.val.tasks.len() == 0 {
+		tf.val.batches.activate(bf)
+	}
+	bf.val.tasks.push(t)
+	return nil
+}
+
+func (q *fairQueue) Pop() (*Task, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	tenant, tf, ok := q.tenants.pick(
+		func(f *tenantFlow) bool { return f.queued == 0 },
+		func(tenant string, _ *tenantFlow) bool { return q.state == nil || !q.state.full(tenant) },
+	)
+	if !ok {
+		return nil, false
+	}
+	weight := 1
+	if q.state != nil {
+		weight = q.state.quota(tenant).Weight
+	}
+	q.tenants.advance(tf, weight)
+	tf.val.queued--
+	q.size--
+
+	if !q.byBatch {
+		return tf.val.tasks.pop(), true
+	}
+	batches := &tf.val.batches
+	group, bf, _ := batches.pick(
+		func(f *batchFlow) bool { return f.tasks.len() == 0 },
+		func(*taskGroup, *batchFlow) bool { return true },
+	)
+	weight = 1
+	if group != nil {
+		weight = group.weight
+	}
+	batches.advance(bf, weight)
+	return bf.val.tasks.pop(), true
+}
+
+func (q *fairQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.size
+}
+
+// tenantLen 返回租户排队中的任务数
+func (q *fairQueue) tenantLen(tenant string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if tf, ok := q.tenants.flows[tenant]; ok {
+		return tf.val.queued
+	}
+	return 0
+}
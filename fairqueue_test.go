@@ -0,0 +1,100 @@
+package fastscheduler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestScheduler_BatchFairness(t *testing.T) {
+	scheduler := NewScheduler(1, 100, WithBatchFairness())
+	defer scheduler.Stop()
+
+	var mu sync.Mutex
+	var order []string
+	makeTasks := func(name string, n int) []*Task {
+		var tasks []*Task
+		for i := 0; i < n; i++ {
+			tasks = append(tasks, &Task{
+				ID: fmt.Sprintf("%s-%d", name, i),
+				Execute: func(ctx context.Context) (TaskResult, error) {
+					mu.Lock()
+					order = append(order, name)
+					mu.Unlock()
+					return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+				},
+			})
+		}
+		return tasks
+	}
+
+	// 大批次先提交，小批次和加权的批次随后提交
+	scheduler.Pause()
+	var batches []*Batch
+	for _, b := range []struct {
+		name   string
+		n      int
+		weight int
+	}{{"big", 20, 1}, {"small", 2, 1}, {"urgent", 4, 2}} {
+		batch, err := scheduler.SubmitBatch(makeTasks(b.name, b.n), WithBatchWeight(b.weight))
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		batches = append(batches, batch)
+	}
+	scheduler.Resume()
+	for _, batch := range batches {
+		batch.Wait()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"big", "small", "urgent", "urgent", "big", "small", "urgent", "urgent", "big", "big"}
+	if !slices.Equal(order[:len(want)], want) {
+		t.Errorf("Expected interleaved dispatch %v, got %v", want, order[:len(want)])
+	}
+}
+
+func TestScheduler_BatchFairnessWithinTenant(t *testing.T) {
+	scheduler := NewScheduler(1, 100, WithBatchFairness(), WithTenantPolicy(TenantPolicy{}))
+	defer scheduler.Stop()
+
+	var mu sync.Mutex
+	var order []string
+	submit := func(tenant, name string, n int) *Batch {
+		var tasks []*Task
+		for i := 0; i < n; i++ {
+			tasks = append(tasks, &Task{
+				ID: fmt.Sprintf("%s-%d", name, i),
+				Execute: func(ctx context.Context) (TaskResult, error) {
+					mu.Lock()
+					order = append(order, name)
+					mu.Unlock()
+					return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+				},
+			})
+		}
+		batch, err := scheduler.SubmitBatch(tasks, WithTenant(tenant))
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		return batch
+	}
+
+	// 租户 a 的两个批次共享 a 的份额，与租户 b 轮流
+	scheduler.Pause()
+	batches := []*Batch{submit("a", "a1", 4), submit("a", "a2", 4), submit("b", "b1", 4)}
+	scheduler.Resume()
+	for _, batch := range batches {
+		batch.Wait()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"a1", "b1", "a2", "b1", "a1", "b1", "a2", "b1"}
+	if !slices.Equal(order[:len(want)], want) {
+		t.Errorf("Expected tenant then batch interleaving %v, got %v", want, order[:len(want)])
+	}
+}
@@ -0,0 +1,73 @@
+package fastscheduler
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// WithFallback 注册备用任务，只有批次中所有主任务都失败时才执行，
+// 例如所有廉价的缓存或副本都未命中时才请求昂贵的权威接口。
+// 主任务有成功的结果、批次被取消或超时时备用任务不执行；备用任务执行时其结果与主任务一样
+// 出现在结果流中，成功时成为批次的获胜结果。
+// 备用任务在派生自批次父上下文的新上下文中运行，不受组上下文影响；
+// Batch.Cancel、父上下文结束或调度器的基础上下文结束时仍会取消它
+func WithFallback(task *Task) BatchOption {
+	return func(g *taskGroup) {
+		g.fallback = &fallbackState{task: task}
+	}
+}
+
+// fallbackState 批次的备用任务
+type fallbackState struct {
+	task    *Task
+	started atomic.Bool
+	// cancel 和 stopBase 释放备用任务的上下文，在批次完成时调用
+	cancel   context.CancelFunc
+	stopBase func() bool
+}
+
+// release 释放备用任务的上下文
+func (fb *fallbackState) release() {
+	if fb.cancel != nil {
+		fb.cancel()
+	}
+	if fb.stopBase != nil {
+		fb.stopBase()
+	}
+}
+
+// runFallback 所有主任务都失败后提交备用任务，返回是否已提交
+// 在最后一个主任务的 complete 或开放批次的 Close 中调用，此时批次的 wg 计数尚未释放
+func (g *taskGroup) runFallback() bool {
+	fb := g.fallback
+	if fb == nil || g.ctx.Err() != nil || g.pendingStopped() {
+		return false
+	}
+	// WithBestResult 的批次完成前 success 尚未设置，按已完成任务的计数判断
+	g.errMu.Lock()
+	succeeded := g.tally.succeeded > 0
+	g.errMu.Unlock()
+	if succeeded {
+		return false
+	}
+	if !fb.started.CompareAndSwap(false, true) {
+		return false
+	}
+	t := new(Task)
+	*t = *fb.task
+	g.total.Add(1)
+	g.remaining.Add(1)
+	g.wg.Add(1)
+	g.attach(t, g.lane, g.sched.now())
+	// 任务链上下文派生自父上下文且随 Batch.Cancel 取消，但不关联 baseCtx，这里单独关联
+	t.ctx, fb.cancel = context.WithCancel(g.chainContext())
+	if base := g.sched.baseCtx; base.Done() != nil {
+		fb.stopBase = context.AfterFunc(base, fb.cancel)
+	}
+	t.reason = "all primary tasks failed"
+	g.sched.logBatch(slog.LevelInfo, "batch fallback started", g, slog.String("task_id", t.ID))
+	// 同步模式下备用任务可能在此直接执行，其完成时会再次调用 runFallback
+	g.sched.requeue(t)
+	return true
+}
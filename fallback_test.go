@@ -0,0 +1,119 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBatch_Fallback(t *testing.T) {
+	var fallbackRuns atomic.Int32
+	canonical := &Task{
+		ID: "canonical",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			fallbackRuns.Add(1)
+			if ctx.Err() != nil {
+				return TaskResult{}, ctx.Err()
+			}
+			return TaskResult{HTTPCode: 200, Data: "canonical"}, nil
+		},
+	}
+	replica := func(id string, code int) *Task {
+		return &Task{ID: id, Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: code}, nil
+		}}
+	}
+
+	for _, mode := range []struct {
+		name string
+		opts []Option
+	}{
+		{"Async", nil},
+		{"Synchronous", []Option{WithSynchronousMode()}},
+	} {
+		t.Run(mode.name, func(t *testing.T) {
+			scheduler := NewScheduler(2, 10, mode.opts...)
+			defer scheduler.Stop()
+
+			// 所有副本都未命中，执行备用任务
+			fallbackRuns.Store(0)
+			batch, err := scheduler.SubmitBatch([]*Task{replica("cache", 404), replica("replica", 503)}, WithFallback(canonical))
+			if err != nil {
+				t.Fatalf("Submit failed: %v", err)
+			}
+			batch.Wait()
+			if !batch.IsSuccess() || fallbackRuns.Load() != 1 {
+				t.Fatalf("Expected fallback to win, success=%v runs=%d", batch.IsSuccess(), fallbackRuns.Load())
+			}
+			var ids []string
+			for r := range batch.ResultsChan() {
+				ids = append(ids, r.TaskID)
+			}
+			if len(ids) != 3 || ids[2] != "canonical" {
+				t.Errorf("Unexpected results %v", ids)
+			}
+
+			// 有副本命中时不执行备用任务
+			fallbackRuns.Store(0)
+			batch, err = scheduler.SubmitBatch([]*Task{replica("cache", 200)}, WithFallback(canonical))
+			if err != nil {
+				t.Fatalf("Submit failed: %v", err)
+			}
+			batch.Wait()
+			if fallbackRuns.Load() != 0 || batch.Summary().Total != 1 {
+				t.Errorf("Expected fallback not to run, runs=%d total=%d", fallbackRuns.Load(), batch.Summary().Total)
+			}
+		})
+	}
+}
+
+func TestBatch_FallbackRunsUnderOwnContext(t *testing.T) {
+	scheduler := NewScheduler(2, 10)
+	defer scheduler.Stop()
+
+	started := make(chan struct{})
+	released := make(chan struct{})
+	checked := make(chan struct{})
+	var groupCancelErr error
+	canonical := &Task{
+		ID: "canonical",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			close(started)
+			<-released
+			groupCancelErr = ctx.Err()
+			close(checked)
+			<-ctx.Done()
+			return TaskResult{}, ctx.Err()
+		},
+	}
+	batch, err := scheduler.SubmitBatch([]*Task{{
+		ID: "cache",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: 404}, nil
+		},
+	}}, WithFallback(canonical))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	// 组上下文结束(例如预算耗尽)不影响已开始的备用任务，Batch.Cancel 仍会取消它
+	<-started
+	batch.group.cancel()
+	close(released)
+	<-checked
+	batch.Cancel()
+	batch.Wait()
+	var fallback TaskResult
+	for r := range batch.ResultsChan() {
+		if r.TaskID == "canonical" {
+			fallback = r
+		}
+	}
+	if groupCancelErr != nil {
+		t.Errorf("Expected fallback context to outlive the group context, got %v", groupCancelErr)
+	}
+	if !errors.Is(fallback.Err, context.Canceled) {
+		t.Errorf("Expected fallback to be cancelled by Batch.Cancel, got %+v", fallback)
+	}
+}
@@ -0,0 +1,171 @@
+// Package fastschedulertest 提供测试依赖 fastscheduler 的代码时使用的工具
+package fastschedulertest
+
+import (
+	"sync"
+	"time"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+// FakeClock 手动推进的 fastscheduler.Clock
+// 通过 WithClock 注入调度器后，执行超时、重试等待、错开启动等只在调用 Advance 时推进，
+// 测试不需要真实的 sleep：
+//
+//	clock := fastschedulertest.NewFakeClock(time.Now())
+//	scheduler := fastscheduler.NewScheduler(4, 100, fastscheduler.WithClock(clock))
+//	batch, _ := scheduler.SubmitBatch(tasks)
+//	clock.BlockUntil(1) // 等待调度器创建超时或重试定时器
+//	clock.Advance(5 * time.Second)
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeTimer
+}
+
+// NewFakeClock 创建当前时间为 now 的 FakeClock
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now 实现 fastscheduler.Clock
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer 实现 fastscheduler.Clock
+func (c *FakeClock) NewTimer(d time.Duration) fastscheduler.Timer {
+	t := &fakeTimer{clock: c, c: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+// NewTicker 实现 fastscheduler.Clock
+func (c *FakeClock) NewTicker(d time.Duration) fastscheduler.Ticker {
+	if d <= 0 {
+		panic("fastschedulertest: non-positive interval for NewTicker")
+	}
+	t := &fakeTimer{clock: c, c: make(chan time.Time, 1), period: d}
+	t.Reset(d)
+	return fakeTicker{t}
+}
+
+// Advance 将时间推进 d，依次触发到期的定时器
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	end := c.now.Add(d)
+	for {
+		next := c.nextLocked(end)
+		if next == nil {
+			break
+		}
+		c.now = next.at
+		c.fireLocked(next)
+	}
+	c.now = end
+}
+
+// BlockUntil 阻塞直到有 n 个未触发的定时器(包括 ticker)
+// 调度器在后台goroutine中创建定时器，Advance 前调用以确保定时器已经创建
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+// Waiters 返回未触发的定时器数量
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// nextLocked 返回不晚于 end 的最早到期定时器
+func (c *FakeClock) nextLocked(end time.Time) *fakeTimer {
+	var next *fakeTimer
+	for _, t := range c.waiters {
+		if !t.at.After(end) && (next == nil || t.at.Before(next.at)) {
+			next = t
+		}
+	}
+	return next
+}
+
+// fireLocked 触发定时器，ticker 重新排到下一个周期
+// 与 time.Ticker 相同，接收方没有及时读取时丢弃本次触发
+func (c *FakeClock) fireLocked(t *fakeTimer) {
+	select {
+	case t.c <- c.now:
+	default:
+	}
+	if t.period > 0 {
+		t.at = t.at.Add(t.period)
+		return
+	}
+	c.removeLocked(t)
+}
+
+// removeLocked 移除定时器，返回定时器是否仍在等待
+func (c *FakeClock) removeLocked(t *fakeTimer) bool {
+	for i, w := range c.waiters {
+		if w == t {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// fakeTimer FakeClock 上的定时器，period 大于0时为 ticker
+type fakeTimer struct {
+	clock  *FakeClock
+	c      chan time.Time
+	at     time.Time
+	period time.Duration
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.removeLocked(t)
+}
+
+// Reset 重新计时，d<=0 的定时器立即触发
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	active := c.removeLocked(t)
+	t.at = c.now.Add(d)
+	if d <= 0 && t.period == 0 {
+		select {
+		case t.c <- c.now:
+		default:
+		}
+		return active
+	}
+	c.waiters = append(c.waiters, t)
+	c.cond.Broadcast()
+	return active
+}
+
+// fakeTicker 以 ticker 的方法集暴露 fakeTimer
+type fakeTicker struct {
+	*fakeTimer
+}
+
+func (t fakeTicker) Stop() {
+	t.fakeTimer.Stop()
+}
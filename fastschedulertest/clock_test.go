@@ -0,0 +1,105 @@
+package fastschedulertest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+func TestFakeClock_TaskTimeout(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	scheduler := fastscheduler.NewScheduler(1, 10, fastscheduler.WithClock(clock))
+	defer scheduler.Stop()
+
+	batch, err := scheduler.SubmitBatch([]*fastscheduler.Task{{
+		ID:      "hung",
+		Timeout: time.Hour,
+		Execute: func(ctx context.Context) (fastscheduler.TaskResult, error) {
+			<-ctx.Done()
+			return fastscheduler.TaskResult{}, ctx.Err()
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour - time.Second)
+	select {
+	case <-batch.Done():
+		t.Fatal("Task timed out before the fake clock reached its timeout")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	clock.Advance(time.Second)
+	batch.Wait()
+	result := <-batch.ResultsChan()
+	if !errors.Is(result.Err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", result.Err)
+	}
+	if result.Metrics.Duration != time.Hour {
+		t.Errorf("Duration = %s, want 1h", result.Metrics.Duration)
+	}
+	if got := scheduler.Stats().TaskTimeouts; got != 1 {
+		t.Errorf("TaskTimeouts = %d, want 1", got)
+	}
+}
+
+func TestFakeClock_RetryDelay(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	scheduler := fastscheduler.NewScheduler(1, 10, fastscheduler.WithClock(clock))
+	defer scheduler.Stop()
+
+	attempts := 0
+	batch, err := scheduler.SubmitBatch([]*fastscheduler.Task{{
+		ID:    "flaky",
+		Retry: &fastscheduler.RetryPolicy{MaxAttempts: 2, Delay: time.Minute},
+		Execute: func(ctx context.Context) (fastscheduler.TaskResult, error) {
+			attempts++
+			if attempts == 1 {
+				return fastscheduler.TaskResult{HTTPCode: 503}, nil
+			}
+			return fastscheduler.TaskResult{HTTPCode: 200}, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	// 重试等待的定时器创建后，只有推进时钟才会再次执行
+	clock.BlockUntil(1)
+	select {
+	case <-batch.Done():
+		t.Fatal("Task retried before the fake clock advanced")
+	case <-time.After(20 * time.Millisecond):
+	}
+	clock.Advance(time.Minute)
+	batch.Wait()
+	if !batch.IsSuccess() || attempts != 2 {
+		t.Errorf("Expected success on second attempt, got %v after %d attempts", batch.Err(), attempts)
+	}
+}
+
+func TestFakeClock_Ticker(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	ticker := clock.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	clock.Advance(2500 * time.Millisecond)
+	// 接收方没有读取时，与 time.Ticker 相同只保留一次触发
+	if got := <-ticker.C(); !got.Equal(time.Unix(1, 0)) {
+		t.Errorf("First tick at %v, want %v", got, time.Unix(1, 0))
+	}
+	select {
+	case got := <-ticker.C():
+		t.Errorf("Unexpected buffered tick at %v", got)
+	default:
+	}
+	clock.Advance(500 * time.Millisecond)
+	if got := <-ticker.C(); !got.Equal(time.Unix(3, 0)) {
+		t.Errorf("Next tick at %v, want %v", got, time.Unix(3, 0))
+	}
+}
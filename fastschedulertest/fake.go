@@ -0,0 +1,62 @@
+package fastschedulertest
+
+import (
+	"context"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+// Fake 同步执行的 fastscheduler.SchedulerInterface，内部使用 WithSynchronousMode 的调度器
+// 任务在提交方goroutine中按提交顺序逐个执行，某个任务成功后其余任务不再执行；
+// 提交方法在批次完成(包括任务回调)后才返回，调用方拿到 Batch 或 Future 时结果已经确定，
+// 测试不需要等待或 sleep。任务的 ResultChan 需要有足够的缓冲，否则提交方法会阻塞在结果投递上
+type Fake struct {
+	s *fastscheduler.Scheduler
+}
+
+var _ fastscheduler.SchedulerInterface = (*Fake)(nil)
+
+// NewFake 创建 Fake，opts 传给内部的调度器，例如 WithClock
+func NewFake(opts ...fastscheduler.Option) *Fake {
+	opts = append([]fastscheduler.Option{fastscheduler.WithSynchronousMode()}, opts...)
+	return &Fake{s: fastscheduler.NewScheduler(1, 1, opts...)}
+}
+
+// Submit 执行单个任务，返回已完成的 Future
+func (f *Fake) Submit(task *fastscheduler.Task, opts ...fastscheduler.BatchOption) (*fastscheduler.Future, error) {
+	future, err := f.s.Submit(task, opts...)
+	if err != nil {
+		return future, err
+	}
+	<-future.Done()
+	return future, nil
+}
+
+// SubmitBatch 执行一批任务，返回已完成的批次
+func (f *Fake) SubmitBatch(tasks []*fastscheduler.Task, opts ...fastscheduler.BatchOption) (*fastscheduler.Batch, error) {
+	return wait(f.s.SubmitBatch(tasks, opts...))
+}
+
+// SubmitBatchContext 执行一批任务，批次上下文继承 ctx，返回已完成的批次
+func (f *Fake) SubmitBatchContext(ctx context.Context, tasks []*fastscheduler.Task, opts ...fastscheduler.BatchOption) (*fastscheduler.Batch, error) {
+	return wait(f.s.SubmitBatchContext(ctx, tasks, opts...))
+}
+
+// Stop 停止 Fake，之后的提交返回 ErrSchedulerStopped
+func (f *Fake) Stop() {
+	f.s.Stop()
+}
+
+// Stats 返回统计快照
+func (f *Fake) Stats() fastscheduler.Stats {
+	return f.s.Stats()
+}
+
+// wait 等待批次完成
+func wait(batch *fastscheduler.Batch, err error) (*fastscheduler.Batch, error) {
+	if err != nil {
+		return batch, err
+	}
+	batch.Wait()
+	return batch, nil
+}
@@ -0,0 +1,102 @@
+package fastschedulertest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+// search 依赖调度器的被测代码，返回第一个成功的副本
+func search(s fastscheduler.SchedulerInterface, replicas []string) (string, error) {
+	tasks := make([]*fastscheduler.Task, len(replicas))
+	for i, r := range replicas {
+		r := r
+		tasks[i] = &fastscheduler.Task{
+			ID: r,
+			Execute: func(ctx context.Context) (fastscheduler.TaskResult, error) {
+				if r == "down" {
+					return fastscheduler.TaskResult{HTTPCode: 503}, nil
+				}
+				return fastscheduler.TaskResult{HTTPCode: 200, Data: r}, nil
+			},
+		}
+	}
+	batch, err := s.SubmitBatch(tasks)
+	if err != nil {
+		return "", err
+	}
+	result, err := batch.WaitFirstSuccess(context.Background())
+	if err != nil {
+		return "", err
+	}
+	return result.Data.(string), nil
+}
+
+func TestFake_Synchronous(t *testing.T) {
+	fake := NewFake()
+	defer fake.Stop()
+
+	var order []string
+	record := func(id string, code int) *fastscheduler.Task {
+		return &fastscheduler.Task{
+			ID: id,
+			Execute: func(ctx context.Context) (fastscheduler.TaskResult, error) {
+				if ctx.Err() != nil {
+					return fastscheduler.TaskResult{}, ctx.Err()
+				}
+				order = append(order, id)
+				return fastscheduler.TaskResult{HTTPCode: code}, nil
+			},
+		}
+	}
+	batch, err := fake.SubmitBatch([]*fastscheduler.Task{record("a", 500), record("b", 200), record("c", 200)})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	// 返回时批次已经完成，按顺序执行到第一个成功的任务为止
+	select {
+	case <-batch.Done():
+	default:
+		t.Fatal("Expected batch to be complete when SubmitBatch returns")
+	}
+	if !batch.IsSuccess() || len(order) != 2 || order[0] != "a" || order[1] != "b" {
+		t.Errorf("Unexpected execution order %v, success=%v", order, batch.IsSuccess())
+	}
+
+	future, err := fake.Submit(record("single", 200))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	select {
+	case <-future.Done():
+	default:
+		t.Fatal("Expected future to be complete when Submit returns")
+	}
+}
+
+func TestMock_RecordsCalls(t *testing.T) {
+	mock := NewMock(nil)
+	defer mock.Stop()
+
+	got, err := search(mock, []string{"down", "replica-1"})
+	if err != nil || got != "replica-1" {
+		t.Fatalf("search = %q, %v", got, err)
+	}
+	calls := mock.Calls()
+	if len(calls) != 1 || calls[0].Method != "SubmitBatch" || len(calls[0].Tasks) != 2 {
+		t.Fatalf("Unexpected calls %+v", calls)
+	}
+	if tasks := mock.Tasks(); tasks[0].ID != "down" || tasks[1].ID != "replica-1" {
+		t.Errorf("Unexpected tasks %v, %v", tasks[0].ID, tasks[1].ID)
+	}
+
+	mock.FailWith(fastscheduler.ErrSchedulerStopped)
+	if _, err := search(mock, []string{"replica-1"}); !errors.Is(err, fastscheduler.ErrSchedulerStopped) {
+		t.Errorf("Expected ErrSchedulerStopped, got %v", err)
+	}
+	if len(mock.Calls()) != 2 {
+		t.Errorf("Expected failed call to be recorded, got %d calls", len(mock.Calls()))
+	}
+}
@@ -0,0 +1,120 @@
+package fastschedulertest
+
+import (
+	"context"
+	"sync"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+// Call 一次提交调用
+type Call struct {
+	// Method 调用的方法名: Submit、SubmitBatch 或 SubmitBatchContext
+	Method string
+	// Tasks 提交的任务，Submit 为单个任务
+	Tasks []*fastscheduler.Task
+	// Opts 批次选项
+	Opts []fastscheduler.BatchOption
+	// Context SubmitBatchContext 的 ctx，其他方法为 nil
+	Context context.Context
+}
+
+// Mock 记录调用的 fastscheduler.SchedulerInterface，实际执行交给 Next
+// 用于断言被测代码提交了哪些任务，或通过 FailWith 模拟调度器拒绝提交
+type Mock struct {
+	// Next 实际处理提交的调度器
+	Next fastscheduler.SchedulerInterface
+
+	mu      sync.Mutex
+	calls   []Call
+	err     error
+	stopped int
+}
+
+var _ fastscheduler.SchedulerInterface = (*Mock)(nil)
+
+// NewMock 创建 Mock，next 为 nil 时使用 NewFake()
+func NewMock(next fastscheduler.SchedulerInterface) *Mock {
+	if next == nil {
+		next = NewFake()
+	}
+	return &Mock{Next: next}
+}
+
+// FailWith 之后的提交调用不再交给 Next，直接返回 err；err 为 nil 时恢复正常
+// 调用仍会被记录
+func (m *Mock) FailWith(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+}
+
+// Calls 返回已记录的提交调用
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// Tasks 返回所有调用提交的任务，按提交顺序排列
+func (m *Mock) Tasks() []*fastscheduler.Task {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var tasks []*fastscheduler.Task
+	for _, c := range m.calls {
+		tasks = append(tasks, c.Tasks...)
+	}
+	return tasks
+}
+
+// StopCount 返回 Stop 被调用的次数
+func (m *Mock) StopCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stopped
+}
+
+// Submit 记录调用并交给 Next
+func (m *Mock) Submit(task *fastscheduler.Task, opts ...fastscheduler.BatchOption) (*fastscheduler.Future, error) {
+	if err := m.record(Call{Method: "Submit", Tasks: []*fastscheduler.Task{task}, Opts: opts}); err != nil {
+		return nil, err
+	}
+	return m.Next.Submit(task, opts...)
+}
+
+// SubmitBatch 记录调用并交给 Next
+func (m *Mock) SubmitBatch(tasks []*fastscheduler.Task, opts ...fastscheduler.BatchOption) (*fastscheduler.Batch, error) {
+	if err := m.record(Call{Method: "SubmitBatch", Tasks: tasks, Opts: opts}); err != nil {
+		return nil, err
+	}
+	return m.Next.SubmitBatch(tasks, opts...)
+}
+
+// SubmitBatchContext 记录调用并交给 Next
+func (m *Mock) SubmitBatchContext(ctx context.Context, tasks []*fastscheduler.Task, opts ...fastscheduler.BatchOption) (*fastscheduler.Batch, error) {
+	if err := m.record(Call{Method: "SubmitBatchContext", Tasks: tasks, Opts: opts, Context: ctx}); err != nil {
+		return nil, err
+	}
+	return m.Next.SubmitBatchContext(ctx, tasks, opts...)
+}
+
+// Stop 记录调用并停止 Next
+func (m *Mock) Stop() {
+	m.mu.Lock()
+	m.stopped++
+	m.mu.Unlock()
+	m.Next.Stop()
+}
+
+// Stats 返回 Next 的统计快照
+func (m *Mock) Stats() fastscheduler.Stats {
+	return m.Next.Stats()
+}
+
+// record 记录调用，返回 FailWith 设置的错误
+func (m *Mock) record(c Call) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, c)
+	return m.err
+}
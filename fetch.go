@@ -0,0 +1,100 @@
+package fastscheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// minFetchSweep 缓存条目达到该数量后才开始清理过期条目
+const minFetchSweep = 64
+
+// fetchCache 保存 GetOrFetch 的缓存结果和进行中的请求
+type fetchCache struct {
+	mu      sync.Mutex
+	entries map[string]fetchEntry
+	calls   map[string]*fetchCall
+	// sweepAt 写入后条目数达到该值时清理所有过期条目，
+	// 之后调整为剩余条目数的两倍，缓存大小不超过存活条目的两倍
+	sweepAt int
+}
+
+// fetchEntry 缓存的成功结果
+type fetchEntry struct {
+	result    TaskResult
+	expiresAt time.Time
+}
+
+// fetchCall 进行中的竞速请求，同一个key的并发调用共享结果
+type fetchCall struct {
+	done   chan struct{}
+	result TaskResult
+	err    error
+}
+
+// GetOrFetch 返回 key 对应的缓存结果；缓存不存在或已过期时竞速执行 tasks，
+// 缓存第一个成功的结果 ttl 时长。同一个 key 的并发调用只会发起一次竞速。
+// 适用于配置、特性开关等多副本拉取的场景；失败的结果不会被缓存
+func (s *Scheduler) GetOrFetch(ctx context.Context, key string, tasks []*Task, ttl time.Duration) (TaskResult, error) {
+	c := &s.fetches
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok {
+		if s.now().Before(entry.expiresAt) {
+			c.mu.Unlock()
+			return entry.result, nil
+		}
+		delete(c.entries, key)
+	}
+
+	call, inflight := c.calls[key]
+	if !inflight {
+		if c.calls == nil {
+			c.calls = make(map[string]*fetchCall)
+			c.entries = make(map[string]fetchEntry)
+		}
+		call = &fetchCall{done: make(chan struct{})}
+		c.calls[key] = call
+	}
+	c.mu.Unlock()
+
+	if !inflight {
+		// 共享的竞速不受单个调用方 ctx 的影响
+		go s.fetch(key, call, tasks, ttl)
+	}
+
+	select {
+	case <-call.done:
+		return call.result, call.err
+	case <-ctx.Done():
+		return TaskResult{}, ctx.Err()
+	}
+}
+
+// fetch 执行竞速并写入缓存
+func (s *Scheduler) fetch(key string, call *fetchCall, tasks []*Task, ttl time.Duration) {
+	call.result, call.err = s.raceTasks(context.Background(), tasks)
+
+	c := &s.fetches
+	c.mu.Lock()
+	delete(c.calls, key)
+	if call.err == nil && ttl > 0 {
+		now := s.now()
+		c.entries[key] = fetchEntry{result: call.result, expiresAt: now.Add(ttl)}
+		c.sweep(now)
+	}
+	c.mu.Unlock()
+	close(call.done)
+}
+
+// sweep 条目数达到阈值时删除过期条目，只读取一次的 key 过期后也会被回收，调用方需持有 mu
+func (c *fetchCache) sweep(now time.Time) {
+	if len(c.entries) < max(c.sweepAt, minFetchSweep) {
+		return
+	}
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	c.sweepAt = 2 * len(c.entries)
+}
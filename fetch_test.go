@@ -0,0 +1,78 @@
+package fastscheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_GetOrFetch(t *testing.T) {
+	scheduler := NewScheduler(5, 20)
+	defer scheduler.Stop()
+
+	var fetches atomic.Int32
+	tasks := []*Task{{
+		ID: "replica",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			fetches.Add(1)
+			time.Sleep(20 * time.Millisecond)
+			return TaskResult{HTTPCode: 200, BusinessCode: 0, Data: "flags-v1"}, nil
+		},
+	}}
+
+	// 并发调用共享一次拉取
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := scheduler.GetOrFetch(context.Background(), "flags", tasks, time.Minute)
+			if err != nil || res.Data != "flags-v1" {
+				t.Errorf("Unexpected fetch result: %+v, %v", res, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 缓存命中不再拉取
+	if _, err := scheduler.GetOrFetch(context.Background(), "flags", tasks, time.Minute); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fetches.Load() != 1 {
+		t.Errorf("Expected a single fetch, got %d", fetches.Load())
+	}
+}
+
+func TestScheduler_GetOrFetchEvictsExpiredEntries(t *testing.T) {
+	scheduler := NewScheduler(5, 20)
+	defer scheduler.Stop()
+
+	fetch := func(key string) {
+		tasks := []*Task{{
+			ID: "replica",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 200, BusinessCode: 0, Data: key}, nil
+			},
+		}}
+		if _, err := scheduler.GetOrFetch(context.Background(), key, tasks, time.Millisecond); err != nil {
+			t.Fatalf("GetOrFetch failed: %v", err)
+		}
+	}
+	// 每个 key 只读取一次，过期后不会再被访问
+	for i := 0; i < 500; i++ {
+		fetch(fmt.Sprint("key-", i))
+		if i%50 == 49 {
+			time.Sleep(2 * time.Millisecond)
+		}
+	}
+
+	scheduler.fetches.mu.Lock()
+	n := len(scheduler.fetches.entries)
+	scheduler.fetches.mu.Unlock()
+	if n > 2*minFetchSweep {
+		t.Errorf("Expected expired entries to be evicted, cache holds %d", n)
+	}
+}
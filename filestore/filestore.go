@@ -0,0 +1,316 @@
+// Package filestore 提供基于单个追加日志文件的 fastscheduler.Store 实现，不依赖外部数据库
+// 每次写入追加一条带校验和的记录并同步到磁盘，打开时重放日志重建索引；
+// 崩溃导致的末尾残缺记录会被截断，重放后日志被压缩为只包含存活键值。
+// 运行期间日志超过存活键值的两倍且不小于压缩阈值时自动压缩，长期运行的进程日志大小保持有界
+package filestore
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+// 记录类型
+const (
+	opPut    byte = 1
+	opDelete byte = 2
+)
+
+// headerSize 记录头：类型(1) + 键长度(4) + 值长度(4)
+const headerSize = 9
+
+// maxRecordSize 单条记录键值的最大长度，超过时视为损坏的记录头
+const maxRecordSize = 1 << 30
+
+// defaultCompactThreshold 默认的自动压缩阈值
+const defaultCompactThreshold = 4 << 20
+
+// ErrClosed 表示存储已关闭
+var ErrClosed = errors.New("filestore: store closed")
+
+// Store 基于追加日志文件的存储，数据全部保存在内存索引中
+type Store struct {
+	path      string
+	threshold int64
+
+	mu   sync.RWMutex
+	file *os.File
+	data map[string][]byte
+	// size 日志文件的大小，live 存活键值按记录编码后的大小
+	size int64
+	live int64
+	// compactAt 日志达到该大小时自动压缩
+	compactAt int64
+}
+
+// Option 存储选项
+type Option func(*Store)
+
+// WithCompactThreshold 设置自动压缩的最小日志大小，默认4MB；日志还需超过存活键值的两倍才会压缩
+// n<=0 时关闭自动压缩，只在打开时和调用 Compact 时压缩
+func WithCompactThreshold(n int64) Option {
+	return func(s *Store) {
+		s.threshold = n
+	}
+}
+
+// Open 打开或创建 path 处的日志文件
+func Open(path string, opts ...Option) (*Store, error) {
+	s := &Store{path: path, threshold: defaultCompactThreshold, data: make(map[string][]byte)}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if err := s.replay(); err != nil {
+		return nil, err
+	}
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// replay 读取日志重建索引，遇到残缺或校验失败的记录时停止
+func (s *Store) replay() error {
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		op, key, value, err := readRecord(r)
+		if err != nil {
+			// io.EOF 为正常结束，其他错误视为崩溃时写了一半的记录，之后的内容被丢弃
+			return nil
+		}
+		switch op {
+		case opPut:
+			old, ok := s.data[key]
+			s.live += liveSize(key, value, true) - liveSize(key, old, ok)
+			s.data[key] = value
+		case opDelete:
+			old, ok := s.data[key]
+			s.live -= liveSize(key, old, ok)
+			delete(s.data, key)
+		}
+	}
+}
+
+// compact 将存活的键值写入新文件并替换旧日志，调用方需持有写锁或在打开期间调用
+// 失败时继续使用原日志，在日志再增长一倍后重试自动压缩
+func (s *Store) compact() error {
+	if err := s.rewrite(); err != nil {
+		s.compactAt = 2 * s.size
+		return err
+	}
+	s.size = s.live
+	s.compactAt = max(s.threshold, 2*s.live)
+	return nil
+}
+
+// rewrite 写入新文件并替换旧日志，成功后改为追加新文件
+func (s *Store) rewrite() error {
+	tmp := s.path + ".compact"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for key, value := range s.data {
+		if _, err := w.Write(encodeRecord(opPut, key, value)); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file = file
+	return nil
+}
+
+// Compact 立即压缩日志，只保留存活键值
+func (s *Store) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return ErrClosed
+	}
+	return s.compact()
+}
+
+// maybeCompact 日志增长到阈值时压缩，调用方需持有写锁
+// 压缩失败不影响已经写入的记录，只推迟下一次压缩
+func (s *Store) maybeCompact() {
+	if s.threshold > 0 && s.size >= s.compactAt {
+		_ = s.compact()
+	}
+}
+
+// append 追加一条记录并同步到磁盘，调用方需持有写锁
+func (s *Store) append(op byte, key string, value []byte) error {
+	if s.file == nil {
+		return ErrClosed
+	}
+	record := encodeRecord(op, key, value)
+	n, err := s.file.Write(record)
+	s.size += int64(n)
+	if err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+// Put 写入或覆盖键值
+func (s *Store) Put(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.append(opPut, key, value); err != nil {
+		return err
+	}
+	old, ok := s.data[key]
+	s.live -= liveSize(key, old, ok)
+	s.data[key] = append([]byte(nil), value...)
+	s.live += liveSize(key, value, true)
+	s.maybeCompact()
+	return nil
+}
+
+// Get 读取键值，不存在时返回 fastscheduler.ErrNotFound
+func (s *Store) Get(key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.data[key]
+	if !ok {
+		return nil, fastscheduler.ErrNotFound
+	}
+	return append([]byte(nil), value...), nil
+}
+
+// Delete 删除键
+func (s *Store) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.data[key]
+	if !ok {
+		return nil
+	}
+	if err := s.append(opDelete, key, nil); err != nil {
+		return err
+	}
+	s.live -= liveSize(key, old, true)
+	delete(s.data, key)
+	s.maybeCompact()
+	return nil
+}
+
+// Scan 按键的字典序遍历，fn 可以修改存储
+func (s *Store) Scan(fn func(key string, value []byte) error) error {
+	s.mu.RLock()
+	keys := make([]string, 0, len(s.data))
+	for key := range s.data {
+		keys = append(keys, key)
+	}
+	s.mu.RUnlock()
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, err := s.Get(key)
+		if err != nil {
+			// 遍历期间被删除
+			continue
+		}
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close 关闭日志文件
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// liveSize 存活键值压缩后占用的日志大小，键不存在时为0
+func liveSize(key string, value []byte, ok bool) int64 {
+	if !ok {
+		return 0
+	}
+	return int64(headerSize + len(key) + len(value) + 4)
+}
+
+// encodeRecord 编码一条记录：头部、键、值和前述内容的 CRC32
+func encodeRecord(op byte, key string, value []byte) []byte {
+	buf := make([]byte, headerSize+len(key)+len(value)+4)
+	buf[0] = op
+	binary.LittleEndian.PutUint32(buf[1:5], uint32(len(key)))
+	binary.LittleEndian.PutUint32(buf[5:9], uint32(len(value)))
+	copy(buf[headerSize:], key)
+	copy(buf[headerSize+len(key):], value)
+	n := len(buf) - 4
+	binary.LittleEndian.PutUint32(buf[n:], crc32.ChecksumIEEE(buf[:n]))
+	return buf
+}
+
+// readRecord 读取并校验一条记录
+func readRecord(r io.Reader) (op byte, key string, value []byte, err error) {
+	var header [headerSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, "", nil, err
+	}
+	keyLen := binary.LittleEndian.Uint32(header[1:5])
+	valueLen := binary.LittleEndian.Uint32(header[5:9])
+	if uint64(keyLen)+uint64(valueLen) > maxRecordSize {
+		return 0, "", nil, errors.New("filestore: record too large")
+	}
+	body := make([]byte, int(keyLen)+int(valueLen)+4)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, "", nil, err
+	}
+	n := len(body) - 4
+	crc := crc32.NewIEEE()
+	crc.Write(header[:])
+	crc.Write(body[:n])
+	if crc.Sum32() != binary.LittleEndian.Uint32(body[n:]) {
+		return 0, "", nil, errors.New("filestore: checksum mismatch")
+	}
+	return header[0], string(body[:keyLen]), body[keyLen:n], nil
+}
@@ -0,0 +1,94 @@
+package filestore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+	"github.com/hawkli-1994/fast-scheduler/storetest"
+)
+
+func TestStore(t *testing.T) {
+	storetest.TestStore(t, func(t *testing.T) storetest.Opener {
+		path := filepath.Join(t.TempDir(), "tasks.log")
+		return func() (fastscheduler.Store, error) { return Open(path) }
+	})
+}
+
+func TestStore_TruncatedTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.log")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if err := s.Put("kept", []byte("v")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	s.Close()
+
+	// 模拟崩溃时写了一半的记录
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v", err)
+	}
+	f.Write(encodeRecord(opPut, "partial", []byte("value"))[:12])
+	f.Close()
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open after crash failed: %v", err)
+	}
+	defer s.Close()
+	if v, err := s.Get("kept"); err != nil || string(v) != "v" {
+		t.Errorf("Expected kept=v, got %q, %v", v, err)
+	}
+	if _, err := s.Get("partial"); err == nil {
+		t.Error("Partial record should be discarded")
+	}
+	if err := s.Put("after", []byte("v")); err != nil {
+		t.Errorf("Put after recovery failed: %v", err)
+	}
+}
+
+func TestStore_CompactsAtRuntime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.log")
+	s, err := Open(path, WithCompactThreshold(1024))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	value := make([]byte, 100)
+	for i := 0; i < 200; i++ {
+		if err := s.Put("task", value); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+		if err := s.Put("tmp", value); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+		if err := s.Delete("tmp"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+	}
+
+	// 不压缩时日志约 200*(113+116+16) 字节
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if info.Size() > 2048 {
+		t.Errorf("Expected log to be compacted at runtime, size is %d", info.Size())
+	}
+	s.Close()
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer s.Close()
+	if v, err := s.Get("task"); err != nil || len(v) != 100 {
+		t.Errorf("Expected task to survive compaction, got %d bytes, %v", len(v), err)
+	}
+	if _, err := s.Get("tmp"); err == nil {
+		t.Error("Deleted key should stay deleted after compaction")
+	}
+}
@@ -0,0 +1,72 @@
+package fastscheduler
+
+import (
+	"context"
+	"sync"
+)
+
+// Future 表示单个已提交任务或后续任务链的句柄
+type Future struct {
+	done    <-chan struct{}
+	resolve func() TaskResult
+	once    sync.Once
+	result  TaskResult
+
+	// sched、group 和 chain 用于提交后续任务：单任务 Future 使用 group 的任务链上下文，
+	// Then 返回的 Future 沿用所在任务链的 chain
+	sched *Scheduler
+	group *taskGroup
+	chain context.Context
+}
+
+// Submit 提交单个任务并返回其 Future，调度器已停止时返回 ErrSchedulerStopped
+func (s *Scheduler) Submit(task *Task, opts ...BatchOption) (*Future, error) {
+	return newFuture(s.SubmitBatch([]*Task{task}, opts...))
+}
+
+// newFuture 用单任务批次创建 Future
+func newFuture(batch *Batch, err error) (*Future, error) {
+	if batch == nil {
+		return nil, err
+	}
+	return &Future{
+		done: batch.group.done,
+		resolve: func() TaskResult {
+			return <-batch.ResultsChan()
+		},
+		sched: batch.group.sched,
+		group: batch.group,
+	}, err
+}
+
+// chainContext 返回后续任务所在任务链的上下文
+func (f *Future) chainContext() context.Context {
+	if f.chain != nil {
+		return f.chain
+	}
+	return f.group.chainContext()
+}
+
+// Done 返回任务完成时关闭的通道
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait 等待任务完成，ctx 先结束时返回 ctx.Err()
+func (f *Future) Wait(ctx context.Context) error {
+	select {
+	case <-f.Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Result 返回任务结果，任务未完成时阻塞
+func (f *Future) Result() TaskResult {
+	<-f.Done()
+	f.once.Do(func() {
+		f.result = f.resolve()
+	})
+	return f.result
+}
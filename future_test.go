@@ -0,0 +1,48 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScheduler_SubmitFuture(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	release := make(chan struct{})
+	future, err := scheduler.Submit(&Task{
+		ID: "future-task",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			<-release
+			return TaskResult{HTTPCode: 200, BusinessCode: 0, Data: "done"}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	// 任务未完成时 Wait 应随 ctx 超时返回
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := future.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+
+	close(release)
+	select {
+	case <-future.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Future was not done in time")
+	}
+
+	res := future.Result()
+	if res.TaskID != "future-task" || res.Data != "done" {
+		t.Errorf("Unexpected result: %+v", res)
+	}
+	// 多次调用返回相同结果
+	if again := future.Result(); again.Data != "done" {
+		t.Errorf("Expected repeated Result to return same data, got %+v", again)
+	}
+}
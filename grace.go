@@ -0,0 +1,83 @@
+package fastscheduler
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// WithCancelGrace 任务获胜后，其余执行中的任务有 d 的宽限时间自行完成(或到达安全点)后才被取消，
+// 避免中断进行到一半的下游写入；宽限期间尚未开始的任务不再执行。
+// 宽限结束时仍在执行、随后被取消的任务记录在 Summary().Abandoned 中
+func WithCancelGrace(d time.Duration) BatchOption {
+	return func(g *taskGroup) {
+		g.grace.period = d
+	}
+}
+
+// graceState 成功后的取消宽限
+type graceState struct {
+	period time.Duration
+	once   sync.Once
+	// halted 关闭后批次中尚未开始的任务不再执行
+	halted   chan struct{}
+	haltOnce sync.Once
+	// abandoned 宽限结束时被取消的任务ID，由 errMu 保护
+	abandoned []string
+}
+
+// stopPending 不再开始批次中尚未执行的任务，执行中的任务不受影响
+func (g *taskGroup) stopPending() {
+	g.grace.haltOnce.Do(func() { close(g.grace.halted) })
+}
+
+// pendingStopped 返回批次是否已停止开始新任务
+func (g *taskGroup) pendingStopped() bool {
+	select {
+	case <-g.grace.halted:
+		return true
+	default:
+		return false
+	}
+}
+
+// cancelOnWin 任务获胜后取消其余任务，设置了 WithCancelGrace 时先停止开始新任务，宽限结束后再取消
+func (g *taskGroup) cancelOnWin() {
+	if g.grace.period <= 0 {
+		g.cancel()
+		return
+	}
+	g.grace.once.Do(func() {
+		g.stopPending()
+		timer := g.sched.clock.NewTimer(g.grace.period)
+		go func() {
+			defer timer.Stop()
+			select {
+			case <-timer.C():
+				g.abandonRunning()
+				g.cancel()
+			case <-g.done:
+			}
+		}()
+	})
+}
+
+// abandonRunning 宽限结束时记录仍在执行的任务，随后这些任务被取消
+func (g *taskGroup) abandonRunning() {
+	s := g.sched
+	var abandoned []*Task
+	s.running.Range(func(k, _ any) bool {
+		if t := k.(*Task); t.group == g {
+			abandoned = append(abandoned, t)
+		}
+		return true
+	})
+	g.errMu.Lock()
+	for _, t := range abandoned {
+		g.grace.abandoned = append(g.grace.abandoned, t.ID)
+	}
+	g.errMu.Unlock()
+	for _, t := range abandoned {
+		s.logTask(slog.LevelWarn, "task abandoned after cancel grace", t, slog.Duration("grace", g.grace.period))
+	}
+}
@@ -0,0 +1,69 @@
+package fastscheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBatch_CancelGrace(t *testing.T) {
+	scheduler := NewScheduler(3, 10)
+	defer scheduler.Stop()
+
+	var writerInterrupted, pendingRan atomic.Bool
+	started := make(chan struct{}, 2)
+	batch, err := scheduler.SubmitBatch([]*Task{
+		{
+			ID: "writer",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				started <- struct{}{}
+				// 正在进行的写入在宽限内完成，不应被中断
+				time.Sleep(30 * time.Millisecond)
+				writerInterrupted.Store(ctx.Err() != nil)
+				return TaskResult{HTTPCode: 500}, nil
+			},
+		},
+		{
+			ID: "hung",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				started <- struct{}{}
+				<-ctx.Done()
+				return TaskResult{}, ctx.Err()
+			},
+		},
+		{
+			ID: "winner",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				<-started
+				<-started
+				return TaskResult{HTTPCode: 200}, nil
+			},
+		},
+		{
+			ID: "pending",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				pendingRan.Store(true)
+				return TaskResult{HTTPCode: 200}, nil
+			},
+		},
+	}, WithCancelGrace(80*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	if writerInterrupted.Load() {
+		t.Error("Expected in-flight writer to finish within the grace period")
+	}
+	if pendingRan.Load() {
+		t.Error("Expected pending task not to start after the batch was won")
+	}
+	sum := batch.Summary()
+	if len(sum.Abandoned) != 1 || sum.Abandoned[0] != "hung" {
+		t.Errorf("Abandoned = %v, want [hung]", sum.Abandoned)
+	}
+	if sum.Winner.TaskID != "winner" || sum.CancelledTasks != 2 {
+		t.Errorf("Unexpected summary %+v", sum)
+	}
+}
@@ -0,0 +1,207 @@
+package fastscheduler
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HedgeAuto 作为 HedgeConfig.Delay 使用时，根据目标近期延迟的p95动态计算对冲延迟
+const HedgeAuto time.Duration = -1
+
+const (
+	// defaultHedgeFallback 样本不足时自动对冲使用的延迟
+	defaultHedgeFallback = 100 * time.Millisecond
+	// minHedgeSamples 自动对冲计算百分位所需的最少样本数
+	minHedgeSamples = 20
+	// latencyWindowSize 每个目标保留的最近延迟样本数
+	latencyWindowSize = 512
+)
+
+// HedgeConfig 任务对冲配置
+// 任务在 Delay 时间内未完成时，再并发发起一次相同的执行，最先成功的结果生效
+type HedgeConfig struct {
+	// Delay 发起对冲请求前的等待时间，HedgeAuto 表示按目标延迟p95自动计算
+	Delay time.Duration
+
+	// MaxAttempts 最多并发执行次数(包含首次)，默认2
+	MaxAttempts int
+
+	// Target 延迟统计使用的目标标识，为空时使用任务ID
+	Target string
+
+	// Fallback 自动模式下样本不足时使用的延迟，默认100ms
+	Fallback time.Duration
+
+	// Backoff 第 n 次对冲前的等待时间，设置后代替 Delay，可用于逐次拉长对冲间隔
+	Backoff Backoff
+}
+
+// attempt 单次执行的结果
+type attempt struct {
+	result TaskResult
+	err    error
+}
+
+// runHedged 按对冲配置执行任务
+func (s *Scheduler) runHedged(ctx context.Context, task *Task) (TaskResult, error) {
+	cfg := task.Hedge
+	target := cfg.Target
+	if target == "" {
+		target = task.ID
+	}
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 2
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	attempts := make(chan attempt, maxAttempts)
+	launch := func() {
+		go func() {
+			start := s.now()
+			result, err := s.execute(ctx, task)
+			// 被取消的尝试(对冲落败或批次取消)实际延迟不短于已耗时间，作为删失样本计入
+			s.latencies.observe(target, s.since(start), ctx.Err() != nil)
+			attempts <- attempt{result: result, err: err}
+		}()
+	}
+
+	launch()
+	launched, received := 1, 0
+	timer := s.clock.NewTimer(s.hedgeDelay(cfg, target, launched))
+	defer timer.Stop()
+
+	var last attempt
+	for {
+		select {
+		case a := <-attempts:
+			received++
+			if a.err == nil && isSuccess(a.result) {
+				return a.result, nil
+			}
+			last = a
+			if received == launched {
+				return last.result, last.err
+			}
+		case <-timer.C():
+			if launched < maxAttempts {
+				launch()
+				launched++
+				timer.Reset(s.hedgeDelay(cfg, target, launched))
+			}
+		}
+	}
+}
+
+// hedgeDelay 计算第 n 次对冲前的延迟
+func (s *Scheduler) hedgeDelay(cfg *HedgeConfig, target string, n int) time.Duration {
+	if cfg.Backoff != nil {
+		return cfg.Backoff.Next(n)
+	}
+	if cfg.Delay != HedgeAuto {
+		return cfg.Delay
+	}
+	if p95, ok := s.latencies.percentile(target, 0.95); ok {
+		return p95
+	}
+	if cfg.Fallback > 0 {
+		return cfg.Fallback
+	}
+	return defaultHedgeFallback
+}
+
+// latencyTracker 按目标保存最近的执行延迟
+type latencyTracker struct {
+	mu      sync.Mutex
+	windows map[string]*latencyWindow
+}
+
+// latencyWindow 固定大小的环形延迟样本窗口
+type latencyWindow struct {
+	samples []latencySample
+	next    int
+}
+
+// latencySample 一次执行的延迟，censored 表示执行被取消，实际延迟不短于 d
+type latencySample struct {
+	d        time.Duration
+	censored bool
+}
+
+// record 记录一次完整执行的延迟样本
+func (t *latencyTracker) record(target string, d time.Duration) {
+	t.observe(target, d, false)
+}
+
+// observe 记录一次延迟样本，censored 为true时 d 只是实际延迟的下限
+func (t *latencyTracker) observe(target string, d time.Duration, censored bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.windows == nil {
+		t.windows = make(map[string]*latencyWindow)
+	}
+	w, ok := t.windows[target]
+	if !ok {
+		w = &latencyWindow{}
+		t.windows[target] = w
+	}
+	w.add(latencySample{d: d, censored: censored})
+}
+
+// recordTracked 目标已有延迟统计时记录一次完整执行，不为未使用对冲的任务创建窗口
+func (t *latencyTracker) recordTracked(target string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if w, ok := t.windows[target]; ok {
+		w.add(latencySample{d: d})
+	}
+}
+
+// add 追加样本，窗口已满时覆盖最旧的样本
+func (w *latencyWindow) add(sample latencySample) {
+	if len(w.samples) < latencyWindowSize {
+		w.samples = append(w.samples, sample)
+		return
+	}
+	w.samples[w.next] = sample
+	w.next = (w.next + 1) % latencyWindowSize
+}
+
+// percentile 返回目标延迟的百分位值，样本不足时返回false
+// 删失样本按 Kaplan-Meier 乘积限估计处理：它们计入风险集但不视为完成，
+// 因此对冲落败的慢请求会抬高百分位，而不是被忽略；估计达不到 p 时返回最大的样本
+func (t *latencyTracker) percentile(target string, p float64) (time.Duration, bool) {
+	t.mu.Lock()
+	w, ok := t.windows[target]
+	if !ok || len(w.samples) < minHedgeSamples {
+		t.mu.Unlock()
+		return 0, false
+	}
+	sorted := make([]latencySample, len(w.samples))
+	copy(sorted, w.samples)
+	t.mu.Unlock()
+
+	// 同一时长下完整样本排在删失样本之前
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].d != sorted[j].d {
+			return sorted[i].d < sorted[j].d
+		}
+		return !sorted[i].censored && sorted[j].censored
+	})
+	survival := 1.0
+	for i, sample := range sorted {
+		if sample.censored {
+			continue
+		}
+		survival *= 1 - 1/float64(len(sorted)-i)
+		if 1-survival >= p {
+			return sample.d, true
+		}
+	}
+	return sorted[len(sorted)-1].d, true
+}
@@ -0,0 +1,132 @@
+package fastscheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_HedgeAutoDelay(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	// 预置目标的历史延迟样本，p95约为20ms
+	for i := 0; i < 100; i++ {
+		scheduler.latencies.record("upstream", time.Duration(i%21)*time.Millisecond)
+	}
+	cfg := &HedgeConfig{Delay: HedgeAuto, Target: "upstream"}
+	if d := scheduler.hedgeDelay(cfg, "upstream", 1); d < 15*time.Millisecond || d > 25*time.Millisecond {
+		t.Errorf("Expected auto hedge delay around 20ms, got %v", d)
+	}
+
+	// 第一次执行卡住，对冲请求应快速返回成功
+	var calls atomic.Int32
+	task := &Task{
+		ID:    "hedged",
+		Hedge: cfg,
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			if calls.Add(1) == 1 {
+				<-ctx.Done()
+				return TaskResult{}, ctx.Err()
+			}
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	}
+
+	start := time.Now()
+	batch, err := scheduler.SubmitBatch([]*Task{task})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	if !batch.IsSuccess() {
+		t.Error("Expected hedged attempt to succeed")
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls.Load())
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Hedged task took too long: %v", elapsed)
+	}
+}
+
+func TestLatencyTracker_CensoredSamples(t *testing.T) {
+	var tracker latencyTracker
+	// 一半的请求很快完成，另一半在 100ms 时因对冲落败被取消
+	for i := 0; i < 20; i++ {
+		tracker.observe("upstream", 10*time.Millisecond, false)
+		tracker.observe("upstream", 100*time.Millisecond, true)
+	}
+	p95, ok := tracker.percentile("upstream", 0.95)
+	if !ok {
+		t.Fatal("Expected enough samples")
+	}
+	if p95 < 100*time.Millisecond {
+		t.Errorf("Expected cancelled slow attempts to raise p95 to at least 100ms, got %v", p95)
+	}
+	if p50, _ := tracker.percentile("upstream", 0.5); p50 != 10*time.Millisecond {
+		t.Errorf("Expected p50 of 10ms, got %v", p50)
+	}
+}
+
+func TestScheduler_HedgeRecordsLosersAndPlainExecutions(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	var calls atomic.Int32
+	hedged := &Task{
+		ID:    "hedged",
+		Hedge: &HedgeConfig{Delay: 5 * time.Millisecond, Target: "upstream"},
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			if calls.Add(1) == 1 {
+				<-ctx.Done()
+				return TaskResult{}, ctx.Err()
+			}
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	}
+	plain := &Task{
+		ID: "upstream",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	}
+	untracked := &Task{
+		ID: "other",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	}
+	for _, task := range []*Task{hedged, plain, untracked} {
+		batch, err := scheduler.SubmitBatch([]*Task{task})
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		batch.Wait()
+	}
+
+	samples := func(target string) []latencySample {
+		scheduler.latencies.mu.Lock()
+		defer scheduler.latencies.mu.Unlock()
+		if w, ok := scheduler.latencies.windows[target]; ok {
+			return append([]latencySample(nil), w.samples...)
+		}
+		return nil
+	}
+	// 落败的尝试在获胜者返回后才记录
+	waitFor(t, func() bool { return len(samples("upstream")) == 3 })
+	var censored int
+	for _, sample := range samples("upstream") {
+		if sample.censored {
+			censored++
+		}
+	}
+	if censored != 1 {
+		t.Errorf("Expected the losing attempt as one censored sample, got %d", censored)
+	}
+	if got := samples("other"); got != nil {
+		t.Errorf("Expected no latency window for an untracked task, got %v", got)
+	}
+}
@@ -0,0 +1,22 @@
+package fastscheduler
+
+import "time"
+
+// Hints 描述任务的执行环境和位置，用于多云/多区域的扇出策略
+type Hints struct {
+	// Region 任务访问的目标区域，如 "cn-hangzhou"
+	Region string `json:"region,omitempty"`
+	// Provider 目标云厂商或服务提供方
+	Provider string `json:"provider,omitempty"`
+	// CostTier 成本等级，如 "spot"、"standard"、"premium"
+	CostTier string `json:"cost_tier,omitempty"`
+}
+
+// PreferRegion 优先执行 Hints.Region 等于 region 的任务，
+// 其他区域的任务延迟 fallback 后才启动；同区域任务在此之前成功时跨区域任务不会执行
+func PreferRegion(region string, fallback time.Duration) BatchOption {
+	return func(g *taskGroup) {
+		g.preferRegion = region
+		g.fallbackDelay = fallback
+	}
+}
@@ -0,0 +1,52 @@
+package fastscheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBatch_PreferRegion(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	var crossRegionRuns atomic.Int32
+	tasks := []*Task{
+		{
+			ID:    "local",
+			Hints: Hints{Region: "eu-west"},
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				time.Sleep(10 * time.Millisecond)
+				return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+			},
+		},
+		{
+			ID:    "remote",
+			Hints: Hints{Region: "us-east"},
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				crossRegionRuns.Add(1)
+				return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+			},
+		},
+	}
+
+	start := time.Now()
+	batch, err := scheduler.SubmitBatch(tasks, PreferRegion("eu-west", 200*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	if crossRegionRuns.Load() != 0 {
+		t.Error("Cross-region task should not run when local task succeeds first")
+	}
+	if time.Since(start) > 150*time.Millisecond {
+		t.Error("Batch should complete without waiting for the fallback delay")
+	}
+	for res := range batch.ResultsChan() {
+		if res.TaskID == "local" && res.Hints.Region != "eu-west" {
+			t.Errorf("Expected hints on result, got %+v", res.Hints)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package fastscheduler
+
+import (
+	"math/bits"
+	"sync"
+	"time"
+)
+
+// subBucketBits 每个2的幂区间内线性分桶的位数，32个子桶，相对误差约3%
+const subBucketBits = 5
+
+// histogram HDR 风格的延迟直方图，以微秒为单位按对数-线性分桶
+// 内存随最大值对数增长，记录和查询都不需要保存原始样本
+type histogram struct {
+	counts []uint64
+	total  uint64
+	max    time.Duration
+}
+
+// bucketIndex 返回值所在的桶
+func bucketIndex(v uint64) int {
+	if v < 1<<(subBucketBits+1) {
+		return int(v)
+	}
+	shift := bits.Len64(v) - (subBucketBits + 1)
+	return shift<<subBucketBits + int(v>>shift)
+}
+
+// bucketValue 返回桶的代表值(区间中点)
+func bucketValue(i int) uint64 {
+	if i < 1<<(subBucketBits+1) {
+		return uint64(i)
+	}
+	shift := i>>subBucketBits - 1
+	low := uint64(i-shift<<subBucketBits) << shift
+	return low + (uint64(1)<<shift)/2
+}
+
+// record 记录一个样本
+func (h *histogram) record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	i := bucketIndex(uint64(d / time.Microsecond))
+	if i >= len(h.counts) {
+		h.counts = append(h.counts, make([]uint64, i+1-len(h.counts))...)
+	}
+	h.counts[i]++
+	h.total++
+	if d > h.max {
+		h.max = d
+	}
+}
+
+// quantile 返回分位值，q 取值 0~1
+func (h *histogram) quantile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	rank := uint64(q*float64(h.total-1)) + 1
+	var seen uint64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= rank {
+			d := time.Duration(bucketValue(i)) * time.Microsecond
+			if d > h.max {
+				d = h.max
+			}
+			return d
+		}
+	}
+	return h.max
+}
+
+// LatencyStats 延迟分布
+type LatencyStats struct {
+	// Count 样本数
+	Count int64
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+	Max   time.Duration
+}
+
+// snapshot 返回直方图的分位统计
+func (h *histogram) snapshot() LatencyStats {
+	return LatencyStats{
+		Count: int64(h.total),
+		P50:   h.quantile(0.50),
+		P95:   h.quantile(0.95),
+		P99:   h.quantile(0.99),
+		Max:   h.max,
+	}
+}
+
+// TimingStats 已执行任务的排队等待和执行耗时分布
+type TimingStats struct {
+	// QueueWait 从提交到最后一次执行开始的时长，见 TaskMetrics.QueueWait
+	QueueWait LatencyStats
+	// Execution 最后一次执行的耗时
+	Execution LatencyStats
+}
+
+// timingPair 一组排队和执行直方图
+type timingPair struct {
+	wait, exec histogram
+}
+
+// timingStats 全局以及按 Key 分组的延迟直方图
+type timingStats struct {
+	mu  sync.Mutex
+	all timingPair
+	// byKey 按 Task.Key 分组，nil表示未启用
+	byKey map[string]*timingPair
+}
+
+// WithLatencyByKey 在 Stats().TimingByKey 中按 Task.Key 分组统计排队和执行耗时
+// 每个 Key 保存一组直方图，Key 的取值数量应当有限
+func WithLatencyByKey() Option {
+	return func(s *Scheduler) {
+		s.timings.byKey = make(map[string]*timingPair)
+	}
+}
+
+// record 记录一个已执行任务的时间
+func (t *timingStats) record(key string, m TaskMetrics) {
+	if m.StartedAt.IsZero() {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.all.wait.record(m.QueueWait)
+	t.all.exec.record(m.Duration)
+	if t.byKey == nil || key == "" {
+		return
+	}
+	p := t.byKey[key]
+	if p == nil {
+		p = &timingPair{}
+		t.byKey[key] = p
+	}
+	p.wait.record(m.QueueWait)
+	p.exec.record(m.Duration)
+}
+
+// snapshot 返回全局和按 Key 分组的统计
+func (t *timingStats) snapshot() (TimingStats, map[string]TimingStats) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	all := TimingStats{QueueWait: t.all.wait.snapshot(), Execution: t.all.exec.snapshot()}
+	if t.byKey == nil {
+		return all, nil
+	}
+	byKey := make(map[string]TimingStats, len(t.byKey))
+	for k, p := range t.byKey {
+		byKey[k] = TimingStats{QueueWait: p.wait.snapshot(), Execution: p.exec.snapshot()}
+	}
+	return all, byKey
+}
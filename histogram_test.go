@@ -0,0 +1,66 @@
+package fastscheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHistogram_Quantiles(t *testing.T) {
+	var h histogram
+	for i := 1; i <= 1000; i++ {
+		h.record(time.Duration(i) * time.Millisecond)
+	}
+	within := func(got, want time.Duration) bool {
+		diff := got - want
+		if diff < 0 {
+			diff = -diff
+		}
+		return diff <= want/25
+	}
+	stats := h.snapshot()
+	if stats.Count != 1000 || stats.Max != time.Second {
+		t.Errorf("Unexpected count/max %+v", stats)
+	}
+	for _, c := range []struct {
+		name      string
+		got, want time.Duration
+	}{
+		{"p50", stats.P50, 500 * time.Millisecond},
+		{"p95", stats.P95, 950 * time.Millisecond},
+		{"p99", stats.P99, 990 * time.Millisecond},
+	} {
+		if !within(c.got, c.want) {
+			t.Errorf("%s = %s, want about %s", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestScheduler_TimingStats(t *testing.T) {
+	scheduler := NewScheduler(2, 10, WithLatencyByKey())
+	defer scheduler.Stop()
+
+	sleep := func(d time.Duration) func(context.Context) (TaskResult, error) {
+		return func(ctx context.Context) (TaskResult, error) {
+			time.Sleep(d)
+			return TaskResult{HTTPCode: 500}, nil
+		}
+	}
+	batch, err := scheduler.SubmitBatch([]*Task{
+		{ID: "fast", Key: "cache", Execute: sleep(time.Millisecond)},
+		{ID: "slow", Key: "db", Execute: sleep(30 * time.Millisecond)},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	stats := scheduler.Stats()
+	if stats.Timing.Execution.Count != 2 || stats.Timing.Execution.Max < 30*time.Millisecond {
+		t.Errorf("Unexpected execution stats %+v", stats.Timing.Execution)
+	}
+	db, cache := stats.TimingByKey["db"], stats.TimingByKey["cache"]
+	if db.Execution.P99 < 25*time.Millisecond || cache.Execution.P99 >= db.Execution.P99 {
+		t.Errorf("Expected per-key execution stats, got db=%+v cache=%+v", db.Execution, cache.Execution)
+	}
+}
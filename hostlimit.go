@@ -0,0 +1,38 @@
+package fastscheduler
+
+// WithHostConcurrencyLimit 限制同一目标主机的HTTP任务(NewHTTPTask、RaceHTTP 创建)同时执行的数量，跨批次生效
+// 例如多个批次都在竞速请求同一个副本时，该副本最多同时承受 n 个请求；超出的任务按提交顺序排队等待，
+// 不占用worker。对冲的多次尝试属于同一个任务，只占用一个名额；重试等待期间释放名额。n 小于等于0表示不限制
+func WithHostConcurrencyLimit(n int) Option {
+	return func(s *Scheduler) {
+		s.hosts.limit = n
+	}
+}
+
+// acquireHost 尝试让任务持有主机名额，达到上限时任务进入等待队列并返回false
+// 不是HTTP任务、未设置上限或已持有名额时直接返回true
+func (s *Scheduler) acquireHost(t *Task) bool {
+	if t.host == "" || t.hostHeld || s.hosts.limit <= 0 {
+		return true
+	}
+	if !s.hosts.acquire(t.host, t) {
+		return false
+	}
+	t.hostHeld = true
+	return true
+}
+
+// releaseHost 释放任务持有的主机名额，并将同一主机的下一个任务入队
+func (s *Scheduler) releaseHost(t *Task) {
+	if !t.hostHeld {
+		return
+	}
+	t.hostHeld = false
+	next := s.hosts.release(t.host)
+	if next == nil {
+		return
+	}
+	next.hostHeld = true
+	next.reason = "host " + t.host + " released"
+	s.requeue(next)
+}
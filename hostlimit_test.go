@@ -0,0 +1,63 @@
+package fastscheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_HostConcurrencyLimit(t *testing.T) {
+	var inflight, peak atomic.Int32
+	replicaA := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inflight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		inflight.Add(-1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer replicaA.Close()
+	var replicaBCalls atomic.Int32
+	replicaB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		replicaBCalls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer replicaB.Close()
+
+	scheduler := NewScheduler(16, 100, WithHostConcurrencyLimit(2))
+	defer scheduler.Stop()
+
+	var batches []*Batch
+	for i := 0; i < 10; i++ {
+		var tasks []*Task
+		for _, server := range []*httptest.Server{replicaA, replicaB} {
+			req, _ := http.NewRequest(http.MethodGet, server.URL+"/quote", nil)
+			task, err := NewHTTPTask(server.Client(), req)
+			if err != nil {
+				t.Fatalf("NewHTTPTask failed: %v", err)
+			}
+			tasks = append(tasks, task)
+		}
+		batch, err := scheduler.SubmitBatch(tasks)
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		batches = append(batches, batch)
+	}
+	for _, batch := range batches {
+		batch.Wait()
+	}
+
+	if p := peak.Load(); p > 2 {
+		t.Errorf("Expected at most 2 in-flight requests to replica A, got %d", p)
+	}
+	if replicaBCalls.Load() != 10 {
+		t.Errorf("Expected other hosts to be unaffected, got %d calls", replicaBCalls.Load())
+	}
+}
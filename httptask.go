@@ -0,0 +1,232 @@
+package fastscheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultHTTPMaxBody NewHTTPTask 默认读取的最大响应体字节数
+const defaultHTTPMaxBody = 10 << 20
+
+// HTTPResponse NewHTTPTask 任务结果的 Data
+type HTTPResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// RetryAfter 返回服务端要求的重试前等待时间，now 为当前时间
+// 429 和 503 响应按 Retry-After(秒数或HTTP日期)计算；没有 Retry-After 时，
+// 429 响应或 X-RateLimit-Remaining 为0的响应按 X-RateLimit-Reset 计算，
+// 该值看起来像Unix时间戳时按到达该时刻计算，否则按剩余秒数计算。响应没有要求等待时返回false
+func (r *HTTPResponse) RetryAfter(now time.Time) (time.Duration, bool) {
+	limited := r.StatusCode == http.StatusTooManyRequests
+	if limited || r.StatusCode == http.StatusServiceUnavailable {
+		if v := r.Header.Get("Retry-After"); v != "" {
+			if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return max(time.Duration(secs)*time.Second, 0), true
+			}
+			if at, err := http.ParseTime(v); err == nil {
+				return max(at.Sub(now), 0), true
+			}
+		}
+	}
+	if !limited && r.Header.Get("X-RateLimit-Remaining") != "0" {
+		return 0, false
+	}
+	v := r.Header.Get("X-RateLimit-Reset")
+	if v == "" {
+		return 0, false
+	}
+	reset, err := strconv.ParseFloat(v, 64)
+	if err != nil || reset < 0 {
+		return 0, false
+	}
+	if reset >= unixTimestampThreshold {
+		at := time.Unix(0, int64(reset*float64(time.Second)))
+		return max(at.Sub(now), 0), true
+	}
+	return time.Duration(reset * float64(time.Second)), true
+}
+
+// unixTimestampThreshold X-RateLimit-Reset 不小于该值时视为Unix时间戳(约2001年)，否则视为剩余秒数
+const unixTimestampThreshold = 1e9
+
+// HTTPOption 配置 NewHTTPTask 创建的任务
+type HTTPOption func(*httpTask)
+
+// httpTask NewHTTPTask 的配置
+type httpTask struct {
+	task *Task
+	// codeField 业务信封中业务码的字段名，为空时不解析
+	codeField string
+	maxBody   int64
+}
+
+// WithBusinessCodeField 指定JSON响应中业务码的字段名，默认 "code"，为空时不解析业务码
+func WithBusinessCodeField(field string) HTTPOption {
+	return func(h *httpTask) {
+		h.codeField = field
+	}
+}
+
+// WithMaxResponseBody 限制读取的响应体字节数，默认10MB，超出部分被丢弃
+func WithMaxResponseBody(n int64) HTTPOption {
+	return func(h *httpTask) {
+		h.maxBody = n
+	}
+}
+
+// WithHTTPHedge 为请求启用对冲，Target 为空时按请求的主机统计延迟
+func WithHTTPHedge(cfg HedgeConfig) HTTPOption {
+	return func(h *httpTask) {
+		h.task.Hedge = &cfg
+	}
+}
+
+// WithHTTPTaskID 设置任务ID，默认为 "METHOD host/path"
+func WithHTTPTaskID(id string) HTTPOption {
+	return func(h *httpTask) {
+		h.task.ID = id
+	}
+}
+
+// NewHTTPTask 创建执行 req 的任务
+// 每次执行(包括对冲和重试)都克隆请求并绑定任务的上下文，请求体通过 GetBody 重新获取。
+// 响应状态码映射为 HTTPCode，JSON响应中的业务码字段映射为 BusinessCode，
+// Data 为 *HTTPResponse。请求体无法重复读取时会在创建任务时一次性读入内存
+func NewHTTPTask(client *http.Client, req *http.Request, opts ...HTTPOption) (*Task, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if err := bufferBody(req); err != nil {
+		return nil, err
+	}
+
+	h := &httpTask{
+		task:      &Task{ID: req.Method + " " + req.URL.Host + req.URL.Path, host: req.URL.Host},
+		codeField: "code",
+		maxBody:   defaultHTTPMaxBody,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.task.Hedge != nil && h.task.Hedge.Target == "" {
+		h.task.Hedge.Target = req.URL.Host
+	}
+	h.task.Execute = func(ctx context.Context) (TaskResult, error) {
+		return h.do(ctx, client, req)
+	}
+	return h.task, nil
+}
+
+// do 克隆并发送请求，将响应转换为任务结果
+func (h *httpTask) do(ctx context.Context, client *http.Client, req *http.Request) (TaskResult, error) {
+	r := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return TaskResult{}, err
+		}
+		r.Body = body
+	}
+
+	resp, err := client.Do(r)
+	if err != nil {
+		return TaskResult{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, h.maxBody))
+	if err != nil {
+		return TaskResult{HTTPCode: resp.StatusCode}, err
+	}
+
+	return TaskResult{
+		HTTPCode:     resp.StatusCode,
+		BusinessCode: h.businessCode(body),
+		Data: &HTTPResponse{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       body,
+		},
+	}, nil
+}
+
+// businessCode 从JSON信封中解析业务码，响应不是JSON对象或没有该字段时返回0
+func (h *httpTask) businessCode(body []byte) int {
+	if h.codeField == "" {
+		return 0
+	}
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return 0
+	}
+	raw, ok := envelope[h.codeField]
+	if !ok {
+		return 0
+	}
+	var code json.Number
+	if err := json.Unmarshal(raw, &code); err != nil {
+		return 1
+	}
+	n, err := code.Int64()
+	if err != nil {
+		return 1
+	}
+	return int(n)
+}
+
+// bufferBody 请求体无法重复读取时一次性读入内存并设置 GetBody
+func bufferBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return fmt.Errorf("fastscheduler: read request body: %w", err)
+	}
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return nil
+}
+
+// RaceHTTP 将同一个请求同时发往多个副本，返回第一个成功的响应并取消其余请求
+// endpoints 为副本的基础地址(如 "https://replica-2.example.com/api")，
+// 请求的路径和查询参数拼接在基础地址之后；结果的 TaskID 为对应的基础地址。
+// 全部失败时返回最后完成的结果和 ErrAllFailed
+func (s *Scheduler) RaceHTTP(ctx context.Context, client *http.Client, req *http.Request, endpoints []string, opts ...HTTPOption) (TaskResult, error) {
+	if err := bufferBody(req); err != nil {
+		return TaskResult{}, err
+	}
+
+	tasks := make([]*Task, len(endpoints))
+	for i, endpoint := range endpoints {
+		base, err := url.Parse(endpoint)
+		if err != nil {
+			return TaskResult{}, fmt.Errorf("fastscheduler: parse endpoint %q: %w", endpoint, err)
+		}
+		r := req.Clone(ctx)
+		r.URL.Scheme = base.Scheme
+		r.URL.Host = base.Host
+		r.URL.Path = strings.TrimSuffix(base.Path, "/") + req.URL.Path
+		r.URL.RawPath = ""
+		r.Host = ""
+
+		taskOpts := append([]HTTPOption{WithHTTPTaskID(endpoint)}, opts...)
+		if tasks[i], err = NewHTTPTask(client, r, taskOpts...); err != nil {
+			return TaskResult{}, err
+		}
+	}
+	return s.raceTasks(ctx, tasks)
+}
@@ -0,0 +1,214 @@
+package fastscheduler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPTask(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/ok":
+			fmt.Fprintf(w, `{"code":0,"data":%q}`, body)
+		case "/biz-error":
+			fmt.Fprint(w, `{"code":4001,"msg":"quota exceeded"}`)
+		default:
+			http.Error(w, "boom", http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	cases := []struct {
+		path         string
+		httpCode     int
+		businessCode int
+	}{
+		{"/ok", 200, 0},
+		{"/biz-error", 200, 4001},
+		{"/down", 502, 0},
+	}
+	for _, tc := range cases {
+		req, err := http.NewRequest(http.MethodPost, server.URL+tc.path, strings.NewReader("payload"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		task, err := NewHTTPTask(server.Client(), req)
+		if err != nil {
+			t.Fatalf("NewHTTPTask failed: %v", err)
+		}
+		future, err := scheduler.Submit(task)
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		result := future.Result()
+		if result.HTTPCode != tc.httpCode || result.BusinessCode != tc.businessCode {
+			t.Errorf("%s: expected %d/%d, got %d/%d", tc.path, tc.httpCode, tc.businessCode, result.HTTPCode, result.BusinessCode)
+		}
+		if tc.path == "/ok" {
+			if resp := result.Data.(*HTTPResponse); !strings.Contains(string(resp.Body), "payload") {
+				t.Errorf("Request body not sent: %s", resp.Body)
+			}
+		}
+	}
+}
+
+func TestNewHTTPTask_HedgeClonesRequest(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" {
+			http.Error(w, "missing body", http.StatusBadRequest)
+			return
+		}
+		if calls.Add(1) == 1 {
+			// 第一次请求很慢，由对冲请求返回结果
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		fmt.Fprint(w, `{"code":0}`)
+	}))
+	defer server.Close()
+
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, io.NopCloser(strings.NewReader("payload")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, err := NewHTTPTask(server.Client(), req, WithHTTPHedge(HedgeConfig{Delay: 20 * time.Millisecond}))
+	if err != nil {
+		t.Fatalf("NewHTTPTask failed: %v", err)
+	}
+	future, err := scheduler.Submit(task)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if result := future.Result(); result.HTTPCode != 200 || result.BusinessCode != 0 {
+		t.Errorf("Expected hedged request to succeed, got %+v", result)
+	}
+}
+
+func TestScheduler_RaceHTTP(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer slow.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, `{"code":0,"path":%q,"query":%q,"body":%q}`, r.URL.Path, r.URL.RawQuery, body)
+	}))
+	defer healthy.Close()
+
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	req, err := http.NewRequest(http.MethodPost, "http://placeholder/v1/quote?sym=ABC", io.NopCloser(strings.NewReader("payload")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	result, err := scheduler.RaceHTTP(context.Background(), http.DefaultClient, req,
+		[]string{slow.URL, down.URL, healthy.URL + "/api"})
+	if err != nil {
+		t.Fatalf("RaceHTTP failed: %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("RaceHTTP should not wait for the slow replica")
+	}
+	if result.TaskID != healthy.URL+"/api" {
+		t.Errorf("Expected healthy replica to win, got %s", result.TaskID)
+	}
+	body := string(result.Data.(*HTTPResponse).Body)
+	if !strings.Contains(body, `"path":"/api/v1/quote"`) || !strings.Contains(body, `"query":"sym=ABC"`) || !strings.Contains(body, `"body":"payload"`) {
+		t.Errorf("Request not rewritten correctly: %s", body)
+	}
+}
+
+func TestHTTPResponse_RetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name   string
+		status int
+		header map[string]string
+		want   time.Duration
+		ok     bool
+	}{
+		{"seconds", 429, map[string]string{"Retry-After": "3"}, 3 * time.Second, true},
+		{"date", 503, map[string]string{"Retry-After": now.Add(10 * time.Second).Format(http.TimeFormat)}, 10 * time.Second, true},
+		{"past date", 503, map[string]string{"Retry-After": now.Add(-time.Minute).Format(http.TimeFormat)}, 0, true},
+		{"reset seconds", 429, map[string]string{"X-RateLimit-Reset": "2"}, 2 * time.Second, true},
+		{"reset timestamp", 403, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": fmt.Sprint(now.Add(30 * time.Second).Unix())}, 30 * time.Second, true},
+		{"quota left", 200, map[string]string{"X-RateLimit-Remaining": "10", "X-RateLimit-Reset": "2"}, 0, false},
+		{"ignored on 500", 500, map[string]string{"Retry-After": "3"}, 0, false},
+		{"none", 429, nil, 0, false},
+	}
+	for _, c := range cases {
+		resp := &HTTPResponse{StatusCode: c.status, Header: http.Header{}}
+		for k, v := range c.header {
+			resp.Header.Set(k, v)
+		}
+		got, ok := resp.RetryAfter(now)
+		if got != c.want || ok != c.ok {
+			t.Errorf("%s: got (%v, %v), want (%v, %v)", c.name, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestNewHTTPTask_RetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("X-RateLimit-Reset", "0.02")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"code":0}`)
+	}))
+	defer server.Close()
+
+	scheduler := NewScheduler(1, 10)
+	defer scheduler.Stop()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/limited", nil)
+	task, err := NewHTTPTask(server.Client(), req)
+	if err != nil {
+		t.Fatalf("NewHTTPTask failed: %v", err)
+	}
+	// 服务端要求的等待时间代替一小时的重试间隔
+	task.Retry = &RetryPolicy{MaxAttempts: 2, Delay: time.Hour}
+
+	future, err := scheduler.Submit(task)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := future.Wait(ctx); err != nil {
+		t.Fatalf("Expected retry at the indicated time, got %v", err)
+	}
+	if r := future.Result(); r.HTTPCode != 200 || r.Attempts != 2 {
+		t.Errorf("Expected success on second attempt, got %+v", r)
+	}
+}
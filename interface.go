@@ -0,0 +1,15 @@
+package fastscheduler
+
+import "context"
+
+// SchedulerInterface 调度器的提交和生命周期方法
+// 依赖调度器的服务可以面向该接口编程，在单元测试中替换为 fastschedulertest 的 Fake 或 Mock
+type SchedulerInterface interface {
+	Submit(task *Task, opts ...BatchOption) (*Future, error)
+	SubmitBatch(tasks []*Task, opts ...BatchOption) (*Batch, error)
+	SubmitBatchContext(ctx context.Context, tasks []*Task, opts ...BatchOption) (*Batch, error)
+	Stop()
+	Stats() Stats
+}
+
+var _ SchedulerInterface = (*Scheduler)(nil)
@@ -0,0 +1,30 @@
+package fastscheduler
+
+import "iter"
+
+// Iter 返回按完成顺序产出结果的迭代器，批次完成后结束
+// 调用方提前退出循环时取消批次中剩余的任务，适合"拿到足够的结果就停止"的场景：
+//
+//	for result := range batch.Iter() {
+//		if enough(result) {
+//			break
+//		}
+//	}
+//
+// 与 ResultsChan 共享同一个结果流，两者只应使用其一
+func (b *Batch) Iter() iter.Seq[TaskResult] {
+	return func(yield func(TaskResult) bool) {
+		results := b.ResultsChan()
+		for result := range results {
+			if !yield(result) {
+				b.Cancel()
+				// 排空剩余结果，避免开放批次的结果转发goroutine阻塞
+				go func() {
+					for range results {
+					}
+				}()
+				return
+			}
+		}
+	}
+}
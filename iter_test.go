@@ -0,0 +1,55 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBatch_Iter(t *testing.T) {
+	scheduler := NewScheduler(4, 10)
+	defer scheduler.Stop()
+
+	// 所有任务都会失败或等待取消，不会因为成功而提前结束批次
+	tasks := []*Task{
+		{ID: "fast", Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: 500, Data: "fast"}, nil
+		}},
+		{ID: "medium", Execute: func(ctx context.Context) (TaskResult, error) {
+			time.Sleep(20 * time.Millisecond)
+			return TaskResult{HTTPCode: 500, Data: "medium"}, nil
+		}},
+	}
+	for _, id := range []string{"hung-1", "hung-2"} {
+		tasks = append(tasks, &Task{ID: id, Execute: func(ctx context.Context) (TaskResult, error) {
+			<-ctx.Done()
+			return TaskResult{}, ctx.Err()
+		}})
+	}
+	batch, err := scheduler.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	var got []string
+	for result := range batch.Iter() {
+		got = append(got, result.TaskID)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if len(got) != 2 || got[0] != "fast" || got[1] != "medium" {
+		t.Fatalf("Iterated %v, want [fast medium]", got)
+	}
+
+	// 提前退出后剩余任务被取消，批次随之完成
+	select {
+	case <-batch.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected break to cancel remaining tasks")
+	}
+	if !errors.Is(batch.Err(), context.Canceled) {
+		t.Errorf("Expected cancelled tasks, got %v", batch.Err())
+	}
+}
@@ -0,0 +1,76 @@
+package fastscheduler
+
+import "sync"
+
+// keyGate 限制相同 Key 的任务同时在队列中或执行中的数量，超出的任务按提交顺序等待
+// 默认上限为1，即相同 Key 的任务依次执行
+type keyGate struct {
+	mu    sync.Mutex
+	limit int
+	keys  map[string]*keyState
+}
+
+// keyState 单个 Key 的持有数和等待队列
+type keyState struct {
+	held    int
+	waiting []*Task
+}
+
+// WithKeyConcurrencyLimit 设置相同 Key 的任务最多同时执行的数量，跨批次生效
+// 可用于保护单个下游服务(例如以目标主机名作为 Key)，默认值1表示相同 Key 的任务串行执行
+func WithKeyConcurrencyLimit(n int) Option {
+	return func(s *Scheduler) {
+		s.keys.limit = n
+	}
+}
+
+// acquire 尝试让任务持有 key，达到上限时任务进入等待队列并返回false
+func (g *keyGate) acquire(key string, t *Task) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.keys == nil {
+		g.keys = make(map[string]*keyState)
+	}
+	st, ok := g.keys[key]
+	if !ok {
+		st = &keyState{}
+		g.keys[key] = st
+	}
+	if st.held >= max(g.limit, 1) || len(st.waiting) > 0 {
+		st.waiting = append(st.waiting, t)
+		return false
+	}
+	st.held++
+	return true
+}
+
+// release 释放 key，返回下一个获得 key 的任务(没有则返回nil)
+func (g *keyGate) release(key string) *Task {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	st := g.keys[key]
+	if len(st.waiting) == 0 {
+		st.held--
+		if st.held == 0 {
+			delete(g.keys, key)
+		}
+		return nil
+	}
+	next := st.waiting[0]
+	st.waiting = st.waiting[1:]
+	return next
+}
+
+// releaseKey 任务完成后将同 Key 的下一个任务入队
+// 在独立goroutine中入队，避免完成任务的worker因队列已满而阻塞
+func (s *Scheduler) releaseKey(key string) {
+	next := s.keys.release(key)
+	if next == nil {
+		return
+	}
+	next.keyHeld = true
+	next.reason = "key " + key + " released"
+	s.requeue(next)
+}
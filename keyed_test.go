@@ -0,0 +1,103 @@
+package fastscheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_KeyedSerialization(t *testing.T) {
+	scheduler := NewScheduler(8, 50)
+	defer scheduler.Stop()
+
+	var mu sync.Mutex
+	order := make(map[string][]int)
+	var inflight [2]atomic.Int32
+	var overlap atomic.Bool
+
+	var tasks []*Task
+	for i := 0; i < 10; i++ {
+		user := i % 2
+		key := fmt.Sprintf("user-%d", user)
+		tasks = append(tasks, &Task{
+			ID:  fmt.Sprintf("task-%d", i),
+			Key: key,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				if inflight[user].Add(1) > 1 {
+					overlap.Store(true)
+				}
+				time.Sleep(5 * time.Millisecond)
+				mu.Lock()
+				order[key] = append(order[key], i)
+				mu.Unlock()
+				inflight[user].Add(-1)
+				return TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+			},
+		})
+	}
+
+	batch, err := scheduler.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	if overlap.Load() {
+		t.Error("Tasks with the same key ran concurrently")
+	}
+	for key, seq := range order {
+		for j := 1; j < len(seq); j++ {
+			if seq[j] < seq[j-1] {
+				t.Errorf("Tasks for %s ran out of submission order: %v", key, seq)
+			}
+		}
+		if len(seq) != 5 {
+			t.Errorf("Expected 5 tasks for %s, got %d", key, len(seq))
+		}
+	}
+}
+
+func TestScheduler_KeyConcurrencyLimit(t *testing.T) {
+	scheduler := NewScheduler(10, 50, WithKeyConcurrencyLimit(2))
+	defer scheduler.Stop()
+
+	var running, maxRunning atomic.Int32
+	newTask := func(id string) *Task {
+		return &Task{
+			ID:  id,
+			Key: "api.example.com",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				n := running.Add(1)
+				for {
+					m := maxRunning.Load()
+					if n <= m || maxRunning.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				running.Add(-1)
+				return TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+			},
+		}
+	}
+
+	// 限制跨批次生效
+	var batches []*Batch
+	for b := 0; b < 3; b++ {
+		batch, err := scheduler.SubmitBatch([]*Task{newTask("a"), newTask("b"), newTask("c")})
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		batches = append(batches, batch)
+	}
+	for _, batch := range batches {
+		batch.Wait()
+	}
+
+	if maxRunning.Load() != 2 {
+		t.Errorf("Expected at most 2 concurrent tasks per key, got %d", maxRunning.Load())
+	}
+}
@@ -0,0 +1,146 @@
+package fastscheduler
+
+// Lane 表示任务的优先级通道
+type Lane int
+
+const (
+	// LaneNormal 普通通道，SubmitBatch 使用此通道
+	LaneNormal Lane = iota
+	// LaneInteractive 交互通道，优先于其他通道调度
+	LaneInteractive
+	// LaneBackground 后台通道，仅在其他通道空闲时调度
+	LaneBackground
+
+	laneCount = 3
+)
+
+// laneOrder 按优先级从高到低排列的通道
+var laneOrder = [laneCount]Lane{LaneInteractive, LaneNormal, LaneBackground}
+
+// String 返回通道名称
+func (l Lane) String() string {
+	switch l {
+	case LaneNormal:
+		return "normal"
+	case LaneInteractive:
+		return "interactive"
+	case LaneBackground:
+		return "background"
+	default:
+		return "unknown"
+	}
+}
+
+// SubmitInteractive 提交一批交互任务，优先于普通和后台任务调度
+func (s *Scheduler) SubmitInteractive(tasks []*Task, opts ...BatchOption) (*Batch, error) {
+	return s.submitBatch(LaneInteractive, tasks, opts)
+}
+
+// SubmitBackground 提交一批后台任务，仅在没有更高优先级任务等待时调度
+func (s *Scheduler) SubmitBackground(tasks []*Task, opts ...BatchOption) (*Batch, error) {
+	return s.submitBatch(LaneBackground, tasks, opts)
+}
+
+// laneOpen 返回通道是否可调度，达到配额时返回false
+func (s *Scheduler) laneOpen(lane Lane) bool {
+	quota := s.laneQuota[lane]
+	return quota <= 0 || s.laneInflight[lane].Load() < int64(quota)
+}
+
+// nextTask 按优先级取出下一个任务，调度器停止时返回false
+// home 为工作窃取模式下调度goroutine的分片，单调度goroutine时为 noShard
+func (s *Scheduler) nextTask(stop <-chan struct{}, home int) (*Task, bool) {
+	pauseChanged := s.pauseChanged
+	if home != noShard {
+		pauseChanged = s.shardPaused[home]
+	}
+	idle := false
+	for {
+		// 暂停期间不取任务，直到恢复或停止
+		if s.paused.Load() {
+			select {
+			case <-pauseChanged:
+				continue
+			case <-stop:
+				return nil, false
+			}
+		}
+
+		for _, lane := range laneOrder {
+			if task, ok := s.takeTask(lane, home); ok {
+				if s.tenants != nil {
+					s.tenants.start(task.Tenant)
+					task.tenantHeld = true
+				}
+				// 还有排队的任务时唤醒另一个空闲的调度goroutine
+				if home != noShard && s.queues[lane].Len() > 0 {
+					select {
+					case s.queued <- struct{}{}:
+					default:
+					}
+				}
+				s.recordDecision(task, lane, idle)
+				return task, true
+			}
+		}
+
+		// 所有可调度通道为空时等待新任务，配额释放后重新按优先级选择
+		select {
+		case <-s.queued:
+			idle = true
+		case <-s.shardSignal(home):
+			idle = true
+		case <-s.wake[LaneInteractive]:
+			idle = true
+		case <-s.wake[LaneNormal]:
+			idle = true
+		case <-s.wake[LaneBackground]:
+			idle = true
+		case <-s.quotaReleased:
+		case <-pauseChanged:
+		case <-stop:
+			return nil, false
+		}
+	}
+}
+
+// takeTask 通道未达到配额时取出任务并计入执行中
+// 多个调度goroutine并发取任务时，有配额的通道在 laneMu 下检查和计数，避免超出配额
+func (s *Scheduler) takeTask(lane Lane, home int) (*Task, bool) {
+	if home != noShard && s.laneQuota[lane] > 0 {
+		s.laneMu.Lock()
+		defer s.laneMu.Unlock()
+	}
+	if !s.laneOpen(lane) {
+		return nil, false
+	}
+	task, ok := s.popTask(lane, home)
+	if ok {
+		s.laneInflight[lane].Add(1)
+	}
+	return task, ok
+}
+
+// unpopTask 将已取出但因停止未能执行的任务放回队列，归还取出时占用的通道配额和租户名额
+// 重新启动后任务照常调度；队列已满时任务以 ErrSchedulerStopped 结束
+func (s *Scheduler) unpopTask(t *Task) {
+	s.releaseLane(t.lane)
+	if t.tenantHeld {
+		t.tenantHeld = false
+		s.releaseTenant(t.Tenant)
+	}
+	if err := s.queues[t.lane].Push(t); err != nil {
+		s.skipTask(t, ErrSchedulerStopped)
+	}
+}
+
+// releaseLane 任务完成后释放通道配额
+func (s *Scheduler) releaseLane(lane Lane) {
+	s.laneInflight[lane].Add(-1)
+	if s.laneQuota[lane] > 0 {
+		select {
+		case s.quotaReleased <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package fastscheduler
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_InteractiveBeforeBackground(t *testing.T) {
+	scheduler := NewScheduler(1, 10)
+	defer scheduler.Stop()
+
+	// 占住唯一的worker，让后续任务在队列中等待
+	release := make(chan struct{})
+	blocker, err := scheduler.SubmitBatch([]*Task{{
+		ID: "blocker",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			<-release
+			return TaskResult{HTTPCode: 200}, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	var mu sync.Mutex
+	var order []string
+	record := func(id string) *Task {
+		return &Task{
+			ID: id,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				mu.Lock()
+				order = append(order, id)
+				mu.Unlock()
+				return TaskResult{HTTPCode: 200}, nil
+			},
+		}
+	}
+
+	background, err := scheduler.SubmitBackground([]*Task{record("background")})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	interactive, err := scheduler.SubmitInteractive([]*Task{record("interactive")})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	close(release)
+
+	blocker.Wait()
+	background.Wait()
+	interactive.Wait()
+
+	if len(order) != 2 || order[0] != "interactive" {
+		t.Errorf("Expected interactive task to run first, got %v", order)
+	}
+}
+
+func TestScheduler_LaneQuota(t *testing.T) {
+	scheduler := NewScheduler(5, 10, WithLaneQuota(LaneBackground, 1))
+	defer scheduler.Stop()
+
+	var running, maxRunning atomic.Int32
+	var tasks []*Task
+	for i := 0; i < 3; i++ {
+		tasks = append(tasks, &Task{
+			ID: "background",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				n := running.Add(1)
+				for {
+					m := maxRunning.Load()
+					if n <= m || maxRunning.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				running.Add(-1)
+				return TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+			},
+		})
+	}
+
+	batch, err := scheduler.SubmitBackground(tasks)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	if maxRunning.Load() != 1 {
+		t.Errorf("Expected at most 1 concurrent background task, got %d", maxRunning.Load())
+	}
+}
@@ -0,0 +1,98 @@
+package fastscheduler
+
+import (
+	"context"
+	"sync"
+)
+
+// WithLeasePool 注册名为 name、容量为 capacity 的租约池，例如数据库连接或许可证
+// 任务在 Execute 中通过 Lease 获取租约，执行结束时自动归还
+func WithLeasePool(name string, capacity int) Option {
+	return func(s *Scheduler) {
+		if capacity <= 0 {
+			return
+		}
+		if s.leasePools == nil {
+			s.leasePools = make(map[string]chan struct{})
+		}
+		s.leasePools[name] = make(chan struct{}, capacity)
+	}
+}
+
+// leaseKey 任务上下文中租约集合的键
+type leaseKey struct{}
+
+// leaseSet 单次任务执行持有的租约
+type leaseSet struct {
+	pools  map[string]chan struct{}
+	mu     sync.Mutex
+	held   []*leaseHold
+	closed bool
+}
+
+// leaseHold 一份已获取的租约
+type leaseHold struct {
+	pool chan struct{}
+	once sync.Once
+}
+
+// release 归还租约，可重复调用
+func (h *leaseHold) release() {
+	h.once.Do(func() {
+		<-h.pool
+	})
+}
+
+// Lease 在任务执行期间获取名为 name 的租约，池已满时等待
+// 租约在任务结束(完成、取消或panic)时自动归还，也可以调用返回的函数提前归还。
+// 等待期间 ctx 结束时不持有任何租约并返回 ctx.Err()；
+// 不在调度器执行的任务中调用时返回 ErrNoLeaseScope，池不存在时返回 ErrUnknownLease
+func Lease(ctx context.Context, name string) (release func(), err error) {
+	ls, _ := ctx.Value(leaseKey{}).(*leaseSet)
+	if ls == nil {
+		return nil, ErrNoLeaseScope
+	}
+	pool, ok := ls.pools[name]
+	if !ok {
+		return nil, ErrUnknownLease
+	}
+
+	select {
+	case pool <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	hold := &leaseHold{pool: pool}
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if ls.closed {
+		// 任务已结束(例如对冲中落败的执行仍在运行)，不再持有新租约
+		hold.release()
+		return nil, ErrNoLeaseScope
+	}
+	ls.held = append(ls.held, hold)
+	return hold.release, nil
+}
+
+// withLeases 为一次任务执行创建租约集合，返回的函数归还所有租约
+func (s *Scheduler) withLeases(ctx context.Context) (context.Context, func()) {
+	if s.leasePools == nil {
+		return ctx, func() {}
+	}
+	ls := &leaseSet{pools: s.leasePools}
+	return context.WithValue(ctx, leaseKey{}, ls), ls.releaseAll
+}
+
+// releaseAll 归还所有租约并拒绝之后的获取
+func (ls *leaseSet) releaseAll() {
+	ls.mu.Lock()
+	held := ls.held
+	ls.held = nil
+	ls.closed = true
+	ls.mu.Unlock()
+
+	for _, h := range held {
+		h.release()
+	}
+}
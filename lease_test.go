@@ -0,0 +1,105 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLease(t *testing.T) {
+	scheduler := NewScheduler(5, 10, WithLeasePool("db", 1))
+	defer scheduler.Stop()
+
+	var holders, maxHolders atomic.Int32
+	useConn := func(ctx context.Context) (TaskResult, error) {
+		// 不调用 release，依赖任务结束时自动归还
+		if _, err := Lease(ctx, "db"); err != nil {
+			return TaskResult{}, err
+		}
+		n := holders.Add(1)
+		for {
+			m := maxHolders.Load()
+			if n <= m || maxHolders.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		holders.Add(-1)
+		return TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+	}
+
+	batch, err := scheduler.SubmitBatch([]*Task{
+		{ID: "q1", Execute: useConn},
+		{ID: "q2", Execute: useConn},
+		{ID: "q3", Execute: useConn},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	if errors.Is(batch.Err(), ErrNoLeaseScope) {
+		t.Fatalf("Unexpected lease error: %v", batch.Err())
+	}
+	if maxHolders.Load() != 1 {
+		t.Errorf("Expected at most 1 concurrent lease holder, got %d", maxHolders.Load())
+	}
+}
+
+func TestLease_CancelledWhileWaiting(t *testing.T) {
+	scheduler := NewScheduler(5, 10, WithLeasePool("license", 1))
+	defer scheduler.Stop()
+
+	waiting := make(chan struct{})
+	batch, err := scheduler.SubmitBatch([]*Task{
+		{
+			ID: "winner",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				if _, err := Lease(ctx, "license"); err != nil {
+					return TaskResult{}, err
+				}
+				<-waiting
+				return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+			},
+		},
+		{
+			ID: "loser",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				time.Sleep(10 * time.Millisecond)
+				close(waiting)
+				// 等待租约期间同组任务成功，获取被取消或在归还后成功，都不应泄漏
+				_, err := Lease(ctx, "license")
+				return TaskResult{}, err
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	// 所有租约都应已归还
+	future, err := scheduler.Submit(&Task{
+		ID: "next",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			ctx, cancel := context.WithTimeout(ctx, time.Second)
+			defer cancel()
+			if _, err := Lease(ctx, "license"); err != nil {
+				return TaskResult{}, err
+			}
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if r := future.Result(); r.Err != nil {
+		t.Errorf("Lease leaked: %v", r.Err)
+	}
+
+	if _, err := Lease(context.Background(), "license"); !errors.Is(err, ErrNoLeaseScope) {
+		t.Errorf("Expected ErrNoLeaseScope outside task, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package fastscheduler
+
+// State 表示调度器的生命周期状态
+type State int32
+
+const (
+	// StateNew 已创建但尚未启动
+	StateNew State = iota
+	// StateRunning 正在调度任务
+	StateRunning
+	// StateDraining 停止中，不再调度新任务，等待执行中的任务完成
+	StateDraining
+	// StateStopped 已停止，可以通过 Start 重新启动
+	StateStopped
+)
+
+// String 返回状态名称
+func (st State) String() string {
+	switch st {
+	case StateNew:
+		return "new"
+	case StateRunning:
+		return "running"
+	case StateDraining:
+		return "draining"
+	case StateStopped:
+		return "stopped"
+	default:
+		return "unknown"
+	}
+}
+
+// WithManualStart 创建调度器后不自动启动，需要显式调用 Start
+func WithManualStart() Option {
+	return func(s *Scheduler) {
+		s.autoStart = false
+	}
+}
+
+// stopped 返回调度器是否正在停止或已停止
+func (s *Scheduler) stopped() bool {
+	st := s.State()
+	return st == StateDraining || st == StateStopped
+}
+
+// stopSignal 返回当前运行周期的停止信号，未启动时返回nil
+func (s *Scheduler) stopSignal() <-chan struct{} {
+	s.stopMu.RLock()
+	defer s.stopMu.RUnlock()
+	return s.stopChan
+}
+
+// State 返回调度器当前状态
+func (s *Scheduler) State() State {
+	return State(s.state.Load())
+}
+
+// Start 启动调度器，已在运行时不做任何操作
+// 停止后的调度器可以再次启动，队列中尚未调度的任务会继续执行
+func (s *Scheduler) Start() {
+	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
+
+	if s.State() == StateRunning {
+		return
+	}
+	s.start()
+	s.state.Store(int32(StateRunning))
+}
+
+// Stop 停止调度器，等待执行中的任务完成后返回
+// 重复调用是安全的；队列中尚未调度的任务保留到下次 Start
+func (s *Scheduler) Stop() {
+	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
+
+	if s.State() != StateRunning {
+		return
+	}
+	s.state.Store(int32(StateDraining))
+	close(s.stopChan)
+	s.dispatcher.Wait()
+	s.wg.Wait()
+	s.state.Store(int32(StateStopped))
+}
+
+// Pause 暂停调度新任务，执行中的任务继续完成，队列中的任务保留
+// 暂停期间仍然可以提交任务，它们在 Resume 后按优先级调度；暂停状态在 Stop/Start 后保持
+func (s *Scheduler) Pause() {
+	if s.paused.CompareAndSwap(false, true) {
+		s.signalPause()
+	}
+}
+
+// Resume 恢复调度，未暂停时不做任何操作
+func (s *Scheduler) Resume() {
+	if s.paused.CompareAndSwap(true, false) {
+		s.signalPause()
+		if s.synchronous() {
+			s.runInline()
+		}
+	}
+}
+
+// Paused 返回调度器是否处于暂停状态
+func (s *Scheduler) Paused() bool {
+	return s.paused.Load()
+}
+
+// signalPause 唤醒调度goroutine重新检查暂停状态
+func (s *Scheduler) signalPause() {
+	select {
+	case s.pauseChanged <- struct{}{}:
+	default:
+	}
+	for _, ch := range s.shardPaused {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+	s.signalBulkheads()
+}
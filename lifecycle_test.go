@@ -0,0 +1,189 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScheduler_Lifecycle(t *testing.T) {
+	scheduler := NewScheduler(2, 10, WithManualStart())
+	if scheduler.State() != StateNew {
+		t.Errorf("Expected state new, got %s", scheduler.State())
+	}
+
+	okTask := &Task{
+		ID: "ok",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	}
+
+	// 启动前提交的任务在启动后执行
+	batch, err := scheduler.SubmitBatch([]*Task{okTask})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	select {
+	case <-batch.group.done:
+		t.Fatal("Task should not run before Start")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	scheduler.Start()
+	scheduler.Start() // 重复启动不做任何操作
+	if scheduler.State() != StateRunning {
+		t.Errorf("Expected state running, got %s", scheduler.State())
+	}
+	batch.Wait()
+	if !batch.IsSuccess() {
+		t.Error("Expected task to run after Start")
+	}
+
+	scheduler.Stop()
+	scheduler.Stop() // 重复停止不会panic
+	if scheduler.State() != StateStopped {
+		t.Errorf("Expected state stopped, got %s", scheduler.State())
+	}
+
+	// 停止后提交返回 ErrSchedulerStopped
+	if _, err := scheduler.SubmitBatch([]*Task{okTask}); !errors.Is(err, ErrSchedulerStopped) {
+		t.Errorf("Expected ErrSchedulerStopped, got %v", err)
+	}
+
+	// 重新启动后可以继续提交
+	scheduler.Start()
+	defer scheduler.Stop()
+	batch, err = scheduler.SubmitBatch([]*Task{okTask})
+	if err != nil {
+		t.Fatalf("Submit after restart failed: %v", err)
+	}
+	batch.Wait()
+	if !batch.IsSuccess() {
+		t.Error("Expected task to run after restart")
+	}
+}
+
+func TestScheduler_StopWhileQueueFull(t *testing.T) {
+	scheduler := NewScheduler(1, 1)
+
+	release := make(chan struct{})
+	blocking := func(ctx context.Context) (TaskResult, error) {
+		<-release
+		return TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+	}
+
+	// 一个任务占住worker，一个占满队列，第三个阻塞在入队
+	submitted := make(chan error, 1)
+	var batch *Batch
+	go func() {
+		var err error
+		batch, err = scheduler.SubmitBatch([]*Task{
+			{ID: "running", Execute: blocking},
+			{ID: "queued", Execute: blocking},
+			{ID: "blocked", Execute: blocking},
+		})
+		submitted <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+	}()
+	scheduler.Stop()
+
+	select {
+	case err := <-submitted:
+		if !errors.Is(err, ErrSchedulerStopped) {
+			t.Errorf("Expected ErrSchedulerStopped, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SubmitBatch stayed blocked after Stop")
+	}
+
+	// 未入队的任务以错误结果完成
+	if done, _ := batch.Progress(); done < 2 {
+		t.Errorf("Expected running and rejected tasks to be completed, got %d", done)
+	}
+}
+
+func TestScheduler_PauseResume(t *testing.T) {
+	scheduler := NewScheduler(2, 10)
+	defer scheduler.Stop()
+
+	scheduler.Pause()
+	if !scheduler.Paused() {
+		t.Fatal("Expected scheduler to be paused")
+	}
+	executed := make(chan struct{}, 1)
+	future, err := scheduler.Submit(&Task{
+		ID: "queued",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			executed <- struct{}{}
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	select {
+	case <-executed:
+		t.Fatal("Task should not run while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if got := scheduler.Stats().Lanes[1].Queued; got != 1 {
+		t.Errorf("Expected task to stay queued, got %d", got)
+	}
+
+	scheduler.Resume()
+	select {
+	case <-executed:
+	case <-time.After(time.Second):
+		t.Fatal("Task did not run after Resume")
+	}
+	future.Result()
+}
+
+func TestScheduler_BaseContext(t *testing.T) {
+	type ctxKey struct{}
+	base, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "app"))
+	scheduler := NewScheduler(2, 10, WithBaseContext(base))
+	defer scheduler.Stop()
+
+	started := make(chan struct{})
+	batch, err := scheduler.SubmitBatch([]*Task{{
+		ID: "long",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			if ctx.Value(ctxKey{}) != "app" {
+				t.Error("Expected task context to carry base context values")
+			}
+			close(started)
+			<-ctx.Done()
+			return TaskResult{}, ctx.Err()
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	<-started
+
+	// 根上下文取消后批次被取消，调度器随之停止
+	cancel()
+	batch.Wait()
+	if batch.Outcome() != OutcomeCancelled {
+		t.Errorf("Expected cancelled batch, got %s", batch.Outcome())
+	}
+	deadline := time.Now().Add(time.Second)
+	for scheduler.State() != StateStopped {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected scheduler to stop, state %s", scheduler.State())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if _, err := scheduler.SubmitBatch([]*Task{{ID: "late"}}); !errors.Is(err, ErrSchedulerStopped) {
+		t.Errorf("Expected ErrSchedulerStopped, got %v", err)
+	}
+}
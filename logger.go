@@ -0,0 +1,78 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// WithLogger 使用 l 输出结构化日志，默认不输出任何日志
+// 任务事件(入队、开始、结束、重试、取消、panic)带有 batch_id、task_id、lane、attempt 属性，
+// 批次事件(创建、完成)带有 batch_id、lane 属性；逐个任务的事件为 Debug 级别，
+// 失败和重试为 Warn，panic 为 Error，批次完成为 Info
+func WithLogger(l *slog.Logger) Option {
+	return func(s *Scheduler) {
+		s.logger = l
+	}
+}
+
+// logEnabled 返回是否需要输出该级别的日志
+func (s *Scheduler) logEnabled(level slog.Level) bool {
+	return s.logger != nil && s.logger.Enabled(context.Background(), level)
+}
+
+// logTask 输出任务事件
+func (s *Scheduler) logTask(level slog.Level, msg string, t *Task, attrs ...slog.Attr) {
+	if !s.logEnabled(level) {
+		return
+	}
+	s.logger.LogAttrs(context.Background(), level, msg, append(taskAttrs(t), attrs...)...)
+}
+
+// taskAttrs 返回任务事件的公共属性
+func taskAttrs(t *Task) []slog.Attr {
+	attrs := make([]slog.Attr, 0, 4)
+	if t.group != nil {
+		attrs = append(attrs, slog.String("batch_id", t.group.id))
+	}
+	return append(attrs,
+		slog.String("task_id", t.ID),
+		slog.String("lane", t.lane.String()),
+		slog.Int("attempt", t.attempts),
+	)
+}
+
+// logTaskFinished 按结果输出任务结束或取消事件
+func (s *Scheduler) logTaskFinished(t *Task, result TaskResult) {
+	if s.logger == nil {
+		return
+	}
+	attrs := []slog.Attr{slog.Int("http_code", result.HTTPCode)}
+	if !t.startedAt.IsZero() {
+		attrs = append(attrs, slog.Duration("duration", s.since(t.startedAt)))
+	}
+	switch {
+	case isSuccess(result):
+		s.logTask(slog.LevelDebug, "task finished", t, append(attrs, slog.Bool("success", true))...)
+	case errors.Is(result.Err, context.Canceled):
+		s.logTask(slog.LevelDebug, "task cancelled", t, attrs...)
+	default:
+		attrs = append(attrs, slog.Bool("success", false), slog.Int("business_code", result.BusinessCode))
+		if result.Err != nil {
+			attrs = append(attrs, slog.Any("error", result.Err))
+		}
+		s.logTask(slog.LevelWarn, "task finished", t, attrs...)
+	}
+}
+
+// logBatch 输出批次事件
+func (s *Scheduler) logBatch(level slog.Level, msg string, g *taskGroup, attrs ...slog.Attr) {
+	if !s.logEnabled(level) {
+		return
+	}
+	base := []slog.Attr{
+		slog.String("batch_id", g.id),
+		slog.String("lane", g.lane.String()),
+	}
+	s.logger.LogAttrs(context.Background(), level, msg, append(base, attrs...)...)
+}
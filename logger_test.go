@@ -0,0 +1,89 @@
+package fastscheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// syncBuffer 并发安全的日志输出
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestScheduler_Logger(t *testing.T) {
+	var out syncBuffer
+	logger := slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	scheduler := NewScheduler(2, 10, WithLogger(logger))
+	defer scheduler.Stop()
+
+	attempts := 0
+	batch, err := scheduler.SubmitBatch([]*Task{
+		{
+			ID: "flaky",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				attempts++
+				if attempts == 1 {
+					return TaskResult{HTTPCode: 503}, nil
+				}
+				return TaskResult{HTTPCode: 500}, nil
+			},
+			Retry: &RetryPolicy{MaxAttempts: 2},
+		},
+		{
+			ID: "broken",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				panic("boom")
+			},
+		},
+	}, WithBatchID("logged"))
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	batch.Wait()
+	for r := range batch.ResultsChan() {
+		if r.TaskID == "broken" && !errors.Is(r.Err, ErrTaskPanicked) {
+			t.Errorf("Expected ErrTaskPanicked, got %v", r.Err)
+		}
+	}
+	// 批次完成事件在结果流关闭后输出
+	<-batch.group.done
+
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid log line %q: %v", line, err)
+		}
+		if entry["batch_id"] != "logged" {
+			t.Errorf("Log entry without batch_id: %s", line)
+		}
+		seen[entry["msg"].(string)] = true
+	}
+	for _, msg := range []string{
+		"batch created", "task enqueued", "task started", "task retried",
+		"task panicked", "task finished", "batch completed",
+	} {
+		if !seen[msg] {
+			t.Errorf("Missing log event %q", msg)
+		}
+	}
+}
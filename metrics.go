@@ -0,0 +1,129 @@
+package fastscheduler
+
+import (
+	"strings"
+	"time"
+)
+
+// defaultMetricsInterval WithMetricsSink 未指定间隔时的上报间隔
+const defaultMetricsInterval = 10 * time.Second
+
+// Tag 指标标签
+type Tag struct {
+	Key, Value string
+}
+
+// MetricsSink 接收调度器指标，例如写入 expvar、statsd 或 Prometheus
+// 调度器按间隔从内部计数器和直方图汇总指标后调用，同一时间只有一个goroutine调用
+type MetricsSink interface {
+	// Gauge 上报瞬时值，例如排队数、延迟百分位
+	Gauge(name string, value float64, tags ...Tag)
+	// Count 上报计数器自上次上报以来的增量
+	Count(name string, delta int64, tags ...Tag)
+}
+
+// WithMetricsSink 每隔 interval(默认10秒)向 sink 上报一次指标，调度器停止时再上报一次
+// 指标名称：
+//
+//	workers.busy、workers.size                       worker 使用情况
+//	lane.queued、lane.inflight (标签 lane)           各通道排队和执行数
+//	tasks.executed、tasks.timeouts、results.dropped 计数器
+//	deadline.tracked、deadline.missed               完成时限计数器
+//	queue_wait.p50/p95/p99/max、execution.p50/p95/p99/max (秒) 延迟分布，启用 WithLatencyByKey 时另有带 key 标签的同名指标
+func WithMetricsSink(sink MetricsSink, interval time.Duration) Option {
+	return func(s *Scheduler) {
+		if interval <= 0 {
+			interval = defaultMetricsInterval
+		}
+		s.metrics = &metricsReporter{sink: sink, interval: interval}
+	}
+}
+
+// metricsReporter 按间隔上报指标，记录上次上报的计数器以计算增量
+type metricsReporter struct {
+	sink     MetricsSink
+	interval time.Duration
+	last     map[string]int64
+}
+
+// run 按间隔上报，stop 关闭时最后上报一次后退出
+func (r *metricsReporter) run(s *Scheduler, stop <-chan struct{}) {
+	ticker := s.clock.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C():
+			r.report(s.Stats())
+		case <-stop:
+			r.report(s.Stats())
+			return
+		}
+	}
+}
+
+// report 上报一次统计快照
+func (r *metricsReporter) report(stats Stats) {
+	r.sink.Gauge("workers.busy", float64(stats.Workers.Busy))
+	r.sink.Gauge("workers.size", float64(stats.Workers.Size))
+	for _, lane := range stats.Lanes {
+		tag := Tag{"lane", lane.Lane.String()}
+		r.sink.Gauge("lane.queued", float64(lane.Queued), tag)
+		r.sink.Gauge("lane.inflight", float64(lane.Inflight), tag)
+	}
+
+	r.count("tasks.executed", stats.Timing.Execution.Count)
+	r.count("tasks.timeouts", stats.TaskTimeouts)
+	r.count("results.dropped", stats.DroppedResults)
+	r.count("deadline.tracked", stats.Deadline.Tracked)
+	r.count("deadline.missed", stats.Deadline.Missed)
+
+	r.latency("queue_wait", stats.Timing.QueueWait)
+	r.latency("execution", stats.Timing.Execution)
+	for key, timing := range stats.TimingByKey {
+		tag := Tag{"key", key}
+		r.latency("queue_wait", timing.QueueWait, tag)
+		r.latency("execution", timing.Execution, tag)
+	}
+}
+
+// count 上报累计值相对上次上报的增量
+func (r *metricsReporter) count(name string, total int64) {
+	if r.last == nil {
+		r.last = make(map[string]int64)
+	}
+	if delta := total - r.last[name]; delta != 0 {
+		r.sink.Count(name, delta)
+	}
+	r.last[name] = total
+}
+
+// latency 以秒为单位上报延迟分布
+func (r *metricsReporter) latency(prefix string, l LatencyStats, tags ...Tag) {
+	if l.Count == 0 {
+		return
+	}
+	r.sink.Gauge(prefix+".p50", l.P50.Seconds(), tags...)
+	r.sink.Gauge(prefix+".p95", l.P95.Seconds(), tags...)
+	r.sink.Gauge(prefix+".p99", l.P99.Seconds(), tags...)
+	r.sink.Gauge(prefix+".max", l.Max.Seconds(), tags...)
+}
+
+// MetricName 返回带标签的指标名，格式为 name{k=v,...}，供不支持标签的后端使用
+func MetricName(name string, tags ...Tag) string {
+	if len(tags) == 0 {
+		return name
+	}
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteByte('{')
+	for i, t := range tags {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(t.Key)
+		b.WriteByte('=')
+		b.WriteString(t.Value)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
@@ -0,0 +1,121 @@
+// Package metrics 提供 fastscheduler.MetricsSink 的 expvar 和 statsd/DogStatsD 实现
+//
+//	sink, err := metrics.NewStatsd(metrics.StatsdConfig{Addr: "127.0.0.1:8125", Prefix: "fanout", DogStatsD: true})
+//	scheduler := fastscheduler.NewScheduler(10, 100, fastscheduler.WithMetricsSink(sink, 10*time.Second))
+package metrics
+
+import (
+	"expvar"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+// Expvar 将指标发布到 expvar，可以通过 /debug/vars 查看
+// 带标签的指标名为 name{k=v}，计数器为累计值
+type Expvar struct {
+	m *expvar.Map
+}
+
+// NewExpvar 在 expvar 中发布名为 name 的指标集合，同名集合已存在时复用
+func NewExpvar(name string) *Expvar {
+	if m, ok := expvar.Get(name).(*expvar.Map); ok {
+		return &Expvar{m: m}
+	}
+	return &Expvar{m: expvar.NewMap(name)}
+}
+
+// Gauge 实现 fastscheduler.MetricsSink
+func (e *Expvar) Gauge(name string, value float64, tags ...fastscheduler.Tag) {
+	f := new(expvar.Float)
+	f.Set(value)
+	e.m.Set(fastscheduler.MetricName(name, tags...), f)
+}
+
+// Count 实现 fastscheduler.MetricsSink
+func (e *Expvar) Count(name string, delta int64, tags ...fastscheduler.Tag) {
+	e.m.Add(fastscheduler.MetricName(name, tags...), delta)
+}
+
+// StatsdConfig statsd 上报配置
+type StatsdConfig struct {
+	// Addr statsd 的 UDP 地址，例如 127.0.0.1:8125
+	Addr string
+	// Prefix 指标名前缀，例如 "fanout" 产生 fanout.workers.busy
+	Prefix string
+	// DogStatsD 为 true 时以 Datadog 扩展格式 |#k:v 发送标签，
+	// 否则标签值依次追加到指标名，例如 lane.queued.normal
+	DogStatsD bool
+	// Tags 附加到每个指标的标签，例如服务名、环境
+	Tags []fastscheduler.Tag
+}
+
+// Statsd 以 statsd 文本协议通过 UDP 发送指标，发送失败时丢弃
+type Statsd struct {
+	cfg  StatsdConfig
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewStatsd 创建 statsd 上报
+func NewStatsd(cfg StatsdConfig) (*Statsd, error) {
+	conn, err := net.Dial("udp", cfg.Addr)
+	if err != nil {
+		return nil, err
+	}
+	return &Statsd{cfg: cfg, conn: conn}, nil
+}
+
+// Gauge 实现 fastscheduler.MetricsSink
+func (s *Statsd) Gauge(name string, value float64, tags ...fastscheduler.Tag) {
+	s.send(name, strconv.FormatFloat(value, 'f', -1, 64), "g", tags)
+}
+
+// Count 实现 fastscheduler.MetricsSink
+func (s *Statsd) Count(name string, delta int64, tags ...fastscheduler.Tag) {
+	s.send(name, strconv.FormatInt(delta, 10), "c", tags)
+}
+
+// Close 关闭连接
+func (s *Statsd) Close() error {
+	return s.conn.Close()
+}
+
+// send 发送一行 name:value|type
+func (s *Statsd) send(name, value, kind string, tags []fastscheduler.Tag) {
+	tags = append(append([]fastscheduler.Tag(nil), s.cfg.Tags...), tags...)
+	var b strings.Builder
+	if s.cfg.Prefix != "" {
+		b.WriteString(s.cfg.Prefix)
+		b.WriteByte('.')
+	}
+	b.WriteString(name)
+	if !s.cfg.DogStatsD {
+		for _, t := range tags {
+			b.WriteByte('.')
+			b.WriteString(t.Value)
+		}
+	}
+	b.WriteByte(':')
+	b.WriteString(value)
+	b.WriteByte('|')
+	b.WriteString(kind)
+	if s.cfg.DogStatsD && len(tags) > 0 {
+		b.WriteString("|#")
+		for i, t := range tags {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(t.Key)
+			b.WriteByte(':')
+			b.WriteString(t.Value)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, _ = s.conn.Write([]byte(b.String()))
+}
@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"expvar"
+	"net"
+	"testing"
+	"time"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+func TestExpvar(t *testing.T) {
+	sink := NewExpvar("fastscheduler_test")
+	// 重复运行测试时复用同一个 expvar 集合，先清空
+	sink.m.Init()
+	sink.Gauge("lane.queued", 3, fastscheduler.Tag{Key: "lane", Value: "normal"})
+	sink.Count("tasks.executed", 2)
+	sink.Count("tasks.executed", 5)
+
+	m := expvar.Get("fastscheduler_test").(*expvar.Map)
+	if got := m.Get("lane.queued{lane=normal}").String(); got != "3" {
+		t.Errorf("lane.queued = %s, want 3", got)
+	}
+	if got := m.Get("tasks.executed").String(); got != "7" {
+		t.Errorf("tasks.executed = %s, want 7", got)
+	}
+	if NewExpvar("fastscheduler_test").m != m {
+		t.Error("Expected existing expvar map to be reused")
+	}
+}
+
+func TestStatsd(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket failed: %v", err)
+	}
+	defer conn.Close()
+
+	read := func() string {
+		buf := make([]byte, 512)
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("ReadFrom failed: %v", err)
+		}
+		return string(buf[:n])
+	}
+	lane := fastscheduler.Tag{Key: "lane", Value: "normal"}
+
+	plain, err := NewStatsd(StatsdConfig{Addr: conn.LocalAddr().String(), Prefix: "fanout"})
+	if err != nil {
+		t.Fatalf("NewStatsd failed: %v", err)
+	}
+	defer plain.Close()
+	plain.Gauge("lane.queued", 3, lane)
+	if got := read(); got != "fanout.lane.queued.normal:3|g" {
+		t.Errorf("Unexpected statsd line %q", got)
+	}
+
+	dog, err := NewStatsd(StatsdConfig{
+		Addr:      conn.LocalAddr().String(),
+		DogStatsD: true,
+		Tags:      []fastscheduler.Tag{{Key: "service", Value: "search"}},
+	})
+	if err != nil {
+		t.Fatalf("NewStatsd failed: %v", err)
+	}
+	defer dog.Close()
+	dog.Count("tasks.executed", 4, lane)
+	if got := read(); got != "tasks.executed:4|c|#service:search,lane:normal" {
+		t.Errorf("Unexpected dogstatsd line %q", got)
+	}
+}
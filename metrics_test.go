@@ -0,0 +1,59 @@
+package fastscheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSink 记录上报的指标
+type recordingSink struct {
+	mu     sync.Mutex
+	gauges map[string]float64
+	counts map[string]int64
+}
+
+func (r *recordingSink) Gauge(name string, value float64, tags ...Tag) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gauges[MetricName(name, tags...)] = value
+}
+
+func (r *recordingSink) Count(name string, delta int64, tags ...Tag) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counts[MetricName(name, tags...)] += delta
+}
+
+func TestScheduler_MetricsSink(t *testing.T) {
+	sink := &recordingSink{gauges: make(map[string]float64), counts: make(map[string]int64)}
+	scheduler := NewScheduler(2, 10, WithMetricsSink(sink, 10*time.Millisecond))
+
+	batch, err := scheduler.SubmitBatch([]*Task{
+		{ID: "a", Execute: func(ctx context.Context) (TaskResult, error) { return TaskResult{HTTPCode: 500}, nil }},
+		{ID: "b", Execute: func(ctx context.Context) (TaskResult, error) { return TaskResult{HTTPCode: 500}, nil }},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+	// 等待至少一次定期上报，停止时再上报一次
+	time.Sleep(30 * time.Millisecond)
+	scheduler.Stop()
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if got := sink.counts["tasks.executed"]; got != 2 {
+		t.Errorf("tasks.executed = %d, want 2", got)
+	}
+	if got := sink.gauges["workers.size"]; got != 2 {
+		t.Errorf("workers.size = %v, want 2", got)
+	}
+	if _, ok := sink.gauges["lane.queued{lane=normal}"]; !ok {
+		t.Errorf("Missing lane gauge, got %v", sink.gauges)
+	}
+	if _, ok := sink.gauges["execution.p99"]; !ok {
+		t.Errorf("Missing latency gauge, got %v", sink.gauges)
+	}
+}
@@ -0,0 +1,65 @@
+package fastscheduler
+
+import (
+	"context"
+	"net/http"
+)
+
+// requestBatchKey 请求上下文中批次的键
+type requestBatchKey struct{}
+
+// Middleware 返回HTTP中间件，为每个请求创建一个开放批次并放入请求上下文
+// 批次以请求上下文为父上下文，任务执行到底不会因某个任务成功而取消其他任务；
+// 处理函数返回或客户端断开时，批次中未完成的任务被取消。调度器已停止时返回 503
+func (s *Scheduler) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		batch, err := s.NewBatch(withParentContext(r.Context()), runToCompletion())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		defer func() {
+			batch.Close()
+			batch.group.cancel()
+		}()
+
+		ctx := context.WithValue(r.Context(), requestBatchKey{}, batch)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestBatch 返回 Middleware 为当前请求创建的批次，不在中间件内时返回nil
+func RequestBatch(ctx context.Context) *Batch {
+	batch, _ := ctx.Value(requestBatchKey{}).(*Batch)
+	return batch
+}
+
+// FanOut 将子请求加入当前请求的批次并等待全部完成，按任务顺序返回结果
+// 失败的子请求体现在对应结果中，只有无法提交时才返回错误；
+// 不在 Middleware 内时返回 ErrNoRequestBatch
+func FanOut(ctx context.Context, tasks ...*Task) ([]TaskResult, error) {
+	batch := RequestBatch(ctx)
+	if batch == nil {
+		return nil, ErrNoRequestBatch
+	}
+
+	chans := make([]chan TaskResult, len(tasks))
+	for i, task := range tasks {
+		chans[i] = make(chan TaskResult, 1)
+		t := *task
+		t.ResultChan = chans[i]
+		if err := batch.Add(&t); err != nil {
+			// 已加入的子请求由批次在请求结束时取消
+			return nil, err
+		}
+	}
+
+	results := make([]TaskResult, len(tasks))
+	for i, ch := range chans {
+		results[i] = <-ch
+		if tasks[i].ResultChan != nil {
+			deliver(tasks[i].ResultChan, results[i])
+		}
+	}
+	return results, nil
+}
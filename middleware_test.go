@@ -0,0 +1,70 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestScheduler_Middleware(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	abandoned := make(chan error, 1)
+	handler := scheduler.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var tasks []*Task
+		for i := 0; i < 3; i++ {
+			tasks = append(tasks, &Task{
+				ID: fmt.Sprintf("upstream-%d", i),
+				Execute: func(ctx context.Context) (TaskResult, error) {
+					return TaskResult{HTTPCode: 200, BusinessCode: 0, Data: i}, nil
+				},
+			})
+		}
+		results, err := FanOut(r.Context(), tasks...)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, res := range results {
+			fmt.Fprint(w, res.Data)
+		}
+
+		// 请求结束时仍在执行的子请求应被取消
+		if err := RequestBatch(r.Context()).Add(&Task{
+			ID: "abandoned",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				<-ctx.Done()
+				abandoned <- ctx.Err()
+				return TaskResult{}, ctx.Err()
+			},
+		}); err != nil {
+			t.Errorf("Add failed: %v", err)
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if body := rec.Body.String(); body != "012" {
+		t.Errorf("Expected results in task order, got %q", body)
+	}
+
+	select {
+	case err := <-abandoned:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Sub-request was not cancelled when the request ended")
+	}
+}
+
+func TestFanOut_WithoutMiddleware(t *testing.T) {
+	if _, err := FanOut(context.Background(), &Task{ID: "orphan"}); !errors.Is(err, ErrNoRequestBatch) {
+		t.Errorf("Expected ErrNoRequestBatch, got %v", err)
+	}
+}
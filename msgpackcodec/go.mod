@@ -0,0 +1,12 @@
+module github.com/hawkli-1994/fast-scheduler/msgpackcodec
+
+go 1.23.3
+
+require (
+	github.com/hawkli-1994/fast-scheduler v0.0.0
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+)
+
+require github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+
+replace github.com/hawkli-1994/fast-scheduler => ..
@@ -0,0 +1,38 @@
+// Package msgpackcodec 基于 github.com/vmihailenco/msgpack/v5 提供 MessagePack 格式的 fastscheduler.Codec
+//
+// 本包是独立的Go模块，只使用核心调度器时不需要引入 msgpack 库：
+//
+//	scheduler := fastscheduler.NewScheduler(10, 100, fastscheduler.WithCodec(msgpackcodec.Codec))
+package msgpackcodec
+
+import (
+	"bytes"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec MessagePack 编码：结构体按 msgpack 标签(其次 json 标签)编码为 map，time.Time 使用时间戳扩展类型；
+// 解码到 interface{} 时整数为 int64/uint64，map 为 map[string]interface{}
+var Codec fastscheduler.Codec = codec{}
+
+type codec struct{}
+
+func (codec) Name() string { return "msgpack" }
+
+func (codec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (codec) Unmarshal(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	dec.UseLooseInterfaceDecoding(true)
+	return dec.Decode(v)
+}
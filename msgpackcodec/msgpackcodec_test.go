@@ -0,0 +1,68 @@
+package msgpackcodec
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+type quote struct {
+	Provider string            `json:"provider"`
+	Cents    int64             `msgpack:"cents"`
+	Ratio    float64           `json:"ratio,omitempty"`
+	Tags     []string          `json:"tags"`
+	Meta     map[string]uint16 `json:"meta"`
+	At       time.Time         `json:"at"`
+	Ignored  string            `json:"-"`
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	original := quote{
+		Provider: "a",
+		Cents:    -1 << 40,
+		Tags:     []string{"x"},
+		Meta:     map[string]uint16{"retries": 3},
+		At:       time.Unix(1700000000, 123456789),
+		Ignored:  "dropped",
+	}
+	w, err := fastscheduler.EncodeResult(Codec, fastscheduler.TaskResult{TaskID: "quote", HTTPCode: 200, Data: original})
+	if err != nil {
+		t.Fatalf("EncodeResult failed: %v", err)
+	}
+	var decoded quote
+	if _, err := fastscheduler.DecodeResult(Codec, w, &decoded); err != nil {
+		t.Fatalf("DecodeResult failed: %v", err)
+	}
+	if !decoded.At.Equal(original.At) {
+		t.Errorf("Timestamp mismatch: %v", decoded.At)
+	}
+	decoded.At = original.At
+	original.Ignored = ""
+	if !reflect.DeepEqual(decoded, original) {
+		t.Errorf("Round trip mismatch:\n got %+v\nwant %+v", decoded, original)
+	}
+}
+
+func TestCodec_Wire(t *testing.T) {
+	// 结构体字段使用 json 标签作为键
+	data, err := Codec.Marshal(struct {
+		A int `json:"a"`
+	}{-1})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if want := []byte{0x81, 0xa1, 'a', 0xff}; !bytes.Equal(data, want) {
+		t.Errorf("Expected % x, got % x", want, data)
+	}
+
+	var v interface{}
+	if err := Codec.Unmarshal([]byte{0x92, 0xcd, 0x01, 0x00, 0xc3}, &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(v, []interface{}{uint64(256), true}) {
+		t.Errorf("Unexpected generic value %#v", v)
+	}
+}
@@ -0,0 +1,273 @@
+package fastscheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// defaultWebhookTimeout WebhookNotifier 未指定 Client 时的请求超时
+const defaultWebhookTimeout = 10 * time.Second
+
+// BatchSummary 批次的摘要
+type BatchSummary struct {
+	BatchID string
+	Lane    Lane
+	Outcome Outcome
+	// Total 批次任务总数
+	Total int
+	// Succeeded 是否有任务成功；Winner 第一个成功的结果，没有成功时为零值
+	Succeeded bool
+	Winner    TaskResult
+	// Created 批次创建的时间；Finished 最后一个任务完成的时间
+	Created  time.Time
+	Finished time.Time
+	// Duration 批次从创建到完成的耗时
+	Duration time.Duration
+	// WinnerLatency 从批次创建到第一个任务成功的耗时，没有成功时为0
+	WinnerLatency time.Duration
+	// Errors 失败任务的 *TaskError
+	Errors []error
+
+	// SucceededTasks、FailedTasks、CancelledTasks 成功、失败和被取消(批次或任务被取消、调度器停止)的任务数
+	SucceededTasks int
+	FailedTasks    int
+	CancelledTasks int
+	// SlowestLatency 耗时最长的任务从提交到结束的时长，SlowestTask 为其ID
+	SlowestLatency time.Duration
+	SlowestTask    string
+	// Abandoned WithCancelGrace 的宽限结束时仍在执行而被取消的任务ID
+	Abandoned []string
+}
+
+// summary 汇总任务组的摘要，批次未完成时 Finished 为零值，Duration 为截至当前的耗时
+func (g *taskGroup) summary() BatchSummary {
+	sum := BatchSummary{
+		BatchID: g.id,
+		Lane:    g.lane,
+		Outcome: g.outcome(),
+		Total:   int(g.total.Load()),
+		Created: g.created,
+	}
+	if sum.Outcome == OutcomePending {
+		sum.Duration = g.sched.since(g.created)
+	} else {
+		sum.Finished = g.finished
+		sum.Duration = g.finished.Sub(g.created)
+	}
+	// winner 在 succeeded 关闭前写入
+	select {
+	case <-g.succeeded:
+		sum.Succeeded = true
+		sum.Winner = g.winner
+		sum.WinnerLatency = g.winnerAt.Sub(g.created)
+	default:
+	}
+	g.errMu.Lock()
+	sum.Errors = append([]error(nil), g.errs...)
+	sum.SucceededTasks = g.tally.succeeded
+	sum.FailedTasks = g.tally.failed
+	sum.CancelledTasks = g.tally.cancelled
+	sum.SlowestLatency = g.tally.slowest
+	sum.SlowestTask = g.tally.slowestTask
+	sum.Abandoned = append([]string(nil), g.grace.abandoned...)
+	g.errMu.Unlock()
+	return sum
+}
+
+// batchTally 已完成任务的计数
+type batchTally struct {
+	succeeded, failed, cancelled int
+	slowest                      time.Duration
+	slowestTask                  string
+}
+
+// count 计入一个完成的任务
+func (g *taskGroup) count(result TaskResult) {
+	latency := result.Metrics.FinishedAt.Sub(result.Metrics.EnqueuedAt)
+	g.errMu.Lock()
+	defer g.errMu.Unlock()
+	switch {
+	case isSuccess(result):
+		g.tally.succeeded++
+	case isCancelled(result):
+		g.tally.cancelled++
+	default:
+		g.tally.failed++
+	}
+	if latency > g.tally.slowest || g.tally.slowestTask == "" {
+		g.tally.slowest = latency
+		g.tally.slowestTask = result.TaskID
+	}
+}
+
+// Summary 返回批次的摘要：各状态的任务数、总耗时、第一个成功的耗时和最慢任务的耗时
+// 批次未完成时只统计已完成的任务
+func (b *Batch) Summary() BatchSummary {
+	return b.group.summary()
+}
+
+// Notifier 批次完成时接收摘要，例如发送 webhook 或写入消息队列
+// Notify 在独立的goroutine中调用，不占用worker；返回的错误通过 WithLogger 的日志输出
+type Notifier interface {
+	Notify(ctx context.Context, summary BatchSummary) error
+}
+
+// NotifierFunc 将函数适配为 Notifier
+type NotifierFunc func(ctx context.Context, summary BatchSummary) error
+
+// Notify 实现 Notifier 接口
+func (f NotifierFunc) Notify(ctx context.Context, summary BatchSummary) error {
+	return f(ctx, summary)
+}
+
+// WithNotifier 批次完成时调用 n，可以多次使用注册多个 Notifier
+func WithNotifier(n Notifier) BatchOption {
+	return func(g *taskGroup) {
+		g.notifiers = append(g.notifiers, n)
+	}
+}
+
+// WithWebhook 批次完成时向 url POST JSON 格式的摘要，等同于 WithNotifier(&WebhookNotifier{URL: url, Codec: s.Codec()})
+// 获胜结果的 Data 按调度器 WithCodec 设置的编码写入
+func WithWebhook(url string) BatchOption {
+	return func(g *taskGroup) {
+		g.notifiers = append(g.notifiers, &WebhookNotifier{URL: url, Codec: g.sched.Codec()})
+	}
+}
+
+// WebhookNotifier 以 JSON 格式 POST 批次摘要，响应状态码不是 2xx 时返回错误
+type WebhookNotifier struct {
+	URL string
+	// Header 附加的请求头，例如认证信息
+	Header http.Header
+	// Client 发送请求的客户端，为nil时使用超时10秒的默认客户端
+	Client *http.Client
+	// Codec 编码获胜结果的 Data，为nil时使用 JSONCodec
+	// JSONCodec 的编码结果原样嵌入 data 字段，其他编码以 base64 字符串写入并在 codec 字段注明编码名称
+	Codec Codec
+}
+
+// webhookPayload webhook 请求体
+type webhookPayload struct {
+	BatchID         string         `json:"batch_id"`
+	Lane            string         `json:"lane"`
+	Outcome         string         `json:"outcome"`
+	Total           int            `json:"total"`
+	Created         time.Time      `json:"created"`
+	Finished        time.Time      `json:"finished"`
+	DurationMS      int64          `json:"duration_ms"`
+	WinnerLatencyMS int64          `json:"winner_latency_ms,omitempty"`
+	SlowestMS       int64          `json:"slowest_ms"`
+	SucceededTasks  int            `json:"succeeded_tasks"`
+	FailedTasks     int            `json:"failed_tasks"`
+	CancelledTasks  int            `json:"cancelled_tasks"`
+	Winner          *webhookResult `json:"winner,omitempty"`
+	Errors          []string       `json:"errors,omitempty"`
+}
+
+// webhookResult 成功结果的JSON形式
+type webhookResult struct {
+	TaskID       string          `json:"task_id"`
+	HTTPCode     int             `json:"http_code"`
+	BusinessCode int             `json:"business_code"`
+	Codec        string          `json:"codec,omitempty"`
+	Data         json.RawMessage `json:"data,omitempty"`
+	// DataError Data 无法编码时的原因，此时摘要仍然发送但不含 data
+	DataError string `json:"data_error,omitempty"`
+}
+
+// Notify 实现 Notifier 接口
+func (w *WebhookNotifier) Notify(ctx context.Context, summary BatchSummary) error {
+	payload := webhookPayload{
+		BatchID:        summary.BatchID,
+		Lane:           summary.Lane.String(),
+		Outcome:        summary.Outcome.String(),
+		Total:          summary.Total,
+		Created:        summary.Created,
+		Finished:       summary.Finished,
+		DurationMS:     summary.Duration.Milliseconds(),
+		SlowestMS:      summary.SlowestLatency.Milliseconds(),
+		SucceededTasks: summary.SucceededTasks,
+		FailedTasks:    summary.FailedTasks,
+		CancelledTasks: summary.CancelledTasks,
+	}
+	if summary.Succeeded {
+		payload.WinnerLatencyMS = summary.WinnerLatency.Milliseconds()
+		payload.Winner = w.encodeWinner(summary.Winner)
+	}
+	for _, err := range summary.Errors {
+		payload.Errors = append(payload.Errors, err.Error())
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, vs := range w.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultWebhookTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s: unexpected status %s", w.URL, resp.Status)
+	}
+	return nil
+}
+
+// encodeWinner 按 Codec 编码获胜结果，Data 编码失败时只发送结果的其余字段
+func (w *WebhookNotifier) encodeWinner(r TaskResult) *webhookResult {
+	codec := w.Codec
+	if codec == nil {
+		codec = JSONCodec
+	}
+	winner := &webhookResult{
+		TaskID:       r.TaskID,
+		HTTPCode:     r.HTTPCode,
+		BusinessCode: r.BusinessCode,
+	}
+	wire, err := EncodeResult(codec, r)
+	if err != nil {
+		winner.DataError = err.Error()
+		return winner
+	}
+	if wire.Data == nil {
+		return winner
+	}
+	winner.Codec = wire.Codec
+	if codec.Name() == JSONCodec.Name() && json.Valid(wire.Data) {
+		winner.Data = wire.Data
+		return winner
+	}
+	// 其他编码的结果是二进制数据，以 base64 字符串嵌入
+	winner.Data, _ = json.Marshal(wire.Data)
+	return winner
+}
+
+// notify 依次调用批次的 Notifier
+func (s *Scheduler) notify(g *taskGroup) {
+	summary := g.summary()
+	for _, n := range g.notifiers {
+		if err := n.Notify(context.Background(), summary); err != nil {
+			s.logBatch(slog.LevelWarn, "batch notification failed", g, slog.Any("error", err))
+		}
+	}
+}
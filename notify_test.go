@@ -0,0 +1,105 @@
+package fastscheduler
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBatch_Webhook(t *testing.T) {
+	payloads := make(chan map[string]any, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("Invalid webhook body: %v", err)
+		}
+		payloads <- p
+	}))
+	defer server.Close()
+
+	scheduler := NewScheduler(2, 10)
+	defer scheduler.Stop()
+
+	summaries := make(chan BatchSummary, 1)
+	batch, err := scheduler.SubmitBatch([]*Task{
+		{
+			ID: "winner",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 200, Data: "ok"}, nil
+			},
+		},
+	}, WithBatchID("notified"), WithWebhook(server.URL),
+		WithNotifier(NotifierFunc(func(ctx context.Context, s BatchSummary) error {
+			summaries <- s
+			return nil
+		})))
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	batch.Wait()
+
+	select {
+	case p := <-payloads:
+		if p["batch_id"] != "notified" || p["outcome"] != "succeeded" {
+			t.Errorf("Unexpected webhook payload %v", p)
+		}
+		winner, _ := p["winner"].(map[string]any)
+		if winner["task_id"] != "winner" || winner["data"] != "ok" {
+			t.Errorf("Unexpected winner in payload %v", p["winner"])
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for webhook")
+	}
+
+	select {
+	case s := <-summaries:
+		if !s.Succeeded || s.Winner.TaskID != "winner" || s.Total != 1 || s.Finished.Before(s.Created) {
+			t.Errorf("Unexpected summary %+v", s)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for notifier")
+	}
+}
+
+func TestWebhookNotifier_Codec(t *testing.T) {
+	payloads := make(chan map[string]any, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("Invalid webhook body: %v", err)
+		}
+		payloads <- p
+	}))
+	defer server.Close()
+
+	summary := BatchSummary{Succeeded: true, Winner: TaskResult{TaskID: "winner", HTTPCode: 200, Data: "ok"}}
+	notify := func(c Codec) map[string]any {
+		n := &WebhookNotifier{URL: server.URL, Codec: c}
+		if err := n.Notify(context.Background(), summary); err != nil {
+			t.Fatalf("Notify failed: %v", err)
+		}
+		winner, _ := (<-payloads)["winner"].(map[string]any)
+		return winner
+	}
+
+	winner := notify(GobCodec)
+	encoded, _ := winner["data"].(string)
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("Expected base64 data, got %v", winner["data"])
+	}
+	var data string
+	if err := GobCodec.Unmarshal(raw, &data); err != nil || data != "ok" || winner["codec"] != "gob" {
+		t.Errorf("Unexpected gob winner %v (decoded %q, %v)", winner, data, err)
+	}
+
+	// 字符串没有实现 BinaryMarshaler，编码失败时仍发送摘要
+	winner = notify(BinaryCodec)
+	if winner["task_id"] != "winner" || winner["data"] != nil || winner["data_error"] == nil {
+		t.Errorf("Expected winner without data but with data_error, got %v", winner)
+	}
+}
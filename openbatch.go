@@ -0,0 +1,161 @@
+package fastscheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// NewBatch 创建一个开放批次，任务可以在执行过程中通过 Add 陆续加入
+// 开放批次与 SubmitBatch 创建的批次语义相同：第一个成功的任务会取消同组其他任务。
+// 调用 Close 之后，所有已加入的任务完成时批次才完成，Wait 和 ResultsChan 才会返回
+func (s *Scheduler) NewBatch(opts ...BatchOption) (*Batch, error) {
+	return s.newOpenBatch(nil, opts)
+}
+
+// NewBatch 通过受限视图创建开放批次，Add 加入的任务同样受视图的并发和速率限制
+func (sc *Scope) NewBatch(opts ...BatchOption) (*Batch, error) {
+	return sc.parent.newOpenBatch(sc, opts)
+}
+
+// newOpenBatch 创建开放批次，scope 非nil时任务经视图准入
+func (s *Scheduler) newOpenBatch(scope *Scope, opts []BatchOption) (*Batch, error) {
+	if s.stopped() {
+		return nil, ErrSchedulerStopped
+	}
+	batch, err := s.newBatch(LaneNormal, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	group := batch.group
+	group.scope = scope
+	group.stream = &resultStream{notify: make(chan struct{}, 1)}
+	// 未关闭的批次占用一个计数，Close 时释放
+	group.wg.Add(1)
+	return batch, nil
+}
+
+// Add 向开放批次加入一个任务并按提交时的准入路径入队
+// 批次已关闭时返回 ErrBatchClosed；批次已有任务成功时，新任务直接以取消结果完成。
+// 通过视图创建的批次在后台等待视图的限制，Add 不阻塞，入队失败时任务以该错误完成
+func (b *Batch) Add(task *Task) error {
+	g := b.group
+	if g.stream == nil {
+		return ErrBatchClosed
+	}
+	if len(task.DependsOn) > 0 {
+		return fmt.Errorf("%w: open batches do not support DependsOn", ErrInvalidDependencies)
+	}
+
+	g.stream.mu.Lock()
+	if g.closed.Load() {
+		g.stream.mu.Unlock()
+		return ErrBatchClosed
+	}
+	// 只登记被接受的任务，被拒绝的任务不会出现在 CancelTask 和管理视图中
+	t := new(Task)
+	*t = *task
+	g.attach(t, b.lane, g.sched.now())
+	b.tasksMu.Lock()
+	b.Tasks = append(b.Tasks, task)
+	b.tasksMu.Unlock()
+	g.total.Add(1)
+	g.remaining.Add(1)
+	g.wg.Add(1)
+	g.stream.mu.Unlock()
+
+	if err := g.ctx.Err(); err != nil {
+		g.sched.skipTask(t, err)
+		return nil
+	}
+	if g.pendingStopped() {
+		g.sched.skipTask(t, context.Canceled)
+		return nil
+	}
+	if g.scope != nil {
+		// 视图的限制可能阻塞，与 Scope.SubmitBatch 一样在后台准入
+		g.sched.admitReady(t)
+		return nil
+	}
+	if err := g.sched.admitTask(t); err != nil {
+		g.sched.skipTask(t, err)
+		return err
+	}
+	return nil
+}
+
+// Close 关闭开放批次，之后不能再加入任务，重复调用无副作用
+func (b *Batch) Close() {
+	g := b.group
+	if g.stream == nil {
+		return
+	}
+	g.stream.mu.Lock()
+	if g.closed.Swap(true) {
+		g.stream.mu.Unlock()
+		return
+	}
+	g.stream.mu.Unlock()
+
+	if g.remaining.Load() == 0 && !g.runFallback() {
+		g.finish()
+	}
+	g.wg.Done()
+}
+
+// resultStream 开放批次的结果缓冲，任务数未知，结果先缓存再转发给订阅方
+type resultStream struct {
+	mu     sync.Mutex
+	buf    []TaskResult
+	notify chan struct{}
+	once   sync.Once
+	out    chan TaskResult
+}
+
+// push 缓存一个结果并唤醒转发goroutine
+func (rs *resultStream) push(result TaskResult) {
+	rs.mu.Lock()
+	rs.buf = append(rs.buf, result)
+	rs.mu.Unlock()
+	select {
+	case rs.notify <- struct{}{}:
+	default:
+	}
+}
+
+// subscribe 返回结果通道，首次调用时启动转发goroutine，done 关闭且结果转发完后关闭通道
+func (rs *resultStream) subscribe(done <-chan struct{}) <-chan TaskResult {
+	rs.once.Do(func() {
+		rs.out = make(chan TaskResult)
+		go rs.forward(done)
+	})
+	return rs.out
+}
+
+// forward 按完成顺序转发缓存的结果
+func (rs *resultStream) forward(done <-chan struct{}) {
+	defer close(rs.out)
+	for {
+		rs.mu.Lock()
+		if len(rs.buf) > 0 {
+			result := rs.buf[0]
+			rs.buf[0] = TaskResult{}
+			rs.buf = rs.buf[1:]
+			rs.mu.Unlock()
+			rs.out <- result
+			continue
+		}
+		rs.mu.Unlock()
+
+		select {
+		case <-rs.notify:
+		case <-done:
+			rs.mu.Lock()
+			drained := len(rs.buf) == 0
+			rs.mu.Unlock()
+			if drained {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatch_OpenBatch(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	batch, err := scheduler.NewBatch()
+	if err != nil {
+		t.Fatalf("NewBatch failed: %v", err)
+	}
+
+	// 模拟分页读取上游，每页产生一个任务
+	for page := 0; page < 3; page++ {
+		id := fmt.Sprintf("page-%d", page)
+		if err := batch.Add(&Task{
+			ID: id,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+			},
+		}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	waited := make(chan struct{})
+	go func() {
+		batch.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+		t.Fatal("Open batch should not complete before Close")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	batch.Close()
+	<-waited
+
+	var results int
+	for range batch.ResultsChan() {
+		results++
+	}
+	if results != 3 {
+		t.Errorf("Expected 3 results, got %d", results)
+	}
+	if done, total := batch.Progress(); done != 3 || total != 3 {
+		t.Errorf("Expected progress 3/3, got %d/%d", done, total)
+	}
+	if err := batch.Add(&Task{ID: "late"}); !errors.Is(err, ErrBatchClosed) {
+		t.Errorf("Expected ErrBatchClosed, got %v", err)
+	}
+}
+
+func TestBatch_OpenBatchFirstSuccessCancels(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	batch, err := scheduler.NewBatch()
+	if err != nil {
+		t.Fatalf("NewBatch failed: %v", err)
+	}
+
+	slowCancelled := make(chan struct{})
+	if err := batch.Add(&Task{
+		ID: "slow",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			<-ctx.Done()
+			close(slowCancelled)
+			return TaskResult{}, ctx.Err()
+		},
+	}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := batch.Add(&Task{
+		ID: "fast",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	select {
+	case <-slowCancelled:
+	case <-time.After(time.Second):
+		t.Fatal("Success in open batch should cancel other tasks")
+	}
+
+	ran := false
+	if err := batch.Add(&Task{
+		ID: "after-success",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			ran = true
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	batch.Close()
+	batch.Wait()
+
+	if ran {
+		t.Error("Task added after success should not run")
+	}
+	if !batch.IsSuccess() {
+		t.Error("Expected batch to succeed")
+	}
+}
+
+func TestScope_OpenBatchRespectsLimits(t *testing.T) {
+	scheduler := NewScheduler(4, 10)
+	defer scheduler.Stop()
+	scope := scheduler.WithLimits(1, 0)
+
+	batch, err := scope.NewBatch()
+	if err != nil {
+		t.Fatalf("NewBatch failed: %v", err)
+	}
+	var running peakCounter
+	for i := 0; i < 4; i++ {
+		if err := batch.Add(&Task{
+			ID: fmt.Sprintf("page-%d", i),
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				running.enter(1)
+				defer running.exit(1)
+				time.Sleep(5 * time.Millisecond)
+				return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+			},
+		}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	batch.Close()
+	batch.Wait()
+	if done, total := batch.Progress(); done != 4 || total != 4 {
+		t.Errorf("Expected progress 4/4, got %d/%d", done, total)
+	}
+	if peak := running.peak.Load(); peak != 1 {
+		t.Errorf("Expected added tasks to respect the scope limit of 1, peak was %d", peak)
+	}
+}
+
+func TestBatch_OpenBatchConcurrentAdd(t *testing.T) {
+	scheduler := NewScheduler(4, 100)
+	defer scheduler.Stop()
+
+	batch, err := scheduler.NewBatch()
+	if err != nil {
+		t.Fatalf("NewBatch failed: %v", err)
+	}
+	const adders, perAdder = 4, 25
+	var wg sync.WaitGroup
+	for i := 0; i < adders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perAdder; j++ {
+				batch.Add(&Task{
+					ID: "t",
+					Execute: func(ctx context.Context) (TaskResult, error) {
+						return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+					},
+				})
+				// 与 Add 并发读取任务列表
+				_ = batch.TaskList()
+			}
+		}()
+	}
+	wg.Wait()
+	batch.Close()
+	batch.Wait()
+	if n := len(batch.TaskList()); n != adders*perAdder {
+		t.Errorf("Expected %d tasks, got %d", adders*perAdder, n)
+	}
+}
+
+func TestBatch_OpenBatchRejectedAddNotTracked(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	batch, err := scheduler.NewBatch()
+	if err != nil {
+		t.Fatalf("NewBatch failed: %v", err)
+	}
+	release := make(chan struct{})
+	if err := batch.Add(&Task{
+		ID: "running",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			<-release
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	batch.Close()
+
+	if err := batch.Add(&Task{ID: "late"}); !errors.Is(err, ErrBatchClosed) {
+		t.Errorf("Expected ErrBatchClosed, got %v", err)
+	}
+	if err := scheduler.CancelTask(batch.ID(), "late"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected rejected task not to be tracked, got %v", err)
+	}
+	close(release)
+	batch.Wait()
+}
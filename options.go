@@ -0,0 +1,64 @@
+package fastscheduler
+
+import (
+	"context"
+	"time"
+)
+
+// BatchOption 用于配置单个批次的行为
+type BatchOption func(*taskGroup)
+
+// OnProgress 注册批次进度回调，每个任务完成后调用一次
+// 回调在worker goroutine中执行，可能被并发调用，应尽量轻量
+func OnProgress(fn func(done, total int)) BatchOption {
+	return func(g *taskGroup) {
+		g.onProgress = fn
+	}
+}
+
+// WithQueueTTL 设置批次内任务的默认排队超时，任务自身的 QueueTTL 优先
+func WithQueueTTL(d time.Duration) BatchOption {
+	return func(g *taskGroup) {
+		g.queueTTL = d
+	}
+}
+
+// WithDeadline 设置批次内任务的默认完成时限，任务自身的 Deadline 优先
+func WithDeadline(d time.Duration) BatchOption {
+	return func(g *taskGroup) {
+		g.deadline = d
+	}
+}
+
+// withParentContext 指定组上下文的父上下文
+func withParentContext(ctx context.Context) BatchOption {
+	return func(g *taskGroup) {
+		g.parent = ctx
+	}
+}
+
+// runToCompletion 任务成功时不取消同组其他任务
+func runToCompletion() BatchOption {
+	return func(g *taskGroup) {
+		g.cancelOnSuccess = false
+	}
+}
+
+// Option 用于配置调度器
+type Option func(*Scheduler)
+
+// WithBaseContext 所有批次的组上下文从 ctx 派生，ctx 结束时取消所有批次并停止调度器
+// 通过 SubmitBatchContext 等指定了调用方上下文的批次继承调用方的值，同时随 ctx 一起取消
+func WithBaseContext(ctx context.Context) Option {
+	return func(s *Scheduler) {
+		s.baseCtx = ctx
+	}
+}
+
+// WithLaneQuota 限制指定优先级通道的最大并发任务数
+// 例如为后台通道设置配额，保证交互任务始终有空闲worker
+func WithLaneQuota(lane Lane, maxConcurrent int) Option {
+	return func(s *Scheduler) {
+		s.laneQuota[lane] = maxConcurrent
+	}
+}
@@ -0,0 +1,97 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+)
+
+// Outcome 批次的最终状态
+type Outcome int
+
+const (
+	// OutcomePending 批次尚未完成
+	OutcomePending Outcome = iota
+	// OutcomeSucceeded 有任务成功，且没有需要关注的失败
+	OutcomeSucceeded
+	// OutcomePartial 批次执行到底(如 Map 或带依赖的批次)，部分任务成功、部分失败
+	OutcomePartial
+	// OutcomeAllFailed 所有任务都执行完且失败
+	OutcomeAllFailed
+	// OutcomeCancelled 没有任务成功，且有任务因取消或调度器停止而未完成
+	OutcomeCancelled
+	// OutcomeTimedOut 没有任务成功，且有任务因超时或排队超时而未完成
+	OutcomeTimedOut
+	// OutcomeBudgetExceeded 没有任务成功，且批次成本预算在完成前用完
+	OutcomeBudgetExceeded
+	// OutcomeRetryBudgetExhausted 没有任务成功，且有任务因批次重试预算用完而没有再重试
+	OutcomeRetryBudgetExhausted
+)
+
+// String 返回状态名称
+func (o Outcome) String() string {
+	switch o {
+	case OutcomePending:
+		return "pending"
+	case OutcomeSucceeded:
+		return "succeeded"
+	case OutcomePartial:
+		return "partial"
+	case OutcomeAllFailed:
+		return "all_failed"
+	case OutcomeCancelled:
+		return "cancelled"
+	case OutcomeTimedOut:
+		return "timed_out"
+	case OutcomeBudgetExceeded:
+		return "budget_exceeded"
+	case OutcomeRetryBudgetExhausted:
+		return "retry_budget_exhausted"
+	default:
+		return "unknown"
+	}
+}
+
+// Outcome 返回批次的最终状态，批次未完成时返回 OutcomePending
+// 与 IsSuccess 不同，它能区分全部失败和完成前被取消或超时
+func (b *Batch) Outcome() Outcome {
+	return b.group.outcome()
+}
+
+// outcome 计算任务组的最终状态
+func (g *taskGroup) outcome() Outcome {
+	select {
+	case <-g.done:
+	default:
+		return OutcomePending
+	}
+
+	g.errMu.Lock()
+	defer g.errMu.Unlock()
+	if g.success.Load() {
+		if len(g.errs) > 0 && !g.cancelOnSuccess {
+			return OutcomePartial
+		}
+		return OutcomeSucceeded
+	}
+
+	timedOut, retryExhausted := false, false
+	for _, err := range g.errs {
+		switch {
+		case errors.Is(err, ErrBudgetExceeded):
+			return OutcomeBudgetExceeded
+		case errors.Is(err, ErrRetryBudgetExhausted):
+			retryExhausted = true
+		case errors.Is(err, context.Canceled), errors.Is(err, ErrSchedulerStopped):
+			return OutcomeCancelled
+		case errors.Is(err, context.DeadlineExceeded), errors.Is(err, ErrQueueTTLExpired):
+			timedOut = true
+		}
+	}
+	if retryExhausted {
+		return OutcomeRetryBudgetExhausted
+	}
+	if timedOut {
+		return OutcomeTimedOut
+	}
+	return OutcomeAllFailed
+}
@@ -0,0 +1,80 @@
+package fastscheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBatch_Outcome(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	ok := func(ctx context.Context) (TaskResult, error) {
+		return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+	}
+	fail := func(ctx context.Context) (TaskResult, error) {
+		return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+	}
+	slow := func(ctx context.Context) (TaskResult, error) {
+		<-ctx.Done()
+		return TaskResult{}, ctx.Err()
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	timedOut, cancelTimeout := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelTimeout()
+
+	cases := []struct {
+		name  string
+		execs []func(context.Context) (TaskResult, error)
+		opts  []BatchOption
+		want  Outcome
+	}{
+		{"succeeded", []func(context.Context) (TaskResult, error){fail, ok}, nil, OutcomeSucceeded},
+		{"partial", []func(context.Context) (TaskResult, error){fail, ok}, []BatchOption{runToCompletion()}, OutcomePartial},
+		{"all failed", []func(context.Context) (TaskResult, error){fail, fail}, nil, OutcomeAllFailed},
+		{"cancelled", []func(context.Context) (TaskResult, error){slow, fail}, []BatchOption{withParentContext(cancelled)}, OutcomeCancelled},
+		{"timed out", []func(context.Context) (TaskResult, error){slow, fail}, []BatchOption{withParentContext(timedOut)}, OutcomeTimedOut},
+	}
+	for _, tc := range cases {
+		var tasks []*Task
+		for _, exec := range tc.execs {
+			tasks = append(tasks, &Task{ID: tc.name, Execute: exec})
+		}
+		batch, err := scheduler.SubmitBatch(tasks, tc.opts...)
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		batch.Wait()
+		if got := batch.Outcome(); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestBatch_OutcomePending(t *testing.T) {
+	scheduler := NewScheduler(1, 10)
+	defer scheduler.Stop()
+
+	release := make(chan struct{})
+	batch, err := scheduler.SubmitBatch([]*Task{{
+		ID: "blocked",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			<-release
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if got := batch.Outcome(); got != OutcomePending {
+		t.Errorf("Expected pending, got %v", got)
+	}
+	close(release)
+	batch.Wait()
+	if got := batch.Outcome(); got != OutcomeSucceeded {
+		t.Errorf("Expected succeeded, got %v", got)
+	}
+}
@@ -0,0 +1,44 @@
+package fastscheduler
+
+// OverflowPolicy 队列已满时的提交策略
+type OverflowPolicy int
+
+const (
+	// OverflowBlock 阻塞提交方直到队列有空位(默认)
+	OverflowBlock OverflowPolicy = iota
+	// OverflowReject 拒绝放不下的任务，提交返回 ErrQueueFull
+	OverflowReject
+	// OverflowDropOldest 丢弃通道中最早排队的任务，为新任务腾出空间
+	// 被丢弃的任务以 ErrTaskDropped 完成，适用于可容忍丢失的遥测类负载
+	OverflowDropOldest
+)
+
+// WithOverflowPolicy 设置队列已满时的提交策略
+func WithOverflowPolicy(policy OverflowPolicy) Option {
+	return func(s *Scheduler) {
+		s.overflow = policy
+	}
+}
+
+// offer 按溢出策略非阻塞地放入任务，返回是否需要回退到阻塞入队
+func (s *Scheduler) offer(t *Task) (handled bool, err error) {
+	switch s.overflow {
+	case OverflowReject:
+		return true, s.pushTask(t)
+	case OverflowDropOldest:
+		for {
+			err := s.pushTask(t)
+			if err != ErrQueueFull {
+				return true, err
+			}
+			// 队列中没有可丢弃的任务时退化为拒绝
+			oldest, ok := s.popTask(t.lane, noShard)
+			if !ok {
+				return true, ErrQueueFull
+			}
+			s.skipTask(oldest, ErrTaskDropped)
+		}
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,79 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// blockWorker 占住唯一的worker直到返回的函数被调用
+func blockWorker(t *testing.T, s *Scheduler) func() {
+	t.Helper()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	_, err := s.SubmitBatch([]*Task{{
+		ID: "blocker",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			close(started)
+			<-release
+			return TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	<-started
+	return func() { close(release) }
+}
+
+func noopTask(id string) *Task {
+	return &Task{
+		ID: id,
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+		},
+	}
+}
+
+func TestOverflow_Reject(t *testing.T) {
+	scheduler := NewScheduler(1, 1, WithOverflowPolicy(OverflowReject))
+	defer scheduler.Stop()
+	release := blockWorker(t, scheduler)
+	defer release()
+
+	batch, err := scheduler.SubmitBatch([]*Task{noopTask("fits"), noopTask("rejected")})
+	if !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("Expected ErrQueueFull, got %v", err)
+	}
+	res := <-batch.ResultsChan()
+	if res.TaskID != "rejected" || !errors.Is(res.Err, ErrQueueFull) {
+		t.Errorf("Expected rejected task to complete with ErrQueueFull, got %+v", res)
+	}
+}
+
+func TestOverflow_DropOldest(t *testing.T) {
+	scheduler := NewScheduler(1, 1, WithOverflowPolicy(OverflowDropOldest))
+	defer scheduler.Stop()
+	release := blockWorker(t, scheduler)
+
+	oldest, err := scheduler.SubmitBatch([]*Task{noopTask("oldest")})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	newest, err := scheduler.SubmitBatch([]*Task{noopTask("newest")})
+	if err != nil {
+		t.Fatalf("Expected newest task to be accepted, got %v", err)
+	}
+
+	oldest.Wait()
+	res := <-oldest.ResultsChan()
+	if !errors.Is(res.Err, ErrTaskDropped) {
+		t.Errorf("Expected oldest task to be dropped, got %+v", res)
+	}
+
+	release()
+	newest.Wait()
+	if res := <-newest.ResultsChan(); res.Err != nil {
+		t.Errorf("Expected newest task to run, got %+v", res)
+	}
+}
@@ -0,0 +1,106 @@
+package fastscheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// All 在调度器的worker池上对每个输入并发执行 fn，按输入顺序返回结果
+// 任一调用返回错误时取消其余调用并返回第一个错误
+// 未执行即结束的任务(被丢弃、排队超时、熔断、调度器停止等)同样视为失败
+func All[I, T any](ctx context.Context, s *Scheduler, inputs []I, fn func(context.Context, I) (T, error)) ([]T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		once     sync.Once
+		firstErr error
+	)
+	results, errs := runParallel(ctx, s, "all", inputs, func(ctx context.Context, input I) (T, error) {
+		v, err := fn(ctx, input)
+		if err != nil {
+			once.Do(func() {
+				firstErr = err
+				cancel()
+			})
+		}
+		return v, err
+	})
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Map 与 All 类似，但不会因单个错误提前取消
+// 返回按输入顺序排列的结果和错误，errs[i] 非nil时 results[i] 为零值
+// 未执行即结束的任务 errs[i] 为其最终结果的错误，调度器已停止时所有 errs[i] 均为 ErrSchedulerStopped
+func Map[I, T any](ctx context.Context, s *Scheduler, inputs []I, fn func(context.Context, I) (T, error)) (results []T, errs []error) {
+	return runParallel(ctx, s, "map", inputs, fn)
+}
+
+// runParallel 以批次执行 fn，结果和错误均取自每个任务的最终结果而非 Execute 的副作用
+func runParallel[I, T any](ctx context.Context, s *Scheduler, prefix string, inputs []I, fn func(context.Context, I) (T, error)) ([]T, []error) {
+	results := make([]T, len(inputs))
+	errs := make([]error, len(inputs))
+	var mu sync.Mutex
+	tasks := make([]*Task, len(inputs))
+	for i, input := range inputs {
+		fail := func(r TaskResult) {
+			mu.Lock()
+			errs[i] = resultError(r)
+			mu.Unlock()
+		}
+		tasks[i] = &Task{
+			ID: fmt.Sprintf("%s-%d", prefix, i),
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				v, err := fn(ctx, input)
+				if err != nil {
+					return TaskResult{}, err
+				}
+				// 对冲等并发尝试可能同时成功，任取其一
+				mu.Lock()
+				results[i] = v
+				mu.Unlock()
+				return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+			},
+			OnFailure: fail,
+			OnCancel:  fail,
+		}
+	}
+
+	batch, err := s.SubmitBatch(tasks, withParentContext(ctx), runToCompletion())
+	if batch == nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return results, errs
+	}
+	batch.Wait()
+
+	var zero T
+	for i := range errs {
+		if errs[i] != nil {
+			results[i] = zero
+		}
+	}
+	return results, errs
+}
+
+// resultError 返回失败结果对应的错误，仅以状态码表示失败时返回 *TaskError
+func resultError(r TaskResult) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	return &TaskError{TaskID: r.TaskID, HTTPCode: r.HTTPCode, BusinessCode: r.BusinessCode}
+}
@@ -0,0 +1,85 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestAll_PreservesInputOrder(t *testing.T) {
+	scheduler := NewScheduler(4, 20)
+	defer scheduler.Stop()
+
+	inputs := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	results, err := All(context.Background(), scheduler, inputs, func(ctx context.Context, n int) (string, error) {
+		return strconv.Itoa(n * n), nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i, n := range inputs {
+		if results[i] != strconv.Itoa(n*n) {
+			t.Errorf("Expected results[%d] = %d, got %s", i, n*n, results[i])
+		}
+	}
+}
+
+func TestAll_FailFast(t *testing.T) {
+	scheduler := NewScheduler(4, 20)
+	defer scheduler.Stop()
+
+	errBoom := errors.New("boom")
+	_, err := All(context.Background(), scheduler, []int{1, 2, 3}, func(ctx context.Context, n int) (int, error) {
+		if n == 2 {
+			return 0, errBoom
+		}
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	if !errors.Is(err, errBoom) {
+		t.Errorf("Expected boom error, got %v", err)
+	}
+}
+
+func TestMap_CollectsErrors(t *testing.T) {
+	scheduler := NewScheduler(4, 20)
+	defer scheduler.Stop()
+
+	results, errs := Map(context.Background(), scheduler, []int{1, 2, 3}, func(ctx context.Context, n int) (int, error) {
+		if n == 2 {
+			return 0, errors.New("even")
+		}
+		return n * 10, nil
+	})
+	if results[0] != 10 || results[2] != 30 {
+		t.Errorf("Unexpected results: %v", results)
+	}
+	if errs[0] != nil || errs[1] == nil || errs[2] != nil {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+}
+
+func TestMap_ReportsTasksFailedOutsideFn(t *testing.T) {
+	scheduler := NewScheduler(4, 20, WithChaos(ChaosConfig{DropProbability: 1}))
+	defer scheduler.Stop()
+
+	results, errs := Map(context.Background(), scheduler, []int{1, 2}, func(ctx context.Context, n int) (int, error) {
+		return n, nil
+	})
+	for i := range errs {
+		if !errors.Is(errs[i], ErrChaosDropped) {
+			t.Errorf("Expected errs[%d] to be ErrChaosDropped, got %v", i, errs[i])
+		}
+		if results[i] != 0 {
+			t.Errorf("Expected zero results[%d], got %d", i, results[i])
+		}
+	}
+
+	_, err := All(context.Background(), scheduler, []int{1, 2}, func(ctx context.Context, n int) (int, error) {
+		return n, nil
+	})
+	if !errors.Is(err, ErrChaosDropped) {
+		t.Errorf("Expected ErrChaosDropped, got %v", err)
+	}
+}
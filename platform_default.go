@@ -0,0 +1,6 @@
+//go:build !js && !wasip1
+
+package fastscheduler
+
+// cooperativeDispatch 多线程平台上调度goroutine无需主动让出处理器
+const cooperativeDispatch = false
@@ -0,0 +1,7 @@
+//go:build js || wasip1
+
+package fastscheduler
+
+// cooperativeDispatch WebAssembly 平台只有一个线程，调度goroutine每分派一个任务就让出处理器，
+// 让任务尽快开始执行，而不是先把队列中的任务全部取出
+const cooperativeDispatch = true
@@ -0,0 +1,165 @@
+package fastscheduler
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// SchedulingPolicy 决定同一通道内排队任务的出队顺序
+type SchedulingPolicy interface {
+	// Less 报告任务 a 是否应先于 b 出队，两者不分先后时按入队顺序
+	Less(a, b *Task) bool
+}
+
+// SchedulingPolicyFunc 将普通函数适配为 SchedulingPolicy
+type SchedulingPolicyFunc func(a, b *Task) bool
+
+// Less 实现 SchedulingPolicy 接口
+func (f SchedulingPolicyFunc) Less(a, b *Task) bool {
+	return f(a, b)
+}
+
+// fifoPolicy 先进先出，调度器的默认策略
+type fifoPolicy struct{}
+
+func (fifoPolicy) Less(a, b *Task) bool { return false }
+
+// FIFO 先进先出策略，调度器默认使用
+func FIFO() SchedulingPolicy {
+	return fifoPolicy{}
+}
+
+// EarliestDeadlineFirst 最早截止时间优先策略：截止时间(提交时间加 Task.Deadline 或批次的 WithDeadline)
+// 最早的任务先出队，最接近错过 SLA 的任务优先执行；没有截止时间的任务排在有截止时间的任务之后，按入队顺序执行
+func EarliestDeadlineFirst() SchedulingPolicy {
+	return SchedulingPolicyFunc(func(a, b *Task) bool {
+		da, okA := a.Due()
+		db, okB := b.Due()
+		if okA != okB {
+			return okA
+		}
+		return okA && da.Before(db)
+	})
+}
+
+// WithSchedulingPolicy 设置同一通道内的出队顺序，默认 FIFO
+// 非 FIFO 策略使用内置的有序队列，WithQueue 不生效；与 WithTenantPolicy、WithBatchFairness 同时使用时，
+// 策略决定每个租户或批次内部的顺序。优先级通道之间的顺序不受影响
+func WithSchedulingPolicy(policy SchedulingPolicy) Option {
+	return func(s *Scheduler) {
+		if _, ok := policy.(fifoPolicy); ok {
+			policy = nil
+		}
+		s.policy = policy
+	}
+}
+
+// Due 返回任务的截止时间(提交时间加 Deadline)，任务没有截止时间或尚未提交时返回false
+func (t *Task) Due() (time.Time, bool) {
+	if t.Deadline <= 0 || t.enqueuedAt.IsZero() {
+		return time.Time{}, false
+	}
+	return t.enqueuedAt.Add(t.Deadline), true
+}
+
+// taskList 排队中的任务，policy 为nil时先进先出，否则按策略排序，相同时按入队顺序
+type taskList struct {
+	policy SchedulingPolicy
+	seq    uint64
+	items  []queuedTask
+}
+
+// queuedTask 带入队序号的任务
+type queuedTask struct {
+	task *Task
+	seq  uint64
+}
+
+func (l *taskList) push(t *Task) {
+	l.seq++
+	if l.policy == nil {
+		l.items = append(l.items, queuedTask{task: t, seq: l.seq})
+		return
+	}
+	heap.Push((*taskHeap)(l), queuedTask{task: t, seq: l.seq})
+}
+
+func (l *taskList) pop() *Task {
+	if l.policy == nil {
+		t := l.items[0].task
+		l.items[0] = queuedTask{}
+		l.items = l.items[1:]
+		return t
+	}
+	return heap.Pop((*taskHeap)(l)).(queuedTask).task
+}
+
+func (l *taskList) len() int {
+	return len(l.items)
+}
+
+// taskHeap 按策略排序的 container/heap 实现
+type taskHeap taskList
+
+func (h *taskHeap) Len() int { return len(h.items) }
+
+func (h *taskHeap) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if h.policy.Less(a.task, b.task) {
+		return true
+	}
+	if h.policy.Less(b.task, a.task) {
+		return false
+	}
+	return a.seq < b.seq
+}
+
+func (h *taskHeap) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *taskHeap) Push(x any) { h.items = append(h.items, x.(queuedTask)) }
+
+func (h *taskHeap) Pop() any {
+	n := len(h.items) - 1
+	item := h.items[n]
+	h.items[n] = queuedTask{}
+	h.items = h.items[:n]
+	return item
+}
+
+// policyQueue 按调度策略出队的队列
+type policyQueue struct {
+	mu       sync.Mutex
+	capacity int
+	tasks    taskList
+}
+
+// newPolicyQueue 创建容量为 capacity、按 policy 出队的队列
+func newPolicyQueue(capacity int, policy SchedulingPolicy) *policyQueue {
+	return &policyQueue{capacity: max(capacity, 1), tasks: taskList{policy: policy}}
+}
+
+func (q *policyQueue) Push(t *Task) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.tasks.len() >= q.capacity {
+		return ErrQueueFull
+	}
+	q.tasks.push(t)
+	return nil
+}
+
+func (q *policyQueue) Pop() (*Task, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.tasks.len() == 0 {
+		return nil, false
+	}
+	return q.tasks.pop(), true
+}
+
+func (q *policyQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.tasks.len()
+}
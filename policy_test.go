@@ -0,0 +1,90 @@
+package fastscheduler
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// runInOrder 暂停调度器提交任务后恢复，返回任务的执行顺序
+func runInOrder(t *testing.T, scheduler *Scheduler, tasks []*Task) []string {
+	t.Helper()
+	var mu sync.Mutex
+	var order []string
+	for _, task := range tasks {
+		id := task.ID
+		task.Execute = func(ctx context.Context) (TaskResult, error) {
+			mu.Lock()
+			order = append(order, id)
+			mu.Unlock()
+			return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+		}
+	}
+
+	scheduler.Pause()
+	var batches []*Batch
+	for _, task := range tasks {
+		batch, err := scheduler.SubmitBatch([]*Task{task})
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		batches = append(batches, batch)
+	}
+	scheduler.Resume()
+	for _, batch := range batches {
+		batch.Wait()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return order
+}
+
+func TestScheduler_EarliestDeadlineFirst(t *testing.T) {
+	scheduler := NewScheduler(1, 10, WithSchedulingPolicy(EarliestDeadlineFirst()))
+	defer scheduler.Stop()
+
+	order := runInOrder(t, scheduler, []*Task{
+		{ID: "no-deadline"},
+		{ID: "relaxed", Deadline: time.Minute},
+		{ID: "urgent", Deadline: 10 * time.Millisecond},
+		{ID: "also-no-deadline"},
+		{ID: "soon", Deadline: time.Second},
+	})
+	want := []string{"urgent", "soon", "relaxed", "no-deadline", "also-no-deadline"}
+	if !slices.Equal(order, want) {
+		t.Errorf("Expected EDF order %v, got %v", want, order)
+	}
+}
+
+func TestScheduler_CustomSchedulingPolicy(t *testing.T) {
+	cheapest := SchedulingPolicyFunc(func(a, b *Task) bool { return a.Cost < b.Cost })
+	scheduler := NewScheduler(1, 10, WithSchedulingPolicy(cheapest))
+	defer scheduler.Stop()
+
+	order := runInOrder(t, scheduler, []*Task{
+		{ID: "expensive", Cost: 9},
+		{ID: "cheap", Cost: 1},
+		{ID: "medium", Cost: 5},
+		{ID: "cheap-2", Cost: 1},
+	})
+	want := []string{"cheap", "cheap-2", "medium", "expensive"}
+	if !slices.Equal(order, want) {
+		t.Errorf("Expected cost order %v, got %v", want, order)
+	}
+}
+
+func TestScheduler_FIFOPolicy(t *testing.T) {
+	scheduler := NewScheduler(1, 10, WithSchedulingPolicy(FIFO()))
+	defer scheduler.Stop()
+
+	order := runInOrder(t, scheduler, []*Task{
+		{ID: "first", Deadline: time.Minute},
+		{ID: "second", Deadline: time.Millisecond},
+	})
+	if !slices.Equal(order, []string{"first", "second"}) {
+		t.Errorf("Expected submission order, got %v", order)
+	}
+}
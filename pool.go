@@ -0,0 +1,235 @@
+package fastscheduler
+
+import (
+	"context"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// workerSlots worker信号量，由调度goroutine获取，任务执行结束时归还
+// 容量和已占用的名额打包在一个原子变量中，获取和归还名额都不加锁，
+// 工作窃取模式下多个调度goroutine和执行结束的worker不会在同一把锁上竞争
+type workerSlots struct {
+	// state 高32位为容量，低32位为已占用的名额
+	state atomic.Uint64
+	// freed 有空位时的信号，唤醒等待的调度goroutine
+	freed chan struct{}
+}
+
+func newWorkerSlots(size int) *workerSlots {
+	p := &workerSlots{freed: make(chan struct{}, 1)}
+	p.state.Store(packSlots(size, 0))
+	return p
+}
+
+// packSlots 将容量和已占用的名额打包为 state
+func packSlots(size, busy int) uint64 {
+	return uint64(uint32(size))<<32 | uint64(uint32(busy))
+}
+
+// unpackSlots 从 state 中取出容量和已占用的名额
+func unpackSlots(state uint64) (size, busy int) {
+	return int(uint32(state >> 32)), int(uint32(state))
+}
+
+// tryAcquire 按当前容量计算所需名额 units(size)，空位足够时原子地占用
+// 返回占用的名额数、占用后是否仍有空位以及是否成功；所需名额不大于0时不占用并返回成功
+func (p *workerSlots) tryAcquire(units func(size int) int) (n int, more, ok bool) {
+	for {
+		state := p.state.Load()
+		size, busy := unpackSlots(state)
+		n = units(size)
+		if n <= 0 {
+			return 0, busy < size, true
+		}
+		if busy+n > size {
+			return n, false, false
+		}
+		if p.state.CompareAndSwap(state, packSlots(size, busy+n)) {
+			return n, busy+n < size, true
+		}
+	}
+}
+
+// acquireUnits 等待 units(size) 个空闲名额，stop 关闭时返回false；stop 为nil时一直等待
+func (p *workerSlots) acquireUnits(units func(size int) int, stop <-chan struct{}) (int, bool) {
+	for {
+		n, more, ok := p.tryAcquire(units)
+		if ok {
+			// 还有空位时把信号传给其他等待的调度goroutine
+			if n > 0 && more {
+				p.signal()
+			}
+			return n, true
+		}
+		select {
+		case <-p.freed:
+		case <-stop:
+			return 0, false
+		}
+	}
+}
+
+// acquire 等待一个空闲worker，stop 关闭时返回false
+func (p *workerSlots) acquire(stop <-chan struct{}) bool {
+	return p.acquireN(1, stop)
+}
+
+// acquireN 等待 n 个空闲名额，stop 关闭时返回false；stop 为nil时一直等待
+func (p *workerSlots) acquireN(n int, stop <-chan struct{}) bool {
+	_, ok := p.acquireUnits(func(int) int { return n }, stop)
+	return ok
+}
+
+// release 归还worker
+func (p *workerSlots) release() {
+	p.releaseN(1)
+}
+
+// releaseN 归还 n 个名额
+func (p *workerSlots) releaseN(n int) {
+	p.state.Add(^uint64(n - 1))
+	p.signal()
+}
+
+// acquireWeight 任务的 Weight 大于1时，在已获取的一个名额之外再获取 Weight-1 个名额
+// 权重超过容量时按容量计算，等待期间容量调整后重新计算；stop 关闭时返回false，此时没有获取额外名额
+func (p *workerSlots) acquireWeight(t *Task, stop <-chan struct{}) bool {
+	extra, ok := p.acquireUnits(func(size int) int { return min(t.Weight, size) - 1 }, stop)
+	if ok {
+		t.extraSlots = extra
+	}
+	return ok
+}
+
+// acquireTask 一次获取任务所需的全部名额(Weight，至少1，超过容量时按容量计算)，stop 关闭时返回false
+// 多个调度goroutine并发获取时，分步获取会使各自持有部分名额而互相等待
+func (p *workerSlots) acquireTask(t *Task, stop <-chan struct{}) bool {
+	units, ok := p.acquireUnits(func(size int) int { return max(min(t.Weight, size), 1) }, stop)
+	if ok {
+		t.extraSlots = units - 1
+	}
+	return ok
+}
+
+// taskRelease 返回归还任务本次执行占用的worker名额、通道配额和租户名额的函数
+// 在执行开始时调用：任务重试或被抢占后会在其他goroutine中重新入队，执行结束时不能再读写这些状态
+func (s *Scheduler) taskRelease(t *Task) func() {
+	units := 1 + t.extraSlots
+	t.extraSlots = 0
+	if b := t.bulkhead; b != nil {
+		return func() { b.slots.releaseN(units) }
+	}
+	lane, tenant, tenantHeld := t.lane, t.Tenant, t.tenantHeld
+	t.tenantHeld = false
+	return func() {
+		s.workerPool.releaseN(units)
+		s.releaseLane(lane)
+		if tenantHeld {
+			s.releaseTenant(tenant)
+		}
+	}
+}
+
+// resize 调整容量，缩小时执行中的任务不受影响，空位降到新容量以下后才调度新任务
+func (p *workerSlots) resize(size int) {
+	for {
+		state := p.state.Load()
+		_, busy := unpackSlots(state)
+		if p.state.CompareAndSwap(state, packSlots(size, busy)) {
+			break
+		}
+	}
+	p.signal()
+}
+
+func (p *workerSlots) signal() {
+	select {
+	case p.freed <- struct{}{}:
+	default:
+	}
+}
+
+// usage 返回已占用的名额数(执行中任务的权重之和)和容量
+func (p *workerSlots) usage() (busy, size int) {
+	size, busy = unpackSlots(p.state.Load())
+	return busy, size
+}
+
+// Resize 在运行时调整worker池大小，设置了 WithCPUBound 时同样不超过可用CPU数
+// 以 AutoPoolSize 创建的调度器调用 Resize 后不再随 GOMAXPROCS 自动调整，
+// 传入 AutoPoolSize 时按当前 GOMAXPROCS 重新计算并恢复自动调整
+func (s *Scheduler) Resize(poolSize int) {
+	s.autoPoolMu.Lock()
+	defer s.autoPoolMu.Unlock()
+	s.autoPool = poolSize == AutoPoolSize
+	if s.autoPool {
+		poolSize = s.autoPoolSize()
+	}
+	s.resize(poolSize)
+}
+
+// resize 调整worker池大小，设置了 WithCPUBound 时不超过可用CPU数
+func (s *Scheduler) resize(poolSize int) {
+	if s.cpuBound {
+		poolSize = capToCPUs(poolSize)
+	}
+	s.workerPool.resize(poolSize)
+}
+
+// TaskInfo 执行中任务的信息
+type TaskInfo struct {
+	ID      string
+	BatchID string
+	Lane    Lane
+	Attempt int
+	// Started 本次执行开始的时间
+	Started time.Time
+	// Checkpoints 本次执行中调用 Checkpoint 的次数
+	Checkpoints int64
+	// LastCheckpoint 最近一次调用 Checkpoint 的时间，尚未调用时为零值
+	LastCheckpoint time.Time
+
+	// progress 本次执行的检查点记录
+	progress *taskProgress
+	// goroutine 执行该任务的goroutine ID，仅启用 WithWatchdog 时记录
+	goroutine uint64
+}
+
+// trackRunning 记录任务开始执行，返回带检查点记录的上下文，返回的函数在执行结束时调用
+func (s *Scheduler) trackRunning(ctx context.Context, t *Task) (context.Context, func()) {
+	t.startedAt = s.now()
+	progress := &taskProgress{now: s.now}
+	info := TaskInfo{ID: t.ID, BatchID: t.group.id, Lane: t.lane, Attempt: t.attempts, Started: t.startedAt, progress: progress}
+	if s.watchdog != nil {
+		info.goroutine = goroutineID()
+	}
+	s.running.Store(t, info)
+	return context.WithValue(ctx, progressKey{}, progress), func() { s.running.Delete(t) }
+}
+
+// metrics 计算任务在 now 结束时的时间指标
+func (t *Task) metrics(now time.Time) TaskMetrics {
+	m := TaskMetrics{EnqueuedAt: t.enqueuedAt, StartedAt: t.startedAt, FinishedAt: now}
+	if t.startedAt.IsZero() {
+		m.QueueWait = now.Sub(t.enqueuedAt)
+	} else {
+		m.QueueWait = t.startedAt.Sub(t.enqueuedAt)
+		m.Duration = now.Sub(t.startedAt)
+	}
+	return m
+}
+
+// Running 返回正在执行的任务，按开始时间排序
+func (s *Scheduler) Running() []TaskInfo {
+	var tasks []TaskInfo
+	s.running.Range(func(_, v any) bool {
+		info := v.(TaskInfo)
+		info.Checkpoints, info.LastCheckpoint = info.progress.snapshot()
+		tasks = append(tasks, info)
+		return true
+	})
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].Started.Before(tasks[j].Started) })
+	return tasks
+}
@@ -0,0 +1,190 @@
+package fastscheduler
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_ResizeShrink(t *testing.T) {
+	scheduler := NewScheduler(4, 20)
+	defer scheduler.Stop()
+	scheduler.Resize(1)
+
+	var running, peak atomic.Int64
+	tasks := make([]*Task, 5)
+	for i := range tasks {
+		tasks[i] = &Task{
+			ID: "t",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				return TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+			},
+		}
+	}
+	batch, err := scheduler.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	batch.Wait()
+
+	if got := peak.Load(); got != 1 {
+		t.Errorf("Expected at most 1 concurrent task after resize, got %d", got)
+	}
+	if got := scheduler.Stats().Workers.Size; got != 1 {
+		t.Errorf("Expected pool size 1, got %d", got)
+	}
+}
+
+func TestScheduler_WeightedTasks(t *testing.T) {
+	scheduler := NewScheduler(10, 50)
+	defer scheduler.Stop()
+
+	var load, peak atomic.Int32
+	task := func(id string, weight int) *Task {
+		// 实际占用的名额：默认1，超过池容量时按容量计算
+		units := int32(min(max(weight, 1), 10))
+		return &Task{
+			ID:     id,
+			Weight: weight,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				n := load.Add(units)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				load.Add(-units)
+				return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+			},
+		}
+	}
+
+	var tasks []*Task
+	for i := 0; i < 4; i++ {
+		tasks = append(tasks, task(fmt.Sprintf("heavy-%d", i), 6))
+		tasks = append(tasks, task(fmt.Sprintf("light-%d", i), 0), task(fmt.Sprintf("light-%d-b", i), 1))
+	}
+	// 权重超过池容量时按容量计算，不会永远等待
+	tasks = append(tasks, task("huge", 50))
+
+	batch, err := scheduler.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := batch.WaitContext(ctx); err != nil {
+		t.Fatalf("Weighted batch did not finish: %v", err)
+	}
+
+	if p := peak.Load(); p > 10 {
+		t.Errorf("Expected total weight bounded by pool size 10, got peak %d", p)
+	}
+	// 批次完成后归还所有名额，空闲的调度goroutine预先占用一个
+	waitFor(t, func() bool {
+		busy, _ := scheduler.workerPool.usage()
+		return busy <= 1
+	})
+}
+
+func TestScheduler_WeightedTaskWaitingForSlots(t *testing.T) {
+	scheduler := NewScheduler(4, 10)
+	defer scheduler.Stop()
+
+	release := make(chan struct{})
+	blocker := &Task{ID: "blocker", Execute: func(ctx context.Context) (TaskResult, error) {
+		<-release
+		return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+	}}
+	var heavyRan atomic.Bool
+	heavy := &Task{ID: "heavy", Weight: 4, Execute: func(ctx context.Context) (TaskResult, error) {
+		heavyRan.Store(true)
+		return TaskResult{HTTPCode: 200}, nil
+	}}
+	if _, err := scheduler.Submit(blocker); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	waitFor(t, func() bool { return len(scheduler.Running()) == 1 })
+	future, err := scheduler.Submit(heavy)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	waitFor(t, func() bool {
+		busy, _ := scheduler.workerPool.usage()
+		return busy == 2
+	})
+
+	// 停止时不再等待额外名额，任务保留在队列中
+	stopped := make(chan struct{})
+	go func() {
+		scheduler.Stop()
+		close(stopped)
+	}()
+	waitFor(t, func() bool { return scheduler.State() == StateDraining })
+	close(release)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop hung while a weighted task was waiting for slots")
+	}
+	if heavyRan.Load() {
+		t.Fatal("Weighted task should not start after Stop")
+	}
+
+	// 重新启动后任务照常执行
+	scheduler.Start()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := future.Wait(ctx); err != nil {
+		t.Fatalf("Weighted task did not run after restart: %v", err)
+	}
+	if !heavyRan.Load() {
+		t.Error("Expected weighted task to run after restart")
+	}
+}
+
+func TestScheduler_WeightedTaskPoolShrinks(t *testing.T) {
+	scheduler := NewScheduler(4, 10)
+	defer scheduler.Stop()
+
+	release := make(chan struct{})
+	if _, err := scheduler.Submit(&Task{ID: "blocker", Execute: func(ctx context.Context) (TaskResult, error) {
+		<-release
+		return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+	}}); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	waitFor(t, func() bool { return len(scheduler.Running()) == 1 })
+	future, err := scheduler.Submit(&Task{ID: "heavy", Weight: 4, Execute: func(ctx context.Context) (TaskResult, error) {
+		return TaskResult{HTTPCode: 200}, nil
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	waitFor(t, func() bool {
+		busy, _ := scheduler.workerPool.usage()
+		return busy == 2
+	})
+
+	// 缩小到2后只需要再获取1个名额，阻塞任务完成后即可执行
+	scheduler.Resize(2)
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := future.Wait(ctx); err != nil {
+		t.Fatalf("Weighted task never ran after the pool shrank: %v", err)
+	}
+}
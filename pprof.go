@@ -0,0 +1,33 @@
+package fastscheduler
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// WithPprofLabels 执行任务时附加 pprof 标签 batch_id、task_id、lane 和 key(设置了 Task.Key 时)，
+// CPU 和 goroutine profile 可以按批次或任务切分，例如 go tool pprof -tagfocus=key=search
+// 任务在 Execute 中启动的goroutine同样继承这些标签
+func WithPprofLabels() Option {
+	return func(s *Scheduler) {
+		s.pprofLabels = true
+	}
+}
+
+// taskLabels 返回任务的 pprof 标签
+func taskLabels(t *Task) pprof.LabelSet {
+	labels := []string{"batch_id", t.group.id, "task_id", t.ID, "lane", t.lane.String()}
+	if t.Key != "" {
+		labels = append(labels, "key", t.Key)
+	}
+	return pprof.Labels(labels...)
+}
+
+// withLabels 按配置在 pprof 标签下调用 fn
+func (s *Scheduler) withLabels(ctx context.Context, t *Task, fn func(context.Context)) {
+	if !s.pprofLabels {
+		fn(ctx)
+		return
+	}
+	pprof.Do(ctx, taskLabels(t), fn)
+}
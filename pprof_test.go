@@ -0,0 +1,36 @@
+package fastscheduler
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+)
+
+func TestScheduler_PprofLabels(t *testing.T) {
+	scheduler := NewScheduler(1, 10, WithPprofLabels())
+	defer scheduler.Stop()
+
+	labels := make(map[string]string)
+	batch, err := scheduler.SubmitBatch([]*Task{{
+		ID:  "labelled",
+		Key: "search",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			pprof.ForLabels(ctx, func(k, v string) bool {
+				labels[k] = v
+				return true
+			})
+			return TaskResult{HTTPCode: 200}, nil
+		},
+	}}, WithBatchID("profiled"))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	want := map[string]string{"batch_id": "profiled", "task_id": "labelled", "lane": "normal", "key": "search"}
+	for k, v := range want {
+		if labels[k] != v {
+			t.Errorf("Label %s = %q, want %q", k, labels[k], v)
+		}
+	}
+}
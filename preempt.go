@@ -0,0 +1,76 @@
+package fastscheduler
+
+import "log/slog"
+
+// WithPreemption 启用抢占：高优先级通道的任务入队时如果所有worker都在执行，
+// 取消一个更低优先级通道中最晚开始的执行(优先选择后台通道)，被抢占的任务重新入队，
+// 不计入执行次数、不触发重试也不计入熔断。腾出的worker按优先级先执行新到的任务。
+// 抢占通过取消任务的 ctx 实现，不响应 ctx 的任务要等执行结束才会让出worker；隔离舱中的任务不会被抢占
+func WithPreemption() Option {
+	return func(s *Scheduler) {
+		s.preemption = true
+	}
+}
+
+// laneRank 返回通道的优先级序号，数值越小越优先
+func laneRank(lane Lane) int {
+	for i, l := range laneOrder {
+		if l == lane {
+			return i
+		}
+	}
+	return len(laneOrder)
+}
+
+// preempt 共享worker池已满时，为 lane 的新任务抢占一个更低优先级的执行
+func (s *Scheduler) preempt(lane Lane) {
+	if busy, size := s.workerPool.usage(); busy < size {
+		return
+	}
+	rank := laneRank(lane)
+	var victim *Task
+	var victimInfo TaskInfo
+	s.running.Range(func(k, v any) bool {
+		t, info := k.(*Task), v.(TaskInfo)
+		if t.bulkhead != nil || laneRank(info.Lane) <= rank {
+			return true
+		}
+		// 优先抢占最低优先级通道中最晚开始的执行，浪费的工作最少
+		if victim == nil || laneRank(info.Lane) > laneRank(victimInfo.Lane) ||
+			(info.Lane == victimInfo.Lane && info.Started.After(victimInfo.Started)) {
+			victim, victimInfo = t, info
+		}
+		return true
+	})
+	if victim == nil {
+		return
+	}
+
+	g := victim.group
+	g.taskMu.Lock()
+	defer g.taskMu.Unlock()
+	if victim.taskCancel == nil || victim.cancelled || victim.preempted {
+		return
+	}
+	victim.preempted = true
+	victim.taskCancel()
+}
+
+// takePreempted 返回任务本次执行是否被抢占，并清除标记
+func (g *taskGroup) takePreempted(t *Task) bool {
+	g.taskMu.Lock()
+	defer g.taskMu.Unlock()
+	preempted := t.preempted
+	t.preempted = false
+	return preempted
+}
+
+// requeuePreempted 被抢占的任务重新入队，本次执行不计入执行次数
+func (s *Scheduler) requeuePreempted(task *Task) {
+	task.attempts--
+	task.reason = "requeued after preemption"
+	s.logTask(slog.LevelInfo, "task preempted", task)
+	s.publishTask(EventTaskPreempted, task, nil)
+	s.releaseHost(task)
+	s.requeue(task)
+}
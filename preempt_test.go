@@ -0,0 +1,95 @@
+package fastscheduler
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_Preemption(t *testing.T) {
+	scheduler := NewScheduler(2, 10, WithPreemption())
+	defer scheduler.Stop()
+
+	release := make(chan struct{})
+	var started atomic.Int32
+	var runs [2]atomic.Int32
+	var tasks []*Task
+	for i := 0; i < 2; i++ {
+		tasks = append(tasks, &Task{
+			ID: fmt.Sprintf("reindex-%d", i),
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				runs[i].Add(1)
+				started.Add(1)
+				select {
+				case <-release:
+					return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+				case <-ctx.Done():
+					return TaskResult{}, ctx.Err()
+				}
+			},
+		})
+	}
+	background, err := scheduler.SubmitBackground(tasks[:1])
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	waitFor(t, func() bool { return started.Load() == 1 })
+	// 第二个任务晚于第一个开始，是最晚开始的执行
+	youngest, err := scheduler.SubmitBackground(tasks[1:])
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	waitFor(t, func() bool { return started.Load() == 2 })
+
+	events := scheduler.Subscribe(EventTypes(EventTaskPreempted))
+	interactive, err := scheduler.SubmitInteractive([]*Task{{
+		ID: "user-request",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: 200}, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := interactive.WaitContext(ctx); err != nil {
+		t.Fatalf("Interactive task was not given a worker: %v", err)
+	}
+	select {
+	case e := <-events:
+		if e.TaskID != "reindex-1" {
+			t.Errorf("Expected the youngest background task to be preempted, got %s", e.TaskID)
+		}
+	case <-ctx.Done():
+		t.Fatal("Expected a preemption event")
+	}
+
+	// 被抢占的任务重新入队执行，不计入执行次数
+	waitFor(t, func() bool { return runs[1].Load() == 2 })
+	close(release)
+	background.Wait()
+	youngest.Wait()
+	if runs[0].Load() != 1 {
+		t.Errorf("Expected the older task to run once, got %d", runs[0].Load())
+	}
+	for r := range youngest.Iter() {
+		if r.Attempts != 1 || r.HTTPCode != 500 {
+			t.Errorf("Expected preempted task to finish normally with 1 attempt, got %+v", r)
+		}
+	}
+}
+
+// waitFor 等待条件成立，超时后测试失败
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
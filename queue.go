@@ -0,0 +1,174 @@
+package fastscheduler
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Queue 任务队列后端，实现需要并发安全
+// 队列只负责存取任务，排队策略(先进先出、优先级等)由实现决定
+type Queue interface {
+	// Push 放入任务，队列已满时返回 ErrQueueFull，不阻塞
+	Push(t *Task) error
+	// Pop 取出下一个任务，队列为空时返回 false，不阻塞
+	Pop() (*Task, bool)
+	// Len 返回排队中的任务数
+	Len() int
+}
+
+// Acker 由持久化队列实现，任务完成后确认，未确认的任务在队列重新打开后再次投递
+// 调度器在任务结束(成功、最终失败或被跳过)时调用 Ack；重试的任务会被再次 Push
+// 对不是从该队列取出的任务调用 Ack 时不做任何操作
+type Acker interface {
+	Ack(t *Task) error
+}
+
+// ResultAcker 由需要任务结果的队列实现，例如把其他实例提交的任务的结果交回提交方
+// 队列实现 ResultAcker 时调度器调用 AckResult 代替 Ack，result 为任务的最终结果
+type ResultAcker interface {
+	AckResult(t *Task, result TaskResult) error
+}
+
+// Waker 由可能被其他进程写入的队列实现
+// Wake 返回的通道在队列可能有新任务时可读，调度goroutine据此重新检查队列
+type Waker interface {
+	Wake() <-chan struct{}
+}
+
+// WithQueue 指定各优先级通道的队列实现，capacity 为 NewScheduler 的 queueSize
+// 例如用优先级堆或持久化队列代替默认的 NewFIFOQueue
+func WithQueue(newQueue func(lane Lane, capacity int) Queue) Option {
+	return func(s *Scheduler) {
+		s.newQueue = newQueue
+	}
+}
+
+// fifoQueue 基于带缓冲通道的先进先出队列
+type fifoQueue struct {
+	tasks chan *Task
+}
+
+// NewFIFOQueue 创建容量为 capacity 的先进先出队列，调度器默认使用此队列
+// capacity 小于1时按1处理：Push 不阻塞，无法实现无缓冲的直接交接，最多缓冲一个任务
+func NewFIFOQueue(capacity int) Queue {
+	return &fifoQueue{tasks: make(chan *Task, max(capacity, 1))}
+}
+
+func (q *fifoQueue) Push(t *Task) error {
+	select {
+	case q.tasks <- t:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (q *fifoQueue) Pop() (*Task, bool) {
+	select {
+	case t := <-q.tasks:
+		return t, true
+	default:
+		return nil, false
+	}
+}
+
+func (q *fifoQueue) Len() int {
+	return len(q.tasks)
+}
+
+// pushTask 将任务放入所属通道的队列并唤醒调度goroutine
+func (s *Scheduler) pushTask(t *Task) error {
+	// 入队后任务可能立即被worker执行，先取日志属性
+	var attrs []slog.Attr
+	if s.logEnabled(slog.LevelDebug) {
+		attrs = taskAttrs(t)
+	}
+	lane := t.lane
+	if err := s.queues[lane].Push(t); err != nil {
+		if err == ErrQueueFull {
+			s.publishTask(EventQueueFull, t, nil)
+		}
+		return err
+	}
+	if attrs != nil {
+		s.logger.LogAttrs(context.Background(), slog.LevelDebug, "task enqueued", attrs...)
+	}
+	select {
+	case s.queued <- struct{}{}:
+	default:
+	}
+	if s.preemption {
+		s.preempt(lane)
+	}
+	return nil
+}
+
+// popTask 从通道的队列取出任务，并唤醒等待空位的提交方
+// 工作窃取模式下 home 为调度goroutine的分片，优先取出该分片的任务
+func (s *Scheduler) popTask(lane Lane, home int) (*Task, bool) {
+	var t *Task
+	var ok bool
+	if q, sharded := s.queues[lane].(*stealingQueue); sharded && home != noShard {
+		t, ok = q.popFrom(home)
+	} else {
+		t, ok = s.queues[lane].Pop()
+	}
+	if !ok {
+		return nil, false
+	}
+	if t.group == nil {
+		s.adopt(t, lane)
+	}
+	if s.spaceWaiters.Load() > 0 {
+		s.spaceMu.Lock()
+		close(s.space)
+		s.space = make(chan struct{})
+		s.spaceMu.Unlock()
+	}
+	return t, true
+}
+
+// adopt 为不是由本调度器提交的任务(其他进程写入或重新打开后恢复)创建单任务批次
+func (s *Scheduler) adopt(t *Task, lane Lane) {
+	// 未指定ID时 newBatch 不会失败
+	batch, _ := s.newBatch(lane, []*Task{t}, nil)
+	group := batch.group
+	group.results = make(chan TaskResult, 1)
+	group.total.Store(1)
+	group.remaining.Store(1)
+	group.closed.Store(true)
+	group.wg.Add(1)
+	group.attach(t, lane, s.now())
+}
+
+// ack 任务结束后向持久化队列确认
+func (s *Scheduler) ack(t *Task, result TaskResult) {
+	// 确认失败时任务在队列重新打开后再次投递，符合至少一次语义
+	switch q := s.queues[t.lane].(type) {
+	case ResultAcker:
+		_ = q.AckResult(t, result)
+	case Acker:
+		_ = q.Ack(t)
+	}
+}
+
+// resolvedResult 任务在其他进程中执行的结果
+type resolvedResult struct {
+	result TaskResult
+	err    error
+}
+
+// ResolveTask 标记排队中的任务已在其他进程中执行完成，调度器取出该任务时不再执行，直接以 result 和 err 结束
+// 供多个实例共享的队列实现把其他实例的执行结果交回提交方的批次，只能在任务位于队列中时调用
+func ResolveTask(t *Task, result TaskResult, err error) {
+	t.resolved = &resolvedResult{result: result, err: err}
+}
+
+// BatchID 返回任务所属批次的ID，任务未提交到调度器时返回空字符串
+// 队列实现可以据此区分本实例提交的任务和其他进程写入的任务
+func (t *Task) BatchID() string {
+	if t.group == nil {
+		return ""
+	}
+	return t.group.id
+}
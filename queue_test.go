@@ -0,0 +1,177 @@
+package fastscheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+// stackQueue 后进先出的测试队列
+type stackQueue struct {
+	mu    sync.Mutex
+	tasks []*Task
+}
+
+func (q *stackQueue) Push(t *Task) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.tasks = append(q.tasks, t)
+	return nil
+}
+
+func (q *stackQueue) Pop() (*Task, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.tasks) == 0 {
+		return nil, false
+	}
+	t := q.tasks[len(q.tasks)-1]
+	q.tasks = q.tasks[:len(q.tasks)-1]
+	return t, true
+}
+
+func (q *stackQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.tasks)
+}
+
+func TestWithQueue(t *testing.T) {
+	scheduler := NewScheduler(1, 10, WithManualStart(), WithQueue(func(lane Lane, capacity int) Queue {
+		return &stackQueue{}
+	}))
+	defer scheduler.Stop()
+
+	var mu sync.Mutex
+	var order []string
+	var tasks []*Task
+	for _, id := range []string{"a", "b", "c"} {
+		tasks = append(tasks, &Task{
+			ID: id,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				mu.Lock()
+				order = append(order, id)
+				mu.Unlock()
+				return TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+			},
+		})
+	}
+	batch, err := scheduler.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	scheduler.Start()
+	batch.Wait()
+
+	if got := order; len(got) != 3 || got[0] != "c" || got[2] != "a" {
+		t.Errorf("Expected tasks in LIFO order, got %v", got)
+	}
+}
+
+func TestScheduler_BlockingEnqueueOnFullQueue(t *testing.T) {
+	scheduler := NewScheduler(2, 1)
+	defer scheduler.Stop()
+
+	tasks := make([]*Task, 20)
+	for i := range tasks {
+		tasks[i] = &Task{
+			ID: "t",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+			},
+		}
+	}
+	batch, err := scheduler.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	batch.Wait()
+	if done, total := batch.Progress(); done != total {
+		t.Errorf("Expected all tasks to complete, got %d/%d", done, total)
+	}
+}
+
+func TestScheduler_ZeroQueueSizeBuffersOneTask(t *testing.T) {
+	scheduler := NewScheduler(1, 0, WithManualStart(), WithOverflowPolicy(OverflowReject))
+	defer scheduler.Stop()
+
+	noop := func(ctx context.Context) (TaskResult, error) {
+		return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+	}
+	// 调度器未启动，没有worker取走任务：第一个任务进入容量为1的队列，第二个被拒绝
+	first, err := scheduler.SubmitBatch([]*Task{{ID: "a", Execute: noop}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if _, err := scheduler.SubmitBatch([]*Task{{ID: "b", Execute: noop}}); err != ErrQueueFull {
+		t.Fatalf("Expected ErrQueueFull for the second task, got %v", err)
+	}
+	scheduler.Start()
+	first.Wait()
+	if !first.IsSuccess() {
+		t.Error("Expected the buffered task to run after Start")
+	}
+
+	// 阻塞入队时容量为0的通道同样可以完成任意数量的任务
+	blocking := NewScheduler(2, 0)
+	defer blocking.Stop()
+	tasks := make([]*Task, 20)
+	for i := range tasks {
+		tasks[i] = &Task{ID: "t", Execute: noop}
+	}
+	batch, err := blocking.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	batch.Wait()
+	if done, total := batch.Progress(); done != total {
+		t.Errorf("Expected all tasks to complete, got %d/%d", done, total)
+	}
+}
+
+// resolvingQueue 把放入的任务标记为已在其他进程中完成，并记录确认时收到的结果
+type resolvingQueue struct {
+	stackQueue
+	acked chan TaskResult
+}
+
+func (q *resolvingQueue) Push(t *Task) error {
+	ResolveTask(t, TaskResult{HTTPCode: 200, Data: "remote " + t.BatchID()}, nil)
+	return q.stackQueue.Push(t)
+}
+
+func (q *resolvingQueue) AckResult(t *Task, result TaskResult) error {
+	q.acked <- result
+	return nil
+}
+
+func TestResolveTask(t *testing.T) {
+	q := &resolvingQueue{acked: make(chan TaskResult, 1)}
+	scheduler := NewScheduler(1, 10, WithQueue(func(lane Lane, capacity int) Queue {
+		return q
+	}))
+	defer scheduler.Stop()
+
+	executed := false
+	batch, err := scheduler.SubmitBatch([]*Task{{
+		ID: "task",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			executed = true
+			return TaskResult{HTTPCode: 500}, nil
+		},
+	}}, WithBatchID("b1"))
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	batch.Wait()
+
+	if executed {
+		t.Error("Resolved task should not be executed")
+	}
+	if !batch.IsSuccess() {
+		t.Error("Expected batch to finish with the resolved result")
+	}
+	if result := <-q.acked; result.Data != "remote b1" {
+		t.Errorf("Expected AckResult to receive the final result, got %v", result.Data)
+	}
+}
@@ -0,0 +1,239 @@
+// Package queuetest 提供 fastscheduler.Queue 实现的一致性测试
+// 第三方队列后端(Redis、SQS、bbolt 等)可以在自己的测试中调用 TestBackend，
+// 确认实现满足调度器依赖的语义
+package queuetest
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+// Backend 描述被测的队列实现
+type Backend struct {
+	// New 创建一个空队列，每个子测试调用一次
+	New func(t *testing.T) fastscheduler.Queue
+	// Capacity 队列容量，0表示不限制
+	Capacity int
+	// Unordered 为true时不检查先进先出顺序(例如优先级队列)
+	Unordered bool
+	// Reopen 在同一存储上重新打开队列，用于检查持久化和确认语义，为nil时跳过相关测试
+	Reopen func(t *testing.T, q fastscheduler.Queue) fastscheduler.Queue
+}
+
+// TestBackend 运行全部一致性测试
+func TestBackend(t *testing.T, b Backend) {
+	t.Run("Empty", func(t *testing.T) { testEmpty(t, b) })
+	t.Run("Order", func(t *testing.T) { testOrder(t, b) })
+	t.Run("Capacity", func(t *testing.T) { testCapacity(t, b) })
+	t.Run("Concurrent", func(t *testing.T) { testConcurrent(t, b) })
+	t.Run("Persistence", func(t *testing.T) { testPersistence(t, b) })
+}
+
+// task 创建只带ID的测试任务
+func task(id string) *fastscheduler.Task {
+	return &fastscheduler.Task{ID: id}
+}
+
+// testEmpty 空队列 Pop 返回 false，Len 为0
+func testEmpty(t *testing.T, b Backend) {
+	q := b.New(t)
+	if n := q.Len(); n != 0 {
+		t.Errorf("Len of new queue = %d, want 0", n)
+	}
+	if task, ok := q.Pop(); ok {
+		t.Errorf("Pop on empty queue returned %v", task.ID)
+	}
+}
+
+// testOrder 所有任务恰好取出一次，Len 随之变化，有序队列按先进先出
+func testOrder(t *testing.T, b Backend) {
+	q := b.New(t)
+	n := 5
+	if b.Capacity > 0 && b.Capacity < n {
+		n = b.Capacity
+	}
+	for i := 0; i < n; i++ {
+		if err := q.Push(task(fmt.Sprint(i))); err != nil {
+			t.Fatalf("Push %d: %v", i, err)
+		}
+		if got := q.Len(); got != i+1 {
+			t.Errorf("Len after %d pushes = %d", i+1, got)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < n; i++ {
+		got, ok := q.Pop()
+		if !ok {
+			t.Fatalf("Pop %d: queue empty", i)
+		}
+		if !b.Unordered && got.ID != fmt.Sprint(i) {
+			t.Errorf("Pop %d = %s, want FIFO order", i, got.ID)
+		}
+		if seen[got.ID] {
+			t.Errorf("Task %s delivered twice", got.ID)
+		}
+		seen[got.ID] = true
+		ack(t, q, got)
+	}
+	if got := q.Len(); got != 0 {
+		t.Errorf("Len after draining = %d", got)
+	}
+}
+
+// testCapacity 有界队列已满时 Push 返回 ErrQueueFull，取出后可以继续放入
+func testCapacity(t *testing.T, b Backend) {
+	if b.Capacity == 0 {
+		t.Skip("unbounded queue")
+	}
+	q := b.New(t)
+	for i := 0; i < b.Capacity; i++ {
+		if err := q.Push(task(fmt.Sprint(i))); err != nil {
+			t.Fatalf("Push %d: %v", i, err)
+		}
+	}
+	if err := q.Push(task("overflow")); !errors.Is(err, fastscheduler.ErrQueueFull) {
+		t.Fatalf("Push on full queue = %v, want ErrQueueFull", err)
+	}
+	got, ok := q.Pop()
+	if !ok {
+		t.Fatal("Pop on full queue returned false")
+	}
+	ack(t, q, got)
+	if err := q.Push(task("after-pop")); err != nil {
+		t.Errorf("Push after Pop: %v", err)
+	}
+}
+
+// testConcurrent 并发读写时每个任务恰好被取出一次
+func testConcurrent(t *testing.T, b Backend) {
+	q := b.New(t)
+	const producers, perProducer = 4, 50
+
+	var (
+		mu   sync.Mutex
+		seen = make(map[string]int)
+		wg   sync.WaitGroup
+	)
+	total := producers * perProducer
+	done := make(chan struct{})
+	for c := 0; c < 4; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				got, ok := q.Pop()
+				if !ok {
+					select {
+					case <-done:
+						return
+					default:
+						runtime.Gosched()
+						continue
+					}
+				}
+				ack(t, q, got)
+				mu.Lock()
+				seen[got.ID]++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	var pwg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		pwg.Add(1)
+		go func() {
+			defer pwg.Done()
+			for i := 0; i < perProducer; i++ {
+				id := fmt.Sprintf("%d-%d", p, i)
+				// 有界队列满时重试
+				for {
+					err := q.Push(task(id))
+					if err == nil {
+						break
+					}
+					if !errors.Is(err, fastscheduler.ErrQueueFull) {
+						t.Errorf("Push %s: %v", id, err)
+						return
+					}
+				}
+			}
+		}()
+	}
+	pwg.Wait()
+	close(done)
+	wg.Wait()
+
+	// 消费者退出前可能有剩余任务
+	for {
+		got, ok := q.Pop()
+		if !ok {
+			break
+		}
+		ack(t, q, got)
+		seen[got.ID]++
+	}
+	if len(seen) != total {
+		t.Errorf("Received %d distinct tasks, want %d", len(seen), total)
+	}
+	for id, n := range seen {
+		if n != 1 {
+			t.Errorf("Task %s delivered %d times", id, n)
+		}
+	}
+}
+
+// testPersistence 重新打开后保留未取出和未确认的任务，已确认的任务不再投递
+func testPersistence(t *testing.T, b Backend) {
+	if b.Reopen == nil {
+		t.Skip("queue is not persistent")
+	}
+	q := b.New(t)
+	for _, id := range []string{"acked", "unacked", "queued"} {
+		if err := q.Push(task(id)); err != nil {
+			t.Fatalf("Push %s: %v", id, err)
+		}
+	}
+	first, _ := q.Pop()
+	ack(t, q, first)
+	second, ok := q.Pop()
+	if !ok {
+		t.Fatal("Pop returned false")
+	}
+
+	q = b.Reopen(t, q)
+	remaining := make(map[string]bool)
+	for {
+		got, ok := q.Pop()
+		if !ok {
+			break
+		}
+		remaining[got.ID] = true
+		ack(t, q, got)
+	}
+
+	if remaining[first.ID] {
+		t.Errorf("Acked task %s redelivered after reopen", first.ID)
+	}
+	if !remaining[second.ID] {
+		t.Errorf("Unacked task %s lost after reopen", second.ID)
+	}
+	if len(remaining) != 2 {
+		t.Errorf("Expected 2 tasks after reopen, got %v", remaining)
+	}
+}
+
+// ack 队列实现 Acker 时确认任务
+func ack(t *testing.T, q fastscheduler.Queue, task *fastscheduler.Task) {
+	if a, ok := q.(fastscheduler.Acker); ok {
+		if err := a.Ack(task); err != nil {
+			t.Errorf("Ack %s: %v", task.ID, err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package queuetest
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+	"github.com/hawkli-1994/fast-scheduler/filestore"
+)
+
+// memQueue 用于验证一致性测试本身的参考实现
+// storage 模拟持久化存储：保存未确认的任务，重新打开时恢复
+type memQueue struct {
+	mu       sync.Mutex
+	capacity int
+	queued   []*fastscheduler.Task
+	storage  *memStorage
+}
+
+type memStorage struct {
+	mu      sync.Mutex
+	order   []string
+	pending map[string]*fastscheduler.Task
+}
+
+func (q *memQueue) Push(t *fastscheduler.Task) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.capacity > 0 && len(q.queued) >= q.capacity {
+		return fastscheduler.ErrQueueFull
+	}
+	q.queued = append(q.queued, t)
+	if s := q.storage; s != nil {
+		s.mu.Lock()
+		s.order = append(s.order, t.ID)
+		s.pending[t.ID] = t
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+func (q *memQueue) Pop() (*fastscheduler.Task, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.queued) == 0 {
+		return nil, false
+	}
+	t := q.queued[0]
+	q.queued = q.queued[1:]
+	return t, true
+}
+
+func (q *memQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.queued)
+}
+
+func (q *memQueue) Ack(t *fastscheduler.Task) error {
+	if s := q.storage; s != nil {
+		s.mu.Lock()
+		delete(s.pending, t.ID)
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// reopen 从存储恢复所有未确认的任务
+func (s *memStorage) reopen() *memQueue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q := &memQueue{storage: s}
+	var order []string
+	for _, id := range s.order {
+		if t, ok := s.pending[id]; ok {
+			q.queued = append(q.queued, t)
+			order = append(order, id)
+		}
+	}
+	s.order = order
+	return q
+}
+
+func TestBackend_Memory(t *testing.T) {
+	TestBackend(t, Backend{
+		New: func(t *testing.T) fastscheduler.Queue {
+			return &memQueue{}
+		},
+	})
+}
+
+func TestBackend_Bounded(t *testing.T) {
+	TestBackend(t, Backend{
+		New: func(t *testing.T) fastscheduler.Queue {
+			return &memQueue{capacity: 3}
+		},
+		Capacity: 3,
+	})
+}
+
+func TestBackend_Durable(t *testing.T) {
+	TestBackend(t, Backend{
+		New: func(t *testing.T) fastscheduler.Queue {
+			return &memQueue{storage: &memStorage{pending: make(map[string]*fastscheduler.Task)}}
+		},
+		Reopen: func(t *testing.T, q fastscheduler.Queue) fastscheduler.Queue {
+			return q.(*memQueue).storage.reopen()
+		},
+	})
+}
+
+func TestBackend_FIFO(t *testing.T) {
+	TestBackend(t, Backend{
+		New: func(t *testing.T) fastscheduler.Queue {
+			return fastscheduler.NewFIFOQueue(4)
+		},
+		Capacity: 4,
+	})
+}
+
+func TestBackend_DurableQueue(t *testing.T) {
+	open := func(t *testing.T, path string) fastscheduler.Queue {
+		store, err := filestore.Open(path)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		t.Cleanup(func() { store.Close() })
+		q, err := fastscheduler.NewDurableQueue(store, fastscheduler.DurableQueueConfig{
+			Marshal: func(t *fastscheduler.Task) ([]byte, error) { return []byte(t.ID), nil },
+			Unmarshal: func(data []byte) (*fastscheduler.Task, error) {
+				return &fastscheduler.Task{ID: string(data)}, nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("NewDurableQueue failed: %v", err)
+		}
+		return q
+	}
+	var path string
+	TestBackend(t, Backend{
+		New: func(t *testing.T) fastscheduler.Queue {
+			path = filepath.Join(t.TempDir(), "queue.log")
+			return open(t, path)
+		},
+		Reopen: func(t *testing.T, q fastscheduler.Queue) fastscheduler.Queue {
+			return open(t, path)
+		},
+	})
+}
@@ -0,0 +1,54 @@
+package fastscheduler
+
+import (
+	"context"
+	"fmt"
+)
+
+// Race 并发执行所有函数，返回第一个成功的结果并取消其余函数
+// 每次调用使用一个临时调度器；需要复用worker池时使用 Scheduler.Race
+// 全部失败时返回最后完成的结果和 ErrAllFailed
+func Race(ctx context.Context, fns ...func(ctx context.Context) (TaskResult, error)) (TaskResult, error) {
+	s := NewScheduler(len(fns), len(fns))
+	result, err := s.Race(ctx, fns...)
+	// 失败方可能仍在处理取消，在后台回收临时调度器
+	go s.Stop()
+	return result, err
+}
+
+// Race 在调度器上并发执行所有函数，返回第一个成功的结果并取消其余函数
+func (s *Scheduler) Race(ctx context.Context, fns ...func(ctx context.Context) (TaskResult, error)) (TaskResult, error) {
+	tasks := make([]*Task, len(fns))
+	for i, fn := range fns {
+		tasks[i] = &Task{
+			ID:      fmt.Sprintf("race-%d", i),
+			Execute: fn,
+		}
+	}
+	return s.raceTasks(ctx, tasks)
+}
+
+// raceTasks 提交任务并返回第一个成功的结果，ctx 结束时取消批次
+func (s *Scheduler) raceTasks(ctx context.Context, tasks []*Task) (TaskResult, error) {
+	batch, err := s.SubmitBatch(tasks)
+	if err != nil {
+		return TaskResult{}, err
+	}
+
+	var last TaskResult
+	for {
+		select {
+		case result, ok := <-batch.ResultsChan():
+			if !ok {
+				return last, ErrAllFailed
+			}
+			if isSuccess(result) {
+				return result, nil
+			}
+			last = result
+		case <-ctx.Done():
+			batch.group.cancel()
+			return TaskResult{}, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRace_FirstSuccessWins(t *testing.T) {
+	slow := func(ctx context.Context) (TaskResult, error) {
+		select {
+		case <-time.After(time.Second):
+			return TaskResult{HTTPCode: 200, BusinessCode: 0, Data: "slow"}, nil
+		case <-ctx.Done():
+			return TaskResult{}, ctx.Err()
+		}
+	}
+	fast := func(ctx context.Context) (TaskResult, error) {
+		return TaskResult{HTTPCode: 200, BusinessCode: 0, Data: "fast"}, nil
+	}
+
+	start := time.Now()
+	result, err := Race(context.Background(), slow, fast)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Data != "fast" {
+		t.Errorf("Expected fast result, got %v", result.Data)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("Race should return without waiting for losers")
+	}
+}
+
+func TestRace_AllFailed(t *testing.T) {
+	fail := func(ctx context.Context) (TaskResult, error) {
+		return TaskResult{}, errors.New("boom")
+	}
+
+	result, err := Race(context.Background(), fail, fail)
+	if !errors.Is(err, ErrAllFailed) {
+		t.Errorf("Expected ErrAllFailed, got %v", err)
+	}
+	if result.Err == nil {
+		t.Error("Expected last failed result to be returned")
+	}
+}
@@ -0,0 +1,523 @@
+// Package redisqueue 提供基于 Redis 列表的持久化任务队列，实现 fastscheduler.Queue
+// 排队中的任务在进程重启后保留，多个调度器实例可以共享同一个队列消费任务
+//
+// 待处理任务保存在 Config.Key 列表中，取出的任务移动到消费者自己的处理中列表，
+// 确认后删除；同名消费者重新打开队列时，未确认的任务回到待处理列表再次投递(至少一次)。
+// 本实例批次中的任务被其他实例取出执行时，结果写入提交方的结果列表，由提交方交回原批次
+package redisqueue
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+// defaultPollInterval 检查其他实例写入的任务和交回的结果的默认间隔
+const defaultPollInterval = time.Second
+
+// resultTTL 结果列表的过期时间，提交方已退出时结果在此之后由 Redis 删除
+const resultTTL = time.Hour
+
+// ErrResultTimeout 本实例提交的任务在 Config.ResultTimeout 内没有结果
+var ErrResultTimeout = errors.New("redisqueue: timed out waiting for task result")
+
+// Config 队列配置
+type Config struct {
+	// Addr Redis 地址，例如 "localhost:6379"
+	Addr string
+	// Password 非空时连接后执行 AUTH
+	Password string
+	// DB 非0时连接后执行 SELECT
+	DB int
+	// Key 待处理任务列表的键
+	Key string
+	// Consumer 消费者名称，默认 "default"，不能包含冒号，同时运行的实例应使用不同的名称
+	// 取出未确认的任务保存在 Key+":processing:"+Consumer，同名消费者重新打开时恢复；
+	// 其他实例交回的结果保存在 Key+":results:"+Consumer
+	Consumer string
+	// Capacity 最大排队任务数，0表示不限制；多个实例同时写入时为近似限制
+	Capacity int
+	// PollInterval 检查其他实例写入的任务和交回的结果的间隔，默认1秒
+	PollInterval time.Duration
+	// Timeout 单条命令的超时时间，0表示不限制
+	Timeout time.Duration
+
+	// Marshal 序列化任务，默认 fastscheduler.MarshalTask，只能写入由 SerializableTask 创建的任务
+	Marshal func(t *fastscheduler.Task) ([]byte, error)
+	// Unmarshal 由 Marshal 的结果重建任务，用于其他实例写入或重启后恢复的任务，默认 fastscheduler.UnmarshalTask
+	Unmarshal func(data []byte) (*fastscheduler.Task, error)
+
+	// Codec 交回结果时 TaskResult.Data 的编码，默认 fastscheduler.JSONCodec
+	Codec fastscheduler.Codec
+	// ResultData 返回解码交回结果的 Data 所用的指针，默认 new(interface{})
+	ResultData func() interface{}
+	// ResultTimeout 本实例批次中的任务从放入起等待结果的最长时间，
+	// 超时后任务以 ErrResultTimeout 结束，之后交回的结果被忽略；0表示一直等待
+	ResultTimeout time.Duration
+}
+
+// Queue 基于 Redis 列表的任务队列
+type Queue struct {
+	cfg        Config
+	processing string
+	results    string
+	client     *client
+
+	mu sync.Mutex
+	// local 本实例批次中放入且尚未取出的任务，本实例取出时直接复用，保留闭包和批次信息；
+	// 被其他实例取出时等待其交回结果
+	local map[string]localTask
+	// remote 其他实例批次中由本实例取出的任务，结束后把结果交回提交方
+	remote map[*fastscheduler.Task]remoteTask
+	// inflight 已取出未确认的任务对应的列表元素
+	inflight map[*fastscheduler.Task]string
+	// resolved 已收到结果或等待超时的本实例任务，Pop 时优先取出，不再执行
+	resolved []*fastscheduler.Task
+	// length 待处理任务数的缓存，empty 表示上次取出时列表为空，此后 Pop 不再访问 Redis；
+	// 本实例放入任务或轮询发现待处理任务时更新，gen 随之递增，用于检测并发放入
+	length int
+	empty  bool
+	gen    uint64
+	// err 最近一次访问 Redis 失败的错误
+	err error
+
+	wake chan struct{}
+	stop chan struct{}
+	once sync.Once
+}
+
+// localTask 本实例批次中的任务及其放入时间
+type localTask struct {
+	task   *fastscheduler.Task
+	pushed time.Time
+}
+
+// remoteTask 其他实例批次中的任务在列表中的ID和提交方的消费者名称
+type remoteTask struct {
+	id     string
+	origin string
+}
+
+// Open 连接 Redis 并恢复该消费者未确认的任务
+func Open(cfg Config) (*Queue, error) {
+	if cfg.Key == "" {
+		return nil, errors.New("redisqueue: Key is required")
+	}
+	if cfg.Marshal == nil {
+		cfg.Marshal = fastscheduler.MarshalTask
+	}
+	if cfg.Unmarshal == nil {
+		cfg.Unmarshal = fastscheduler.UnmarshalTask
+	}
+	if cfg.Codec == nil {
+		cfg.Codec = fastscheduler.JSONCodec
+	}
+	if cfg.ResultData == nil {
+		cfg.ResultData = func() interface{} { return new(interface{}) }
+	}
+	if cfg.Consumer == "" {
+		cfg.Consumer = "default"
+	}
+	if strings.Contains(cfg.Consumer, ":") {
+		return nil, fmt.Errorf("redisqueue: Consumer %q must not contain ':'", cfg.Consumer)
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+
+	q := &Queue{
+		cfg:        cfg,
+		processing: cfg.Key + ":processing:" + cfg.Consumer,
+		results:    cfg.Key + ":results:" + cfg.Consumer,
+		client:     &client{addr: cfg.Addr, password: cfg.Password, db: cfg.DB, timeout: cfg.Timeout},
+		local:      make(map[string]localTask),
+		remote:     make(map[*fastscheduler.Task]remoteTask),
+		inflight:   make(map[*fastscheduler.Task]string),
+		wake:       make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+	}
+	if err := q.recover(); err != nil {
+		q.client.close()
+		return nil, err
+	}
+	n, err := q.llen()
+	if err != nil {
+		q.client.close()
+		return nil, err
+	}
+	q.length, q.empty = n, n == 0
+	go q.poll()
+	return q, nil
+}
+
+// recover 将处理中列表的任务移回待处理列表
+func (q *Queue) recover() error {
+	for {
+		reply, err := q.client.do("RPOPLPUSH", q.processing, q.cfg.Key)
+		if err != nil {
+			return err
+		}
+		if b, _ := reply.([]byte); b == nil {
+			return nil
+		}
+	}
+}
+
+// Push 放入任务，达到 Capacity 时返回 fastscheduler.ErrQueueFull
+// 已取出未确认的任务再次放入(例如重试)时，替换其原有的处理中记录；
+// 其他实例批次中的任务再次放入时保留提交方，无论由哪个实例取出，结果都交回提交方
+func (q *Queue) Push(t *fastscheduler.Task) error {
+	if q.cfg.Capacity > 0 {
+		n, err := q.llen()
+		if err != nil {
+			return err
+		}
+		if n >= q.cfg.Capacity {
+			return fastscheduler.ErrQueueFull
+		}
+	}
+
+	payload, err := q.cfg.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("redisqueue: marshal task %s: %w", t.ID, err)
+	}
+
+	q.mu.Lock()
+	remote, forwarded := q.remote[t]
+	q.mu.Unlock()
+	ref := remote
+	if !forwarded {
+		if ref.id, err = newEntryID(); err != nil {
+			return err
+		}
+		// 不属于批次的任务(例如只负责写入的实例放入的任务)没有等待结果的提交方
+		if t.BatchID() != "" {
+			ref.origin = q.cfg.Consumer
+		}
+	}
+	entry := ref.id + ":" + ref.origin + ":" + string(payload)
+
+	q.mu.Lock()
+	if !forwarded && ref.origin != "" {
+		q.local[ref.id] = localTask{task: t, pushed: time.Now()}
+	}
+	previous, retried := q.inflight[t]
+	delete(q.inflight, t)
+	delete(q.remote, t)
+	q.mu.Unlock()
+	if _, err := q.client.do("LPUSH", q.cfg.Key, entry); err != nil {
+		q.mu.Lock()
+		delete(q.local, ref.id)
+		if retried {
+			q.inflight[t] = previous
+		}
+		if forwarded {
+			q.remote[t] = remote
+		}
+		q.mu.Unlock()
+		return err
+	}
+	q.mu.Lock()
+	q.length++
+	q.empty = false
+	q.gen++
+	q.mu.Unlock()
+	if retried {
+		// 新记录写入后再删除旧记录，中途崩溃时任务不会丢失；删除失败只会导致重启后重复投递
+		q.client.do("LREM", q.processing, "1", previous)
+	}
+	return nil
+}
+
+// Pop 取出下一个任务：先取出已收到结果的本实例任务，再取出最早放入的任务
+// 队列为空或 Redis 不可用时返回 false，错误可通过 Err 查看；
+// 列表为空或 Redis 不可用后，直到本实例放入任务或轮询发现待处理任务前不再访问 Redis
+func (q *Queue) Pop() (*fastscheduler.Task, bool) {
+	q.mu.Lock()
+	if len(q.resolved) > 0 {
+		t := q.resolved[0]
+		q.resolved[0] = nil
+		q.resolved = q.resolved[1:]
+		q.mu.Unlock()
+		return t, true
+	}
+	if q.empty {
+		q.mu.Unlock()
+		return nil, false
+	}
+	gen := q.gen
+	q.mu.Unlock()
+
+	for {
+		reply, err := q.client.do("RPOPLPUSH", q.cfg.Key, q.processing)
+		if err != nil {
+			q.mu.Lock()
+			q.err = err
+			q.empty = true
+			q.mu.Unlock()
+			return nil, false
+		}
+		raw, _ := reply.([]byte)
+		if raw == nil {
+			q.mu.Lock()
+			if q.gen == gen {
+				q.length = 0
+				q.empty = true
+			}
+			q.mu.Unlock()
+			return nil, false
+		}
+		entry := string(raw)
+		q.mu.Lock()
+		q.length = max(q.length-1, 0)
+		q.mu.Unlock()
+
+		t, err := q.decode(entry)
+		if err != nil {
+			// 无法解码的任务不会被任何实例执行，丢弃以免反复投递
+			q.setErr(err)
+			q.client.do("LREM", q.processing, "1", entry)
+			continue
+		}
+		q.mu.Lock()
+		q.inflight[t] = entry
+		q.mu.Unlock()
+		return t, true
+	}
+}
+
+// decode 优先复用本实例放入的任务，否则使用 Unmarshal 重建
+// 重建的任务属于其他实例的批次时，记录提交方以便交回结果
+func (q *Queue) decode(entry string) (*fastscheduler.Task, error) {
+	id, rest, ok := strings.Cut(entry, ":")
+	origin, payload, ok2 := strings.Cut(rest, ":")
+	if !ok || !ok2 {
+		return nil, fmt.Errorf("redisqueue: malformed entry %q", entry)
+	}
+	q.mu.Lock()
+	lt, ok := q.local[id]
+	delete(q.local, id)
+	q.mu.Unlock()
+	if ok {
+		return lt.task, nil
+	}
+	t, err := q.cfg.Unmarshal([]byte(payload))
+	if err != nil {
+		return nil, fmt.Errorf("redisqueue: unmarshal entry %s: %w", id, err)
+	}
+	// 本实例放入但已不在 local 中(等待超时或重启前放入)的任务没有等待结果的批次
+	if origin != "" && origin != q.cfg.Consumer {
+		q.mu.Lock()
+		q.remote[t] = remoteTask{id: id, origin: origin}
+		q.mu.Unlock()
+	}
+	return t, nil
+}
+
+// Len 返回待处理任务数，不访问 Redis；其他实例写入的任务在下次轮询后计入
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.length + len(q.resolved)
+}
+
+func (q *Queue) llen() (int, error) {
+	reply, err := q.client.do("LLEN", q.cfg.Key)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := reply.(int64)
+	return int(n), nil
+}
+
+// Ack 确认任务已完成，从处理中列表删除
+// 其他实例批次中的任务不交回结果，提交方等待至 ResultTimeout；调度器使用 AckResult
+func (q *Queue) Ack(t *fastscheduler.Task) error {
+	return q.ack(t, nil)
+}
+
+// AckResult 实现 fastscheduler.ResultAcker：其他实例批次中的任务先把结果交回提交方，再从处理中列表删除
+// 交回失败时返回错误且不删除记录，任务在重新打开后再次投递
+func (q *Queue) AckResult(t *fastscheduler.Task, result fastscheduler.TaskResult) error {
+	return q.ack(t, &result)
+}
+
+func (q *Queue) ack(t *fastscheduler.Task, result *fastscheduler.TaskResult) error {
+	q.mu.Lock()
+	entry, ok := q.inflight[t]
+	delete(q.inflight, t)
+	remote, forwarded := q.remote[t]
+	delete(q.remote, t)
+	q.mu.Unlock()
+	if forwarded && result != nil {
+		if err := q.sendResult(remote, *result); err != nil {
+			return err
+		}
+	}
+	if !ok {
+		return nil
+	}
+	_, err := q.client.do("LREM", q.processing, "1", entry)
+	return err
+}
+
+// sendResult 把结果写入提交方的结果列表
+func (q *Queue) sendResult(remote remoteTask, result fastscheduler.TaskResult) error {
+	w, err := fastscheduler.EncodeResult(q.cfg.Codec, result)
+	if err != nil {
+		// Data 无法编码时仍交回结果的其余部分，提交方的批次不会一直等待
+		result.Data = nil
+		w, _ = fastscheduler.EncodeResult(q.cfg.Codec, result)
+		w.Truncated = true
+	}
+	data, err := json.Marshal(w)
+	if err != nil {
+		return fmt.Errorf("redisqueue: encode result of %s: %w", result.TaskID, err)
+	}
+	key := q.cfg.Key + ":results:" + remote.origin
+	if _, err := q.client.do("LPUSH", key, remote.id+":"+string(data)); err != nil {
+		return err
+	}
+	// 过期时间只用于清理已退出的提交方的结果，设置失败不影响交回
+	q.client.do("EXPIRE", key, strconv.Itoa(int(resultTTL/time.Second)))
+	return nil
+}
+
+// Wake 实现 fastscheduler.Waker，在轮询发现待处理任务或收到结果时通知调度器
+func (q *Queue) Wake() <-chan struct{} {
+	return q.wake
+}
+
+// poll 定期检查其他实例写入的任务、交回的结果和等待超时的任务
+func (q *Queue) poll() {
+	ticker := time.NewTicker(q.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-q.stop:
+			return
+		}
+		ready := q.collectResults()
+		ready = q.expireLocal() || ready
+		if n, err := q.llen(); err != nil {
+			q.setErr(err)
+		} else {
+			q.mu.Lock()
+			q.length = n
+			if n > 0 {
+				q.empty = false
+				q.gen++
+			}
+			q.mu.Unlock()
+			ready = ready || n > 0
+		}
+		if ready {
+			select {
+			case q.wake <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// collectResults 取出其他实例交回的结果，对应的本实例任务以该结果结束，返回是否有任务可以取出
+func (q *Queue) collectResults() bool {
+	ready := false
+	for {
+		reply, err := q.client.do("RPOP", q.results)
+		if err != nil {
+			q.setErr(err)
+			return ready
+		}
+		raw, _ := reply.([]byte)
+		if raw == nil {
+			return ready
+		}
+		id, data, _ := strings.Cut(string(raw), ":")
+		q.mu.Lock()
+		lt, ok := q.local[id]
+		delete(q.local, id)
+		q.mu.Unlock()
+		if !ok {
+			// 任务已等待超时，或结果属于重启前的批次
+			continue
+		}
+		result, err := q.decodeResult([]byte(data))
+		if err == nil {
+			err = result.Err
+		}
+		fastscheduler.ResolveTask(lt.task, result, err)
+		q.mu.Lock()
+		q.resolved = append(q.resolved, lt.task)
+		q.mu.Unlock()
+		ready = true
+	}
+}
+
+// decodeResult 还原交回的结果
+func (q *Queue) decodeResult(data []byte) (fastscheduler.TaskResult, error) {
+	var w fastscheduler.WireResult
+	if err := json.Unmarshal(data, &w); err != nil {
+		return fastscheduler.TaskResult{}, fmt.Errorf("redisqueue: decode result: %w", err)
+	}
+	return fastscheduler.DecodeResult(q.cfg.Codec, w, q.cfg.ResultData())
+}
+
+// expireLocal 超过 ResultTimeout 的本实例任务以 ErrResultTimeout 结束，返回是否有任务可以取出
+func (q *Queue) expireLocal() bool {
+	if q.cfg.ResultTimeout <= 0 {
+		return false
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	expired := false
+	for id, lt := range q.local {
+		if time.Since(lt.pushed) < q.cfg.ResultTimeout {
+			continue
+		}
+		delete(q.local, id)
+		fastscheduler.ResolveTask(lt.task, fastscheduler.TaskResult{HTTPCode: 504}, ErrResultTimeout)
+		q.resolved = append(q.resolved, lt.task)
+		expired = true
+	}
+	return expired
+}
+
+// Err 返回最近一次访问 Redis 失败的错误
+func (q *Queue) Err() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.err
+}
+
+func (q *Queue) setErr(err error) {
+	q.mu.Lock()
+	q.err = err
+	q.mu.Unlock()
+}
+
+// Close 停止轮询并关闭连接，未确认的任务保留在 Redis 中
+func (q *Queue) Close() error {
+	q.once.Do(func() {
+		close(q.stop)
+	})
+	return q.client.close()
+}
+
+// newEntryID 生成列表元素的唯一前缀，使相同内容的任务可以分别确认
+func newEntryID() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
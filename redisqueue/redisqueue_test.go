@@ -0,0 +1,399 @@
+package redisqueue
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+	"github.com/hawkli-1994/fast-scheduler/queuetest"
+)
+
+// fakeRedis 支持队列所用列表命令的内存 Redis 服务
+type fakeRedis struct {
+	ln    net.Listener
+	mu    sync.Mutex
+	lists map[string][]string
+	// calls 各命令的执行次数
+	calls map[string]int
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	r := &fakeRedis{ln: ln, lists: make(map[string][]string), calls: make(map[string]int)}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go r.serve(conn)
+		}
+	}()
+	return r
+}
+
+func (r *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	br := bufio.NewReader(conn)
+	for {
+		reply, err := readReply(br)
+		if err != nil {
+			return
+		}
+		var args []string
+		for _, arg := range reply.([]interface{}) {
+			args = append(args, string(arg.([]byte)))
+		}
+		conn.Write([]byte(r.exec(args)))
+	}
+}
+
+// exec 执行命令，列表下标0为左端
+func (r *fakeRedis) exec(args []string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls[strings.ToUpper(args[0])]++
+	switch strings.ToUpper(args[0]) {
+	case "LPUSH":
+		r.lists[args[1]] = append([]string{args[2]}, r.lists[args[1]]...)
+		return ":" + strconv.Itoa(len(r.lists[args[1]])) + "\r\n"
+	case "RPOPLPUSH":
+		src := r.lists[args[1]]
+		if len(src) == 0 {
+			return "$-1\r\n"
+		}
+		v := src[len(src)-1]
+		r.lists[args[1]] = src[:len(src)-1]
+		r.lists[args[2]] = append([]string{v}, r.lists[args[2]]...)
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+	case "RPOP":
+		list := r.lists[args[1]]
+		if len(list) == 0 {
+			return "$-1\r\n"
+		}
+		v := list[len(list)-1]
+		r.lists[args[1]] = list[:len(list)-1]
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+	case "EXPIRE":
+		return ":1\r\n"
+	case "LLEN":
+		return ":" + strconv.Itoa(len(r.lists[args[1]])) + "\r\n"
+	case "LREM":
+		list := r.lists[args[1]]
+		for i, v := range list {
+			if v == args[3] {
+				r.lists[args[1]] = append(list[:i:i], list[i+1:]...)
+				return ":1\r\n"
+			}
+		}
+		return ":0\r\n"
+	default:
+		return "-ERR unknown command\r\n"
+	}
+}
+
+func (r *fakeRedis) called(cmd string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls[cmd]
+}
+
+func (r *fakeRedis) len(key string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.lists[key])
+}
+
+// testConfig 任务按ID编码，重建的任务记录执行到 executed
+func testConfig(addr string, executed chan<- string) Config {
+	return Config{
+		Addr:         addr,
+		Key:          "tasks",
+		PollInterval: 10 * time.Millisecond,
+		Marshal: func(t *fastscheduler.Task) ([]byte, error) {
+			return []byte(t.ID), nil
+		},
+		Unmarshal: func(data []byte) (*fastscheduler.Task, error) {
+			id := string(data)
+			return &fastscheduler.Task{
+				ID: id,
+				Execute: func(ctx context.Context) (fastscheduler.TaskResult, error) {
+					executed <- id
+					return fastscheduler.TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+				},
+			}, nil
+		},
+	}
+}
+
+func TestBackend_Redis(t *testing.T) {
+	server := newFakeRedis(t)
+	open := func(t *testing.T) fastscheduler.Queue {
+		q, err := Open(testConfig(server.ln.Addr().String(), nil))
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		t.Cleanup(func() { q.Close() })
+		return q
+	}
+	queuetest.TestBackend(t, queuetest.Backend{
+		New: func(t *testing.T) fastscheduler.Queue {
+			server.mu.Lock()
+			server.lists = make(map[string][]string)
+			server.mu.Unlock()
+			return open(t)
+		},
+		Reopen: func(t *testing.T, q fastscheduler.Queue) fastscheduler.Queue {
+			q.(*Queue).Close()
+			return open(t)
+		},
+	})
+}
+
+func TestQueue_ConsumesTasksFromOtherInstances(t *testing.T) {
+	server := newFakeRedis(t)
+	executed := make(chan string, 1)
+
+	consumer, err := Open(testConfig(server.ln.Addr().String(), executed))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer consumer.Close()
+	scheduler := fastscheduler.NewScheduler(2, 10, fastscheduler.WithQueue(func(lane fastscheduler.Lane, capacity int) fastscheduler.Queue {
+		if lane == fastscheduler.LaneNormal {
+			return consumer
+		}
+		return fastscheduler.NewFIFOQueue(capacity)
+	}))
+	defer scheduler.Stop()
+
+	// 另一个实例只负责写入任务
+	producerCfg := testConfig(server.ln.Addr().String(), nil)
+	producerCfg.Consumer = "producer"
+	producer, err := Open(producerCfg)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer producer.Close()
+	if err := producer.Push(&fastscheduler.Task{ID: "remote"}); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	select {
+	case id := <-executed:
+		if id != "remote" {
+			t.Errorf("Expected remote task, got %s", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Task pushed by another instance was not executed")
+	}
+
+	// 任务完成后被确认
+	deadline := time.Now().Add(time.Second)
+	for server.len("tasks:processing:default") != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Completed task was not acked")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestQueue_LocalTasksKeepClosures(t *testing.T) {
+	server := newFakeRedis(t)
+	q, err := Open(testConfig(server.ln.Addr().String(), nil))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer q.Close()
+	scheduler := fastscheduler.NewScheduler(2, 10, fastscheduler.WithQueue(func(lane fastscheduler.Lane, capacity int) fastscheduler.Queue {
+		if lane == fastscheduler.LaneNormal {
+			return q
+		}
+		return fastscheduler.NewFIFOQueue(capacity)
+	}))
+	defer scheduler.Stop()
+
+	batch, err := scheduler.SubmitBatch([]*fastscheduler.Task{{
+		ID: "local",
+		Execute: func(ctx context.Context) (fastscheduler.TaskResult, error) {
+			return fastscheduler.TaskResult{HTTPCode: 200, BusinessCode: 0, Data: "closure"}, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	batch.Wait()
+	if !batch.IsSuccess() {
+		t.Error("Expected local task to run its closure")
+	}
+	if n := server.len("tasks:processing:default"); n != 0 {
+		t.Errorf("Expected processing list to be empty, got %d", n)
+	}
+}
+
+// newInstance 创建使用共享队列的调度器实例，重建的任务返回执行它的实例名称
+func newInstance(t *testing.T, addr, consumer string, executed chan<- string, configure func(*Config)) (*fastscheduler.Scheduler, *Queue) {
+	cfg := testConfig(addr, nil)
+	cfg.Consumer = consumer
+	cfg.Unmarshal = func(data []byte) (*fastscheduler.Task, error) {
+		id := string(data)
+		return &fastscheduler.Task{
+			ID: id,
+			Execute: func(ctx context.Context) (fastscheduler.TaskResult, error) {
+				executed <- consumer
+				return fastscheduler.TaskResult{HTTPCode: 200, BusinessCode: 0, Data: "ran on " + consumer}, nil
+			},
+		}, nil
+	}
+	if configure != nil {
+		configure(&cfg)
+	}
+	q, err := Open(cfg)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { q.Close() })
+	scheduler := fastscheduler.NewScheduler(2, 10, fastscheduler.WithQueue(func(lane fastscheduler.Lane, capacity int) fastscheduler.Queue {
+		if lane == fastscheduler.LaneNormal {
+			return q
+		}
+		return fastscheduler.NewFIFOQueue(capacity)
+	}))
+	t.Cleanup(scheduler.Stop)
+	return scheduler, q
+}
+
+func TestQueue_ResultsReturnToSubmittingInstance(t *testing.T) {
+	server := newFakeRedis(t)
+	addr := server.ln.Addr().String()
+	executed := make(chan string, 4)
+
+	// a 暂停，任务只能由 b 取出
+	a, aq := newInstance(t, addr, "a", executed, nil)
+	a.Pause()
+	newInstance(t, addr, "b", executed, nil)
+
+	batch, err := a.SubmitBatch([]*fastscheduler.Task{{
+		ID: "job",
+		Execute: func(ctx context.Context) (fastscheduler.TaskResult, error) {
+			return fastscheduler.TaskResult{HTTPCode: 200, BusinessCode: 0, Data: "ran on a"}, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	select {
+	case who := <-executed:
+		if who != "b" {
+			t.Fatalf("Expected task to run on b, ran on %s", who)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Task was not consumed by the other instance")
+	}
+
+	a.Resume()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	winner, err := batch.WaitFirstSuccess(ctx)
+	if err != nil {
+		t.Fatalf("Submitting batch never received the remote result: %v", err)
+	}
+	if winner.Data != "ran on b" {
+		t.Errorf("Expected remote result data, got %v", winner.Data)
+	}
+	aq.mu.Lock()
+	pending := len(aq.local)
+	aq.mu.Unlock()
+	if pending != 0 {
+		t.Errorf("Expected no local entries after the result returned, got %d", pending)
+	}
+	if n := server.len("tasks:processing:b"); n != 0 {
+		t.Errorf("Expected b to ack the task, %d left in processing", n)
+	}
+}
+
+func TestQueue_ResultTimeout(t *testing.T) {
+	server := newFakeRedis(t)
+	executed := make(chan string, 4)
+	a, aq := newInstance(t, server.ln.Addr().String(), "a", executed, func(cfg *Config) {
+		cfg.ResultTimeout = 30 * time.Millisecond
+	})
+	a.Pause()
+
+	batch, err := a.SubmitBatch([]*fastscheduler.Task{{
+		ID: "job",
+		Execute: func(ctx context.Context) (fastscheduler.TaskResult, error) {
+			return fastscheduler.TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		aq.mu.Lock()
+		expired := len(aq.local) == 0
+		aq.mu.Unlock()
+		if expired {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Local entry was never expired")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	a.Resume()
+	for result := range batch.Iter() {
+		if !errors.Is(result.Err, ErrResultTimeout) {
+			t.Errorf("Expected ErrResultTimeout, got %v", result.Err)
+		}
+	}
+}
+
+func TestQueue_IdleQueueDoesNotHitRedisOnDispatch(t *testing.T) {
+	server := newFakeRedis(t)
+	q, err := Open(testConfig(server.ln.Addr().String(), nil))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer q.Close()
+	// 调度goroutine每次都先检查 Interactive 通道
+	scheduler := fastscheduler.NewScheduler(2, 100, fastscheduler.WithQueue(func(lane fastscheduler.Lane, capacity int) fastscheduler.Queue {
+		if lane == fastscheduler.LaneInteractive {
+			return q
+		}
+		return fastscheduler.NewFIFOQueue(capacity)
+	}))
+	defer scheduler.Stop()
+
+	var tasks []*fastscheduler.Task
+	for i := 0; i < 50; i++ {
+		tasks = append(tasks, &fastscheduler.Task{
+			ID: fmt.Sprint(i),
+			Execute: func(ctx context.Context) (fastscheduler.TaskResult, error) {
+				return fastscheduler.TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+			},
+		})
+	}
+	batch, err := scheduler.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	batch.Wait()
+	if n := server.called("RPOPLPUSH") + server.called("LLEN"); n > 5 {
+		t.Errorf("Expected the empty Redis queue to be skipped while dispatching, got %d round trips", n)
+	}
+}
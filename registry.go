@@ -0,0 +1,140 @@
+package fastscheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TaskHandler 已注册任务类型的处理函数，payload 为 SerializableTask.Payload
+type TaskHandler func(ctx context.Context, payload []byte) (TaskResult, error)
+
+// registry 任务类型名到处理函数的全局注册表
+var registry = struct {
+	sync.RWMutex
+	handlers map[string]TaskHandler
+}{handlers: make(map[string]TaskHandler)}
+
+// RegisterTaskType 注册任务类型，通常在 init 中调用
+// 持久化队列和远程worker只传递类型名和参数，由各进程按类型名找到处理函数执行
+// 重复注册同一类型名或 handler 为nil时 panic
+func RegisterTaskType(name string, handler TaskHandler) {
+	registry.Lock()
+	defer registry.Unlock()
+	if handler == nil {
+		panic("fastscheduler: RegisterTaskType handler is nil")
+	}
+	if _, dup := registry.handlers[name]; dup {
+		panic("fastscheduler: RegisterTaskType called twice for " + name)
+	}
+	registry.handlers[name] = handler
+}
+
+// lookupTaskType 返回已注册的处理函数
+func lookupTaskType(name string) (TaskHandler, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	handler, ok := registry.handlers[name]
+	return handler, ok
+}
+
+// SerializableTask 任务的可序列化形式：已注册的类型名和参数
+type SerializableTask struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Payload []byte `json:"payload,omitempty"`
+}
+
+// Task 创建执行 Type 对应处理函数的任务，类型未注册时返回 ErrUnknownTaskType
+// 返回的任务可以设置 Key、Retry 等字段后提交，也可以通过 MarshalTask 序列化
+func (st SerializableTask) Task() (*Task, error) {
+	handler, ok := lookupTaskType(st.Type)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTaskType, st.Type)
+	}
+	serial := st
+	return &Task{
+		ID: st.ID,
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return handler(ctx, serial.Payload)
+		},
+		serial: &serial,
+	}, nil
+}
+
+// Serializable 返回任务的可序列化形式，任务不是由 SerializableTask 创建时返回false
+func (t *Task) Serializable() (SerializableTask, bool) {
+	if t.serial == nil {
+		return SerializableTask{}, false
+	}
+	return *t.serial, true
+}
+
+// wireTask MarshalTask 的编码格式，包含类型、参数和可以跨进程保留的任务字段
+type wireTask struct {
+	SerializableTask
+	Cost           float64       `json:"cost,omitempty"`
+	Tenant         string        `json:"tenant,omitempty"`
+	Tags           []string      `json:"tags,omitempty"`
+	Retry          *RetryPolicy  `json:"retry,omitempty"`
+	IdempotencyKey string        `json:"idempotency_key,omitempty"`
+	Key            string        `json:"key,omitempty"`
+	Bulkhead       string        `json:"bulkhead,omitempty"`
+	Weight         int           `json:"weight,omitempty"`
+	Class          TaskClass     `json:"class,omitempty"`
+	Hints          Hints         `json:"hints"`
+	QueueTTL       time.Duration `json:"queue_ttl,omitempty"`
+	Deadline       time.Duration `json:"deadline,omitempty"`
+}
+
+// MarshalTask 将由 SerializableTask 创建的任务编码为JSON，其他任务返回 ErrNotSerializable
+// ID 取任务当前的ID；ResultChan、Hedge 和 DependsOn 不会被编码
+func MarshalTask(t *Task) ([]byte, error) {
+	if t.serial == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotSerializable, t.ID)
+	}
+	st := *t.serial
+	st.ID = t.ID
+	return json.Marshal(wireTask{
+		SerializableTask: st,
+		Cost:             t.Cost,
+		Tenant:           t.Tenant,
+		Tags:             t.Tags,
+		Retry:            t.Retry,
+		IdempotencyKey:   t.IdempotencyKey,
+		Key:              t.Key,
+		Bulkhead:         t.Bulkhead,
+		Weight:           t.Weight,
+		Class:            t.Class,
+		Hints:            t.Hints,
+		QueueTTL:         t.QueueTTL,
+		Deadline:         t.Deadline,
+	})
+}
+
+// UnmarshalTask 解码 MarshalTask 的结果并重建任务，类型未注册时返回 ErrUnknownTaskType
+func UnmarshalTask(data []byte) (*Task, error) {
+	var w wireTask
+	if err := json.Unmarshal(data, &w); err != nil {
+		return nil, err
+	}
+	t, err := w.SerializableTask.Task()
+	if err != nil {
+		return nil, err
+	}
+	t.Cost = w.Cost
+	t.Tenant = w.Tenant
+	t.Tags = w.Tags
+	t.Retry = w.Retry
+	t.IdempotencyKey = w.IdempotencyKey
+	t.Key = w.Key
+	t.Bulkhead = w.Bulkhead
+	t.Weight = w.Weight
+	t.Class = w.Class
+	t.Hints = w.Hints
+	t.QueueTTL = w.QueueTTL
+	t.Deadline = w.Deadline
+	return t, nil
+}
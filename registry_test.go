@@ -0,0 +1,88 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func init() {
+	RegisterTaskType("test.echo", func(ctx context.Context, payload []byte) (TaskResult, error) {
+		return TaskResult{HTTPCode: 200, BusinessCode: 0, Data: string(payload)}, nil
+	})
+}
+
+func TestSerializableTask(t *testing.T) {
+	scheduler := NewScheduler(2, 10)
+	defer scheduler.Stop()
+
+	task, err := SerializableTask{ID: "echo-1", Type: "test.echo", Payload: []byte("hello")}.Task()
+	if err != nil {
+		t.Fatalf("Task failed: %v", err)
+	}
+	future, err := scheduler.Submit(task)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if result := future.Result(); result.Data != "hello" {
+		t.Errorf("Expected hello, got %v", result.Data)
+	}
+
+	if _, err := (SerializableTask{Type: "test.missing"}).Task(); !errors.Is(err, ErrUnknownTaskType) {
+		t.Errorf("Expected ErrUnknownTaskType, got %v", err)
+	}
+}
+
+func TestMarshalTask(t *testing.T) {
+	task, err := SerializableTask{ID: "echo-1", Type: "test.echo", Payload: []byte("hello")}.Task()
+	if err != nil {
+		t.Fatalf("Task failed: %v", err)
+	}
+	task.Key = "user-1"
+	task.Retry = &RetryPolicy{MaxAttempts: 2, Delay: time.Second}
+	task.Hints.Region = "eu"
+
+	data, err := MarshalTask(task)
+	if err != nil {
+		t.Fatalf("MarshalTask failed: %v", err)
+	}
+	for _, field := range []string{`"retry":{"max_attempts":2,"delay":1000000000}`, `"hints":{"region":"eu"}`} {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("Expected snake_case field %s, got %s", field, data)
+		}
+	}
+	decoded, err := UnmarshalTask(data)
+	if err != nil {
+		t.Fatalf("UnmarshalTask failed: %v", err)
+	}
+	if decoded.ID != "echo-1" || decoded.Key != "user-1" || decoded.Hints.Region != "eu" ||
+		decoded.Retry == nil || decoded.Retry.Delay != time.Second {
+		t.Errorf("Task fields not preserved: %+v", decoded)
+	}
+	st, ok := decoded.Serializable()
+	if !ok || st.Type != "test.echo" || string(st.Payload) != "hello" {
+		t.Errorf("Unexpected serializable form %+v", st)
+	}
+	result, err := decoded.Execute(context.Background())
+	if err != nil || result.Data != "hello" {
+		t.Errorf("Decoded task returned %v, %v", result.Data, err)
+	}
+
+	closure := &Task{ID: "closure", Execute: task.Execute}
+	if _, err := MarshalTask(closure); !errors.Is(err, ErrNotSerializable) {
+		t.Errorf("Expected ErrNotSerializable, got %v", err)
+	}
+}
+
+func TestRegisterTaskType_Duplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic on duplicate registration")
+		}
+	}()
+	RegisterTaskType("test.echo", func(ctx context.Context, payload []byte) (TaskResult, error) {
+		return TaskResult{}, nil
+	})
+}
@@ -0,0 +1,87 @@
+package fastscheduler
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// WithResultBuffer 为调用方提供的 ResultChan 增加容量为 size 的缓冲
+// 每个通道由独立的goroutine按完成顺序转发结果，消费缓慢时worker不再阻塞在发送上。
+// 缓冲已满时按 policy 处理：OverflowBlock 等待空位，OverflowReject 丢弃新结果，
+// OverflowDropOldest 丢弃最早的未转发结果。丢弃只影响 ResultChan，批次结果不受影响
+func WithResultBuffer(size int, policy OverflowPolicy) Option {
+	return func(s *Scheduler) {
+		if size <= 0 {
+			return
+		}
+		s.resultBuffers = &resultBuffers{
+			size:    size,
+			policy:  policy,
+			bufs:    make(map[chan<- TaskResult]*resultBuffer),
+			dropped: &s.droppedResults,
+		}
+	}
+}
+
+// resultBuffers 按调用方通道管理结果缓冲
+type resultBuffers struct {
+	mu     sync.Mutex
+	size   int
+	policy OverflowPolicy
+	bufs   map[chan<- TaskResult]*resultBuffer
+	// dropped 调度器的丢弃结果计数
+	dropped *atomic.Int64
+}
+
+// resultBuffer 单个通道的待转发结果
+type resultBuffer struct {
+	queue   []TaskResult
+	notFull *sync.Cond
+}
+
+// push 将结果放入 ch 的缓冲，缓冲不存在时启动转发goroutine
+func (rb *resultBuffers) push(ch chan<- TaskResult, result TaskResult) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	b := rb.bufs[ch]
+	if b == nil {
+		b = &resultBuffer{notFull: sync.NewCond(&rb.mu)}
+		rb.bufs[ch] = b
+		go rb.forward(ch, b)
+	}
+	for len(b.queue) >= rb.size {
+		switch rb.policy {
+		case OverflowReject:
+			rb.dropped.Add(1)
+			return
+		case OverflowDropOldest:
+			b.queue = b.queue[1:]
+			rb.dropped.Add(1)
+		default:
+			b.notFull.Wait()
+		}
+	}
+	b.queue = append(b.queue, result)
+}
+
+// forward 依次转发缓冲中的结果，缓冲清空后退出
+func (rb *resultBuffers) forward(ch chan<- TaskResult, b *resultBuffer) {
+	for {
+		rb.mu.Lock()
+		if len(b.queue) == 0 {
+			delete(rb.bufs, ch)
+			rb.mu.Unlock()
+			return
+		}
+		result := b.queue[0]
+		b.queue[0] = TaskResult{}
+		b.queue = b.queue[1:]
+		b.notFull.Broadcast()
+		rb.mu.Unlock()
+
+		if !deliver(ch, result) {
+			rb.dropped.Add(1)
+		}
+	}
+}
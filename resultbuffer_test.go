@@ -0,0 +1,55 @@
+package fastscheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestScheduler_ResultBuffer(t *testing.T) {
+	scheduler := NewScheduler(5, 10, WithResultBuffer(2, OverflowDropOldest))
+	defer scheduler.Stop()
+
+	// 无缓冲且无人接收的通道，不应阻塞worker
+	resultChan := make(chan TaskResult)
+	var tasks []*Task
+	for _, id := range []string{"t1", "t2", "t3", "t4", "t5"} {
+		tasks = append(tasks, &Task{
+			ID:         id,
+			ResultChan: resultChan,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+			},
+		})
+	}
+	batch, err := scheduler.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		batch.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Slow ResultChan consumer blocked the batch")
+	}
+
+	// 一个结果正在转发，缓冲中最多保留2个
+	received := 0
+	for {
+		select {
+		case <-resultChan:
+			received++
+			continue
+		case <-time.After(50 * time.Millisecond):
+		}
+		break
+	}
+	if received == 0 || received > 3 {
+		t.Errorf("Expected 1-3 buffered results, got %d", received)
+	}
+}
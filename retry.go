@@ -0,0 +1,136 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// defaultRetryAttempts RetryPolicy.MaxAttempts 未设置时的最多执行次数
+const defaultRetryAttempts = 3
+
+// RetryPolicy 任务重试配置
+// 任务失败且批次未被取消时，等待 Delay 后重新入队执行，不占用等待期间的worker
+type RetryPolicy struct {
+	// MaxAttempts 最多执行次数(包含首次)，默认3
+	MaxAttempts int `json:"max_attempts,omitempty"`
+
+	// Delay 每次重试前的等待时间
+	Delay time.Duration `json:"delay,omitempty"`
+
+	// Backoff 按重试次数计算等待时间，设置后代替 Delay；不会被 MarshalTask 序列化
+	Backoff Backoff `json:"-"`
+
+	// Retryable 判断失败是否值得重试，nil 时使用 DefaultRetryable；不会被 MarshalTask 序列化
+	Retryable func(result TaskResult, err error) bool `json:"-"`
+}
+
+// permanentError 不可重试的错误
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent 将 err 标记为不可重试，重试时不再执行；errors.Is/As 仍可匹配原始错误
+// err 为 nil 时返回 nil
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Retryable 判断错误是否可以重试：Permanent 标记的错误和取消不可重试，其余(包括超时和网络错误)可以重试
+func Retryable(err error) bool {
+	var p *permanentError
+	if errors.As(err, &p) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	return true
+}
+
+// DefaultRetryable 默认的重试判断：有错误时按 Retryable 判断；
+// 没有错误时 4xx 状态码视为请求本身的问题不重试(408 和 429 除外)，其余失败重试
+func DefaultRetryable(result TaskResult, err error) bool {
+	if err != nil {
+		return Retryable(err)
+	}
+	code := result.HTTPCode
+	if code >= 400 && code < 500 {
+		return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// retryable 按策略判断失败是否值得重试
+func (p *RetryPolicy) retryable(result TaskResult, err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(result, err)
+	}
+	return DefaultRetryable(result, err)
+}
+
+// maxAttempts 返回最多执行次数
+func (p *RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return defaultRetryAttempts
+	}
+	return p.MaxAttempts
+}
+
+// delay 返回第 n 次重试前的等待时间
+func (p *RetryPolicy) delay(n int) time.Duration {
+	if p.Backoff != nil {
+		return p.Backoff.Next(n)
+	}
+	return p.Delay
+}
+
+// retryDelay 返回任务下次重试前的等待时间
+// 结果指定了 RetryAfter 或HTTP响应带有 Retry-After / X-RateLimit-Reset 时按服务端要求的时间等待
+func (s *Scheduler) retryDelay(task *Task, result TaskResult) time.Duration {
+	if result.RetryAfter > 0 {
+		return result.RetryAfter
+	}
+	if resp, ok := result.Data.(*HTTPResponse); ok {
+		if d, ok := resp.RetryAfter(s.now()); ok {
+			return d
+		}
+	}
+	// attempts 已包含刚失败的一次，第 n 次执行失败后进行第 n 次重试
+	return task.Retry.delay(task.attempts)
+}
+
+// shouldRetry 判断失败的任务是否还需要重试
+func (s *Scheduler) shouldRetry(task *Task, result TaskResult, err error) bool {
+	if task.Retry == nil || (err == nil && isSuccess(result)) {
+		return false
+	}
+	if task.runCtx().Err() != nil || task.group.taskCancelled(task) || task.group.pendingStopped() {
+		return false
+	}
+	return task.attempts < task.Retry.maxAttempts() && task.Retry.retryable(result, err)
+}
+
+// retry 延迟后重新入队，任务仍持有并发名额和 Key；主机名额在等待期间释放，重新入队时再获取
+func (s *Scheduler) retry(task *Task, result TaskResult, err error) {
+	task.reason = "retry after failure"
+	s.releaseHost(task)
+	delay := s.retryDelay(task, result)
+	if s.logger != nil {
+		attrs := []slog.Attr{slog.Int("http_code", result.HTTPCode), slog.Duration("delay", delay)}
+		if err != nil {
+			attrs = append(attrs, slog.Any("error", err))
+		}
+		s.logTask(slog.LevelWarn, "task retried", task, attrs...)
+	}
+	if s.synchronous() {
+		s.enqueueAfter(task, delay)
+		return
+	}
+	go s.enqueueAfter(task, delay)
+}
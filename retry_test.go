@@ -0,0 +1,101 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTask_Retry(t *testing.T) {
+	scheduler := NewScheduler(2, 10)
+	defer scheduler.Stop()
+
+	var calls atomic.Int32
+	future, err := scheduler.Submit(&Task{
+		ID:    "flaky",
+		Retry: &RetryPolicy{MaxAttempts: 3, Delay: 10 * time.Millisecond},
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			if calls.Add(1) < 3 {
+				return TaskResult{HTTPCode: 503, BusinessCode: 1}, nil
+			}
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	result := future.Result()
+	if result.HTTPCode != 200 || result.Attempts != 3 {
+		t.Errorf("Expected success on third attempt, got %+v", result)
+	}
+}
+
+func TestTask_RetryExhausted(t *testing.T) {
+	scheduler := NewScheduler(2, 10)
+	defer scheduler.Stop()
+
+	var calls atomic.Int32
+	future, err := scheduler.Submit(&Task{
+		ID:    "broken",
+		Retry: &RetryPolicy{MaxAttempts: 2},
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			calls.Add(1)
+			return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	if result := future.Result(); result.Attempts != 2 || calls.Load() != 2 {
+		t.Errorf("Expected 2 attempts, got %d (calls %d)", result.Attempts, calls.Load())
+	}
+}
+
+func TestTask_RetryClassification(t *testing.T) {
+	scheduler := NewScheduler(2, 10)
+	defer scheduler.Stop()
+
+	errInvalid := errors.New("invalid argument")
+	tests := []struct {
+		name      string
+		result    TaskResult
+		err       error
+		retryable func(TaskResult, error) bool
+		attempts  int
+	}{
+		{"Permanent", TaskResult{}, Permanent(errInvalid), nil, 1},
+		{"NotFound", TaskResult{HTTPCode: 404}, nil, nil, 1},
+		{"TooManyRequests", TaskResult{HTTPCode: 429}, nil, nil, 3},
+		{"NetworkTimeout", TaskResult{}, &net.DNSError{Err: "timeout", IsTimeout: true}, nil, 3},
+		{"Custom", TaskResult{HTTPCode: 503}, nil, func(TaskResult, error) bool { return false }, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			future, err := scheduler.Submit(&Task{
+				ID:    tt.name,
+				Retry: &RetryPolicy{MaxAttempts: 3, Retryable: tt.retryable},
+				Execute: func(ctx context.Context) (TaskResult, error) {
+					return tt.result, tt.err
+				},
+			})
+			if err != nil {
+				t.Fatalf("Submit failed: %v", err)
+			}
+			result := future.Result()
+			if result.Attempts != tt.attempts {
+				t.Errorf("Attempts = %d, want %d", result.Attempts, tt.attempts)
+			}
+			if tt.err != nil && !errors.Is(result.Err, tt.err) {
+				t.Errorf("Expected error %v, got %v", tt.err, result.Err)
+			}
+		})
+	}
+	if !errors.Is(Permanent(errInvalid), errInvalid) {
+		t.Error("Expected Permanent to wrap the original error")
+	}
+}
@@ -0,0 +1,56 @@
+package fastscheduler
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"sync/atomic"
+)
+
+// WithRetryBudget 限制批次的重试总次数为任务数的 ratio 倍(向上取整)，
+// 例如 0.2 表示 100 个任务最多重试 20 次，避免后端抖动时重试把请求量放大数倍。
+// 预算用完后本应重试的任务不再重试，以包装了 ErrRetryBudgetExhausted 和原始错误的错误完成。
+// 开放批次按当前已加入的任务数计算
+func WithRetryBudget(ratio float64) BatchOption {
+	return func(g *taskGroup) {
+		g.retryBudget = &retryBudget{ratio: max(ratio, 0)}
+	}
+}
+
+// retryBudget 批次的重试预算
+type retryBudget struct {
+	ratio float64
+	spent atomic.Int64
+}
+
+// spendRetry 从批次的重试预算中扣除一次重试，预算已用完时返回 false
+func (g *taskGroup) spendRetry() bool {
+	rb := g.retryBudget
+	if rb == nil {
+		return true
+	}
+	limit := int64(math.Ceil(rb.ratio * float64(g.total.Load())))
+	for {
+		n := rb.spent.Load()
+		if n >= limit {
+			return false
+		}
+		if rb.spent.CompareAndSwap(n, n+1) {
+			return true
+		}
+	}
+}
+
+// retryBudgetError 将预算用完而未能重试的失败包装为 ErrRetryBudgetExhausted
+func retryBudgetError(err error) error {
+	if err == nil {
+		return ErrRetryBudgetExhausted
+	}
+	return fmt.Errorf("%w: %w", ErrRetryBudgetExhausted, err)
+}
+
+// exhaustRetry 重试预算用完时记录日志并返回任务的最终错误
+func (s *Scheduler) exhaustRetry(task *Task, err error) error {
+	s.logTask(slog.LevelWarn, "retry budget exhausted", task, slog.Int("attempts", task.attempts))
+	return retryBudgetError(err)
+}
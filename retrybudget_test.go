@@ -0,0 +1,83 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBatch_RetryBudget(t *testing.T) {
+	scheduler := NewScheduler(2, 20)
+	defer scheduler.Stop()
+
+	var runs atomic.Int32
+	var tasks []*Task
+	for i := 0; i < 10; i++ {
+		tasks = append(tasks, &Task{
+			ID:    fmt.Sprintf("flaky-%d", i),
+			Retry: &RetryPolicy{MaxAttempts: 5},
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				runs.Add(1)
+				return TaskResult{HTTPCode: 503, BusinessCode: 1}, nil
+			},
+		})
+	}
+
+	// 10 个任务最多重试 2 次
+	batch, err := scheduler.SubmitBatch(tasks, WithRetryBudget(0.2))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	exhausted := 0
+	for r := range batch.Iter() {
+		if errors.Is(r.Err, ErrRetryBudgetExhausted) {
+			exhausted++
+			if r.HTTPCode != 503 {
+				t.Errorf("Expected original status code to be kept, got %d", r.HTTPCode)
+			}
+		}
+	}
+	if exhausted == 0 {
+		t.Error("Expected some tasks to finish with ErrRetryBudgetExhausted")
+	}
+	if runs.Load() != 12 {
+		t.Errorf("Expected 12 runs with a retry budget of 2, got %d", runs.Load())
+	}
+	if got := batch.Outcome(); got != OutcomeRetryBudgetExhausted {
+		t.Errorf("Expected retry budget exhausted outcome, got %v", got)
+	}
+}
+
+func TestBatch_RetryBudgetKeepsError(t *testing.T) {
+	scheduler := NewScheduler(1, 10)
+	defer scheduler.Stop()
+
+	errBackend := errors.New("backend unavailable")
+	task := &Task{
+		ID:    "once",
+		Retry: &RetryPolicy{MaxAttempts: 3},
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{}, errBackend
+		},
+	}
+
+	batch, err := scheduler.SubmitBatch([]*Task{task}, WithRetryBudget(0))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	var results []TaskResult
+	for r := range batch.Iter() {
+		results = append(results, r)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if !errors.Is(results[0].Err, ErrRetryBudgetExhausted) || !errors.Is(results[0].Err, errBackend) {
+		t.Errorf("Expected both budget and original error, got %v", results[0].Err)
+	}
+	if results[0].Attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", results[0].Attempts)
+	}
+}
@@ -0,0 +1,38 @@
+package fastscheduler
+
+import "sync"
+
+// ringBuffer 固定容量的环形缓冲，写满后覆盖最早的记录
+type ringBuffer[T any] struct {
+	mu   sync.Mutex
+	buf  []T
+	next int
+	full bool
+}
+
+func newRingBuffer[T any](size int) *ringBuffer[T] {
+	return &ringBuffer[T]{buf: make([]T, size)}
+}
+
+// add 追加一条记录
+func (r *ringBuffer[T]) add(v T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.buf[r.next] = v
+	r.next = (r.next + 1) % len(r.buf)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// snapshot 按写入顺序返回记录的副本
+func (r *ringBuffer[T]) snapshot() []T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]T(nil), r.buf[:r.next]...)
+	}
+	out := make([]T, 0, len(r.buf))
+	out = append(out, r.buf[r.next:]...)
+	return append(out, r.buf[:r.next]...)
+}
@@ -0,0 +1,79 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RPCOption 配置 RaceRPC
+type RPCOption func(*rpcRace)
+
+// rpcRace RaceRPC 的配置
+type rpcRace struct {
+	// metadata 在每次尝试发起调用前修改其 ctx
+	metadata func(ctx context.Context, attempt int) context.Context
+	stagger  time.Duration
+}
+
+// WithRPCMetadata 在每次尝试发起调用前用 fn 修改其 ctx，attempt 为连接在 conns 中的下标
+// 用于注入按尝试区分的元数据，例如 gRPC 的 metadata.AppendToOutgoingContext
+func WithRPCMetadata(fn func(ctx context.Context, attempt int) context.Context) RPCOption {
+	return func(r *rpcRace) {
+		r.metadata = fn
+	}
+}
+
+// WithRPCStagger 第 i 个连接在 i*d 后才发起调用，排在前面的调用先成功时后面的不再发起
+func WithRPCStagger(d time.Duration) RPCOption {
+	return func(r *rpcRace) {
+		r.stagger = d
+	}
+}
+
+// RaceRPC 在 s 上对每个连接并发发起同一个一元调用，返回第一个成功(err 为nil)的响应
+// C 通常为 *grpc.ClientConn 或生成的客户端，call 必须使用传入的 ctx 发起调用：
+// 获胜后批次的 ctx 被取消，落败的调用随之被取消。全部失败时返回各次调用错误的合并；
+// 获胜结果的 Data 被结果处理函数替换为其他类型时返回 *ResultTypeError
+func RaceRPC[C, R any](ctx context.Context, s *Scheduler, conns []C, call func(ctx context.Context, conn C) (R, error), opts ...RPCOption) (R, error) {
+	var zero R
+	race := &rpcRace{}
+	for _, opt := range opts {
+		opt(race)
+	}
+
+	tasks := make([]*Task, len(conns))
+	for i, conn := range conns {
+		tasks[i] = &Task{
+			ID: fmt.Sprintf("rpc-%d", i),
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				if race.metadata != nil {
+					ctx = race.metadata(ctx, i)
+				}
+				resp, err := call(ctx, conn)
+				if err != nil {
+					return TaskResult{}, err
+				}
+				return TaskResult{HTTPCode: 200, BusinessCode: 0, Data: resp}, nil
+			},
+		}
+	}
+
+	batch, err := s.SubmitBatch(tasks, Stagger(race.stagger), withParentContext(ctx))
+	if err != nil {
+		return zero, err
+	}
+
+	var errs []error
+	for result := range batch.ResultsChan() {
+		if isSuccess(result) {
+			return resultData[R](result)
+		}
+		errs = append(errs, result.Err)
+	}
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	return zero, errors.Join(append([]error{ErrAllFailed}, errs...)...)
+}
@@ -0,0 +1,95 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeConn 模拟一个响应时间固定的RPC连接
+type fakeConn struct {
+	name  string
+	delay time.Duration
+	err   error
+}
+
+type attemptKey struct{}
+
+func TestRaceRPC(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	cancelled := make(chan string, 3)
+	conns := []*fakeConn{
+		{name: "slow", delay: time.Second},
+		{name: "fast", delay: 20 * time.Millisecond},
+		{name: "broken", err: errors.New("unavailable")},
+	}
+	call := func(ctx context.Context, conn *fakeConn) (string, error) {
+		select {
+		case <-time.After(conn.delay):
+		case <-ctx.Done():
+			cancelled <- conn.name
+			return "", ctx.Err()
+		}
+		if conn.err != nil {
+			return "", conn.err
+		}
+		return conn.name + "#" + string(rune('0'+ctx.Value(attemptKey{}).(int))), nil
+	}
+
+	resp, err := RaceRPC(context.Background(), scheduler, conns, call,
+		WithRPCMetadata(func(ctx context.Context, attempt int) context.Context {
+			return context.WithValue(ctx, attemptKey{}, attempt)
+		}))
+	if err != nil {
+		t.Fatalf("RaceRPC failed: %v", err)
+	}
+	if resp != "fast#1" {
+		t.Errorf("Expected fast#1, got %s", resp)
+	}
+
+	select {
+	case name := <-cancelled:
+		if name != "slow" {
+			t.Errorf("Expected slow call to be cancelled, got %s", name)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Error("Losing call was not cancelled")
+	}
+}
+
+func TestRaceRPC_AllFailed(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	unavailable := errors.New("unavailable")
+	conns := []*fakeConn{{name: "a", err: unavailable}, {name: "b", err: unavailable}}
+	_, err := RaceRPC(context.Background(), scheduler, conns, func(ctx context.Context, conn *fakeConn) (int, error) {
+		return 0, conn.err
+	}, WithRPCStagger(time.Millisecond))
+	if !errors.Is(err, ErrAllFailed) || !errors.Is(err, unavailable) {
+		t.Errorf("Expected ErrAllFailed wrapping call errors, got %v", err)
+	}
+}
+
+func TestRaceRPC_UnexpectedResultType(t *testing.T) {
+	scheduler := NewScheduler(5, 10, WithResultTransformers(func(r TaskResult) TaskResult {
+		r.Data = "rewritten"
+		return r
+	}))
+	defer scheduler.Stop()
+
+	conns := []*fakeConn{{name: "a"}}
+	_, err := RaceRPC(context.Background(), scheduler, conns, func(ctx context.Context, conn *fakeConn) (int, error) {
+		return 1, nil
+	})
+	var typeErr *ResultTypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("Expected *ResultTypeError, got %v", err)
+	}
+	if typeErr.Data != "rewritten" {
+		t.Errorf("Expected the rewritten data in the error, got %v", typeErr.Data)
+	}
+}
@@ -0,0 +1,123 @@
+package fastscheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Scope 是调度器的受限视图，与父调度器共享worker池，
+// 但对通过它提交的任务额外限制并发数和速率
+type Scope struct {
+	parent  *Scheduler
+	sem     chan struct{}
+	limiter *rateLimiter
+}
+
+// WithLimits 返回共享当前worker池的受限视图
+// maxConcurrent: 通过该视图提交的任务最大并发数，<=0 表示不限制
+// rate: 每秒最多开始的任务数，<=0 表示不限制
+// 任务在满足限制前停留在视图中，不会占用父调度器的队列和worker
+func (s *Scheduler) WithLimits(maxConcurrent int, rate float64) *Scope {
+	scope := &Scope{parent: s}
+	if maxConcurrent > 0 {
+		scope.sem = make(chan struct{}, maxConcurrent)
+	}
+	if rate > 0 {
+		scope.limiter = newRateLimiter(rate, s.clock)
+	}
+	return scope
+}
+
+// SubmitBatch 通过受限视图提交一批任务
+// 调度器已停止时返回 ErrSchedulerStopped
+func (sc *Scope) SubmitBatch(tasks []*Task, opts ...BatchOption) (*Batch, error) {
+	if sc.parent.stopped() {
+		return nil, ErrSchedulerStopped
+	}
+	batch, queued, err := sc.parent.prepareBatch(LaneNormal, tasks, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(queued) == 0 {
+		return batch, nil
+	}
+	batch.group.scope = sc
+
+	go func() {
+		if err := batch.group.waitAfter(); err != nil {
+			sc.parent.skipAll(queued, err)
+			return
+		}
+		for i, t := range queued {
+			if t.blocked {
+				continue
+			}
+			if err := sc.parent.admitTask(t); err != nil {
+				sc.parent.skipTask(t, err)
+				sc.parent.skipAll(queued[i+1:], err)
+				return
+			}
+		}
+	}()
+	return batch, nil
+}
+
+// Submit 通过受限视图提交单个任务
+func (sc *Scope) Submit(task *Task, opts ...BatchOption) (*Future, error) {
+	return newFuture(sc.SubmitBatch([]*Task{task}, opts...))
+}
+
+// admit 等待视图的并发和速率限制，批次已取消时直接放行
+func (sc *Scope) admit(t *Task) {
+	ctx := t.runCtx()
+	if sc.sem != nil {
+		select {
+		case sc.sem <- struct{}{}:
+			t.release = func() { <-sc.sem }
+		case <-ctx.Done():
+			return
+		}
+	}
+	if sc.limiter != nil {
+		_ = sc.limiter.wait(ctx)
+		t.reason = "admitted by scope rate limit"
+	}
+}
+
+// rateLimiter 按固定间隔放行的简单限速器
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+	clock    Clock
+}
+
+// newRateLimiter 创建每秒放行 rate 次的限速器
+func newRateLimiter(rate float64, clock Clock) *rateLimiter {
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / rate), clock: clock}
+}
+
+// wait 阻塞直到下一个放行时间点或 ctx 结束
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := l.clock.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := l.clock.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
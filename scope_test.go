@@ -0,0 +1,71 @@
+package fastscheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScope_MaxConcurrent(t *testing.T) {
+	scheduler := NewScheduler(10, 20)
+	defer scheduler.Stop()
+
+	scope := scheduler.WithLimits(2, 0)
+
+	var running, maxRunning atomic.Int32
+	var tasks []*Task
+	for i := 0; i < 6; i++ {
+		tasks = append(tasks, &Task{
+			ID: "scoped",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				n := running.Add(1)
+				for {
+					m := maxRunning.Load()
+					if n <= m || maxRunning.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				running.Add(-1)
+				return TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+			},
+		})
+	}
+
+	batch, err := scope.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	if maxRunning.Load() != 2 {
+		t.Errorf("Expected at most 2 concurrent scoped tasks, got %d", maxRunning.Load())
+	}
+}
+
+func TestScope_Rate(t *testing.T) {
+	scheduler := NewScheduler(10, 20)
+	defer scheduler.Stop()
+
+	scope := scheduler.WithLimits(0, 50) // 每20ms一个
+	var tasks []*Task
+	for i := 0; i < 5; i++ {
+		tasks = append(tasks, &Task{
+			ID: "rated",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+			},
+		})
+	}
+
+	start := time.Now()
+	batch, err := scope.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("Expected rate limit to spread 5 tasks over ~80ms, took %v", elapsed)
+	}
+}
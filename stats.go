@@ -0,0 +1,169 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// Stats 调度器运行统计快照
+type Stats struct {
+	// Cost 成本统计
+	Cost CostStats
+	// Deadline 完成时限统计
+	Deadline DeadlineStats
+	// Workers worker池使用情况
+	Workers WorkerStats
+	// Lanes 各优先级通道的排队和执行情况，按优先级从高到低排列
+	Lanes []LaneStats
+	// DroppedResults 未能发送到 ResultChan 而被丢弃的结果数，见 WithResultDelivery
+	DroppedResults int64
+	// TaskTimeouts 任务执行超过 Task.Timeout 或 WithDefaultTaskTimeout 的次数
+	TaskTimeouts int64
+	// Timing 已执行任务的排队和执行耗时分布(p50/p95/p99)
+	Timing TimingStats
+	// TimingByKey 按 Task.Key 分组的耗时分布，仅启用 WithLatencyByKey 时非nil
+	TimingByKey map[string]TimingStats
+}
+
+// WorkerStats worker池使用情况
+type WorkerStats struct {
+	// Busy 正在执行任务的worker数
+	Busy int
+	// Size worker池大小
+	Size int
+}
+
+// LaneStats 单个优先级通道的状态
+type LaneStats struct {
+	Lane Lane
+	// Queued 排队中的任务数
+	Queued int
+	// Inflight 正在执行的任务数
+	Inflight int64
+	// Quota 最大并发数，0表示不限制
+	Quota int
+}
+
+// CostStats 任务成本汇总
+type CostStats struct {
+	// Total 全部已执行任务的成本总和
+	Total float64
+	// ByTenant 按租户汇总的成本
+	ByTenant map[string]float64
+	// ByTag 按标签汇总的成本，带多个标签的任务会计入每个标签
+	ByTag map[string]float64
+}
+
+// DeadlineStats 设置了 Deadline 的任务的完成情况
+type DeadlineStats struct {
+	// Tracked 设置了 Deadline 且已完成的任务数，不含因取消而结束的任务
+	Tracked int64
+	// Missed 超过 Deadline 才完成的任务数
+	Missed int64
+	// MissedSucceeded 超过 Deadline 但结果成功的任务数
+	MissedSucceeded int64
+}
+
+// costLedger 累计任务成本
+type costLedger struct {
+	mu       sync.Mutex
+	total    float64
+	byTenant map[string]float64
+	byTag    map[string]float64
+}
+
+// add 记录一次任务成本
+func (l *costLedger) add(tenant string, tags []string, cost float64) {
+	if cost == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.byTenant == nil {
+		l.byTenant = make(map[string]float64)
+		l.byTag = make(map[string]float64)
+	}
+	l.total += cost
+	l.byTenant[tenant] += cost
+	for _, tag := range tags {
+		l.byTag[tag] += cost
+	}
+}
+
+// snapshot 返回成本统计的副本
+func (l *costLedger) snapshot() CostStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := CostStats{
+		Total:    l.total,
+		ByTenant: make(map[string]float64, len(l.byTenant)),
+		ByTag:    make(map[string]float64, len(l.byTag)),
+	}
+	for k, v := range l.byTenant {
+		stats.ByTenant[k] = v
+	}
+	for k, v := range l.byTag {
+		stats.ByTag[k] = v
+	}
+	return stats
+}
+
+// deadlineCounter 累计完成时限统计
+type deadlineCounter struct {
+	tracked         atomic.Int64
+	missed          atomic.Int64
+	missedSucceeded atomic.Int64
+}
+
+// record 记录一个设置了 Deadline 的任务结果
+func (c *deadlineCounter) record(result TaskResult) {
+	if errors.Is(result.Err, context.Canceled) || errors.Is(result.Err, ErrBudgetExceeded) {
+		// 被取消的任务(例如同组任务已成功)不反映任务本身的耗时
+		return
+	}
+	c.tracked.Add(1)
+	if !result.DeadlineMissed {
+		return
+	}
+	c.missed.Add(1)
+	if isSuccess(result) {
+		c.missedSucceeded.Add(1)
+	}
+}
+
+// snapshot 返回完成时限统计的副本
+func (c *deadlineCounter) snapshot() DeadlineStats {
+	return DeadlineStats{
+		Tracked:         c.tracked.Load(),
+		Missed:          c.missed.Load(),
+		MissedSucceeded: c.missedSucceeded.Load(),
+	}
+}
+
+// Stats 返回调度器当前的统计快照
+func (s *Scheduler) Stats() Stats {
+	_, size := s.workerPool.usage()
+	stats := Stats{
+		Cost:           s.costs.snapshot(),
+		Deadline:       s.deadlines.snapshot(),
+		Workers:        WorkerStats{Size: size},
+		DroppedResults: s.droppedResults.Load(),
+		TaskTimeouts:   s.taskTimeouts.Load(),
+	}
+	stats.Timing, stats.TimingByKey = s.timings.snapshot()
+	for _, lane := range laneOrder {
+		inflight := s.laneInflight[lane].Load()
+		stats.Workers.Busy += int(inflight)
+		stats.Lanes = append(stats.Lanes, LaneStats{
+			Lane:     lane,
+			Queued:   s.queues[lane].Len(),
+			Inflight: inflight,
+			Quota:    s.laneQuota[lane],
+		})
+	}
+	return stats
+}
@@ -0,0 +1,109 @@
+package fastscheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestScheduler_CostAccounting(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	tasks := []*Task{
+		{
+			ID:     "fixed-cost",
+			Cost:   2,
+			Tenant: "team-a",
+			Tags:   []string{"search"},
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+			},
+		},
+		{
+			ID:     "reported-cost",
+			Cost:   1,
+			Tenant: "team-b",
+			Tags:   []string{"search", "llm"},
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				// 任务上报的实际成本覆盖预估成本
+				return TaskResult{HTTPCode: 200, BusinessCode: 1, Cost: 5}, nil
+			},
+		},
+	}
+
+	batch, err := scheduler.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	if batch.Cost() != 7 {
+		t.Errorf("Expected batch cost 7, got %v", batch.Cost())
+	}
+
+	cost := scheduler.Stats().Cost
+	if cost.Total != 7 {
+		t.Errorf("Expected total cost 7, got %v", cost.Total)
+	}
+	if cost.ByTenant["team-a"] != 2 || cost.ByTenant["team-b"] != 5 {
+		t.Errorf("Unexpected per-tenant cost: %v", cost.ByTenant)
+	}
+	if cost.ByTag["search"] != 7 || cost.ByTag["llm"] != 5 {
+		t.Errorf("Unexpected per-tag cost: %v", cost.ByTag)
+	}
+}
+
+func TestScheduler_DeadlineMisses(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	resultChan := make(chan TaskResult, 3)
+	tasks := []*Task{
+		{
+			ID:         "on-time",
+			ResultChan: resultChan,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+			},
+		},
+		{
+			ID:         "late-success",
+			ResultChan: resultChan,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				time.Sleep(30 * time.Millisecond)
+				return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+			},
+		},
+		{
+			ID:         "late-failure",
+			ResultChan: resultChan,
+			Deadline:   5 * time.Millisecond,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				time.Sleep(20 * time.Millisecond)
+				return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+			},
+		},
+	}
+
+	// 执行到底，避免成功后取消其他任务影响计数
+	batch, err := scheduler.SubmitBatch(tasks, WithDeadline(10*time.Millisecond), runToCompletion())
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+	close(resultChan)
+
+	missed := make(map[string]bool)
+	for r := range resultChan {
+		missed[r.TaskID] = r.DeadlineMissed
+	}
+	if missed["on-time"] || !missed["late-success"] || !missed["late-failure"] {
+		t.Errorf("Unexpected DeadlineMissed flags: %v", missed)
+	}
+
+	stats := scheduler.Stats().Deadline
+	if stats.Tracked != 3 || stats.Missed != 2 || stats.MissedSucceeded != 1 {
+		t.Errorf("Unexpected deadline stats: %+v", stats)
+	}
+}
@@ -0,0 +1,138 @@
+package fastscheduler
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// noShard 表示不属于任何分片的调度goroutine(单调度goroutine模式或溢出处理)
+const noShard = -1
+
+// WithWorkStealing 使用 n 个调度goroutine代替单个调度goroutine，提高大量小任务时的调度吞吐
+// 每个优先级通道的队列分为 n 个分片，提交的任务轮流放入各分片；每个调度goroutine优先取出自己分片的任务，
+// 自己的分片为空时从其他分片窃取。每个调度goroutine把任务交给自己的空闲worker，worker名额以原子操作获取和归还，
+// 调度路径上没有全局锁。n 小于等于0时使用 GOMAXPROCS。
+// 通道优先级和配额仍然生效，但同一通道内只保证大致的先进先出，较重的任务也不再阻止之后的任务插队；
+// 与 WithQueue、WithSchedulingPolicy、WithTenantPolicy、WithBatchFairness 同时使用时不生效
+func WithWorkStealing(n int) Option {
+	return func(s *Scheduler) {
+		if n <= 0 {
+			n = runtime.GOMAXPROCS(0)
+		}
+		s.stealShards = n
+	}
+}
+
+// stealShard 队列的一个分片，n 为任务数，空分片不加锁即可跳过
+type stealShard struct {
+	mu    sync.Mutex
+	tasks []*Task
+	n     atomic.Int64
+}
+
+// stealingQueue 分片队列，每个分片对应一个调度goroutine
+type stealingQueue struct {
+	shards   []stealShard
+	capacity int64
+	size     atomic.Int64
+	next     atomic.Uint64
+	// notify 各分片有新任务时唤醒对应的调度goroutine
+	notify []chan struct{}
+}
+
+func newStealingQueue(capacity int, notify []chan struct{}) *stealingQueue {
+	return &stealingQueue{
+		shards:   make([]stealShard, len(notify)),
+		capacity: int64(max(capacity, 1)),
+		notify:   notify,
+	}
+}
+
+// Push 轮流放入各分片，总数达到容量时返回 ErrQueueFull
+func (q *stealingQueue) Push(t *Task) error {
+	if q.size.Add(1) > q.capacity {
+		q.size.Add(-1)
+		return ErrQueueFull
+	}
+	i := int(q.next.Add(1) % uint64(len(q.shards)))
+	sh := &q.shards[i]
+	sh.mu.Lock()
+	sh.tasks = append(sh.tasks, t)
+	sh.n.Add(1)
+	sh.mu.Unlock()
+	select {
+	case q.notify[i] <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Pop 从第一个分片开始取出任务
+func (q *stealingQueue) Pop() (*Task, bool) {
+	return q.popFrom(0)
+}
+
+// popFrom 先取出 home 分片的任务，为空时依次从其他分片窃取
+func (q *stealingQueue) popFrom(home int) (*Task, bool) {
+	for k := range q.shards {
+		sh := &q.shards[(home+k)%len(q.shards)]
+		if sh.n.Load() == 0 {
+			continue
+		}
+		sh.mu.Lock()
+		if len(sh.tasks) == 0 {
+			sh.mu.Unlock()
+			continue
+		}
+		t := sh.tasks[0]
+		sh.tasks[0] = nil
+		sh.tasks = sh.tasks[1:]
+		sh.n.Add(-1)
+		sh.mu.Unlock()
+		q.size.Add(-1)
+		return t, true
+	}
+	return nil, false
+}
+
+func (q *stealingQueue) Len() int {
+	return int(q.size.Load())
+}
+
+// newSignals 创建 n 个容量为1的信号通道
+func newSignals(n int) []chan struct{} {
+	chs := make([]chan struct{}, n)
+	for i := range chs {
+		chs[i] = make(chan struct{}, 1)
+	}
+	return chs
+}
+
+// shardSignal 返回分片的新任务信号，不属于分片时返回nil(永不就绪)
+func (s *Scheduler) shardSignal(home int) <-chan struct{} {
+	if home == noShard {
+		return nil
+	}
+	return s.shardQueued[home]
+}
+
+// runShard 工作窃取模式下一个分片的调度循环
+// 先取任务再一次获取它所需的全部worker名额：多个调度goroutine各自预先占用名额会使较重的任务凑不够名额
+func (s *Scheduler) runShard(home int, stop <-chan struct{}) {
+	for {
+		task, ok := s.nextTask(stop, home)
+		if !ok {
+			return
+		}
+		if !s.workerPool.acquireTask(task, stop) {
+			s.unpopTask(task)
+			return
+		}
+		s.wg.Add(1)
+		s.dispatch(task, s.shardHandoff[home], stop)
+		if cooperativeDispatch {
+			runtime.Gosched()
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package fastscheduler
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// peakCounter 记录并发执行数的峰值
+type peakCounter struct {
+	load, peak atomic.Int32
+}
+
+func (c *peakCounter) enter(n int32) {
+	cur := c.load.Add(n)
+	for {
+		p := c.peak.Load()
+		if cur <= p || c.peak.CompareAndSwap(p, cur) {
+			return
+		}
+	}
+}
+
+func (c *peakCounter) exit(n int32) {
+	c.load.Add(-n)
+}
+
+func TestScheduler_WorkStealing(t *testing.T) {
+	scheduler := NewScheduler(8, 2000, WithWorkStealing(4))
+	defer scheduler.Stop()
+
+	const n = 1000
+	var runs [n]atomic.Int32
+	tasks := make([]*Task, n)
+	for i := range tasks {
+		tasks[i] = &Task{
+			ID: fmt.Sprint(i),
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				runs[i].Add(1)
+				return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+			},
+		}
+	}
+	batch, err := scheduler.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	batch.Wait()
+	for i := range runs {
+		if got := runs[i].Load(); got != 1 {
+			t.Fatalf("Task %d ran %d times", i, got)
+		}
+	}
+}
+
+func TestScheduler_WorkStealingLimits(t *testing.T) {
+	scheduler := NewScheduler(4, 100, WithWorkStealing(4), WithLaneQuota(LaneNormal, 2))
+	defer scheduler.Stop()
+
+	var normal, total peakCounter
+	task := func(id string, weight int, lane *peakCounter) *Task {
+		units := int32(max(weight, 1))
+		return &Task{
+			ID:     id,
+			Weight: weight,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				total.enter(units)
+				if lane != nil {
+					lane.enter(1)
+				}
+				time.Sleep(2 * time.Millisecond)
+				if lane != nil {
+					lane.exit(1)
+				}
+				total.exit(units)
+				return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+			},
+		}
+	}
+	var normalTasks, backgroundTasks []*Task
+	for i := 0; i < 20; i++ {
+		normalTasks = append(normalTasks, task(fmt.Sprintf("normal-%d", i), 1, &normal))
+		backgroundTasks = append(backgroundTasks, task(fmt.Sprintf("heavy-%d", i), 3, nil))
+	}
+	normalBatch, err := scheduler.SubmitBatch(normalTasks)
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	backgroundBatch, err := scheduler.SubmitBackground(backgroundTasks)
+	if err != nil {
+		t.Fatalf("SubmitBackground failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, batch := range []*Batch{normalBatch, backgroundBatch} {
+		if err := batch.WaitContext(ctx); err != nil {
+			t.Fatalf("Batch did not finish: %v", err)
+		}
+	}
+
+	if p := normal.peak.Load(); p > 2 {
+		t.Errorf("Expected lane quota 2 to hold across dispatchers, got peak %d", p)
+	}
+	if p := total.peak.Load(); p > 4 {
+		t.Errorf("Expected total weight bounded by pool size 4, got peak %d", p)
+	}
+}
+
+func TestScheduler_WorkStealingPauseResume(t *testing.T) {
+	scheduler := NewScheduler(4, 100, WithWorkStealing(4))
+	defer scheduler.Stop()
+	scheduler.Pause()
+
+	var ran atomic.Int32
+	var tasks []*Task
+	for i := 0; i < 20; i++ {
+		tasks = append(tasks, &Task{
+			ID: fmt.Sprint(i),
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				ran.Add(1)
+				return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+			},
+		})
+	}
+	batch, err := scheduler.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n := ran.Load(); n != 0 {
+		t.Fatalf("Expected no tasks to run while paused, got %d", n)
+	}
+
+	scheduler.Resume()
+	done := make(chan struct{})
+	go func() {
+		batch.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Batch did not finish after Resume")
+	}
+}
@@ -0,0 +1,53 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBatch_StopPending(t *testing.T) {
+	scheduler := NewScheduler(1, 10)
+	defer scheduler.Stop()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	var interrupted atomic.Bool
+	var ran atomic.Int32
+	tasks := []*Task{{
+		ID: "in-flight",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			close(started)
+			<-release
+			interrupted.Store(ctx.Err() != nil)
+			return TaskResult{HTTPCode: 500}, nil
+		},
+	}}
+	for _, id := range []string{"pending-1", "pending-2"} {
+		tasks = append(tasks, &Task{ID: id, Execute: func(ctx context.Context) (TaskResult, error) {
+			ran.Add(1)
+			return TaskResult{HTTPCode: 500}, nil
+		}})
+	}
+	batch, err := scheduler.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	<-started
+	batch.StopPending()
+	close(release)
+	batch.Wait()
+
+	if interrupted.Load() {
+		t.Error("Expected in-flight task not to be cancelled by StopPending")
+	}
+	if ran.Load() != 0 {
+		t.Errorf("Expected pending tasks not to run, %d ran", ran.Load())
+	}
+	for result := range batch.ResultsChan() {
+		if result.TaskID != "in-flight" && !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("Expected %s to be cancelled, got %v", result.TaskID, result.Err)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package fastscheduler
+
+// Store 持久化键值存储，供持久化队列记录任务状态，实现需要并发安全
+type Store interface {
+	// Put 写入或覆盖键值，返回时数据已持久化
+	Put(key string, value []byte) error
+	// Get 读取键值，不存在时返回 ErrNotFound
+	Get(key string) ([]byte, error)
+	// Delete 删除键，键不存在时不报错
+	Delete(key string) error
+	// Scan 按键的字典序遍历所有键值，fn 返回错误时停止并返回该错误
+	Scan(fn func(key string, value []byte) error) error
+	// Close 关闭存储
+	Close() error
+}
@@ -0,0 +1,167 @@
+// Package storetest 提供 fastscheduler.Store 实现的一致性测试
+// 第三方存储后端可以在自己的测试中调用 TestStore，确认实现满足持久化队列依赖的语义
+package storetest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+// Opener 在同一份底层存储上打开 Store，每次调用都应看到之前关闭的 Store 写入的数据
+type Opener func() (fastscheduler.Store, error)
+
+// TestStore 运行全部一致性测试
+// newStorage 为每个子测试准备一份空的底层存储(例如临时目录)并返回其 Opener
+func TestStore(t *testing.T, newStorage func(t *testing.T) Opener) {
+	t.Run("GetPutDelete", func(t *testing.T) { testGetPutDelete(t, newStorage(t)) })
+	t.Run("ScanOrder", func(t *testing.T) { testScanOrder(t, newStorage(t)) })
+	t.Run("ScanStop", func(t *testing.T) { testScanStop(t, newStorage(t)) })
+	t.Run("Concurrent", func(t *testing.T) { testConcurrent(t, newStorage(t)) })
+	t.Run("Persistence", func(t *testing.T) { testPersistence(t, newStorage(t)) })
+}
+
+// mustOpen 打开存储，打开失败时终止测试
+func mustOpen(t *testing.T, open Opener) fastscheduler.Store {
+	t.Helper()
+	s, err := open()
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	return s
+}
+
+// testGetPutDelete 基本读写、覆盖和删除
+func testGetPutDelete(t *testing.T, open Opener) {
+	s := mustOpen(t, open)
+	defer s.Close()
+
+	if _, err := s.Get("missing"); !errors.Is(err, fastscheduler.ErrNotFound) {
+		t.Errorf("Get missing = %v, want ErrNotFound", err)
+	}
+	if err := s.Put("k", []byte("v1")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := s.Put("k", []byte("v2")); err != nil {
+		t.Fatalf("Put overwrite: %v", err)
+	}
+	if v, err := s.Get("k"); err != nil || !bytes.Equal(v, []byte("v2")) {
+		t.Errorf("Get = %q, %v; want v2", v, err)
+	}
+	if err := s.Delete("k"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get("k"); !errors.Is(err, fastscheduler.ErrNotFound) {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete("k"); err != nil {
+		t.Errorf("Delete missing key: %v", err)
+	}
+}
+
+// testScanOrder Scan 按键的字典序返回所有键值
+func testScanOrder(t *testing.T, open Opener) {
+	s := mustOpen(t, open)
+	defer s.Close()
+
+	for _, k := range []string{"task/0003", "task/0001", "task/0002"} {
+		if err := s.Put(k, []byte(k)); err != nil {
+			t.Fatalf("Put %s: %v", k, err)
+		}
+	}
+	var keys []string
+	err := s.Scan(func(key string, value []byte) error {
+		if string(value) != key {
+			t.Errorf("Scan value for %s = %q", key, value)
+		}
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	want := []string{"task/0001", "task/0002", "task/0003"}
+	if fmt.Sprint(keys) != fmt.Sprint(want) {
+		t.Errorf("Scan order = %v, want %v", keys, want)
+	}
+}
+
+// testScanStop fn 返回错误时 Scan 停止并返回该错误
+func testScanStop(t *testing.T, open Opener) {
+	s := mustOpen(t, open)
+	defer s.Close()
+
+	for i := 0; i < 3; i++ {
+		if err := s.Put(fmt.Sprint(i), nil); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+	stop := errors.New("stop")
+	calls := 0
+	err := s.Scan(func(key string, value []byte) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Scan = %v after %d calls, want stop after 1", err, calls)
+	}
+}
+
+// testConcurrent 并发写入不丢数据
+func testConcurrent(t *testing.T, open Opener) {
+	s := mustOpen(t, open)
+	defer s.Close()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				if err := s.Put(fmt.Sprintf("%d-%02d", w, i), []byte("x")); err != nil {
+					t.Errorf("Put: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	n := 0
+	if err := s.Scan(func(string, []byte) error { n++; return nil }); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if n != 100 {
+		t.Errorf("Scan found %d keys, want 100", n)
+	}
+}
+
+// testPersistence 关闭后重新打开，数据和删除都保留
+func testPersistence(t *testing.T, open Opener) {
+	s := mustOpen(t, open)
+	if err := s.Put("kept", []byte("v")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := s.Put("deleted", []byte("v")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := s.Delete("deleted"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	s = mustOpen(t, open)
+	defer s.Close()
+	if v, err := s.Get("kept"); err != nil || string(v) != "v" {
+		t.Errorf("Get after reopen = %q, %v", v, err)
+	}
+	if _, err := s.Get("deleted"); !errors.Is(err, fastscheduler.ErrNotFound) {
+		t.Errorf("Deleted key survived reopen: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package storetest
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+// memStore 用于验证一致性测试本身的参考实现，关闭后数据保留在共享的 map 中
+type memStore struct {
+	mu   *sync.Mutex
+	data map[string][]byte
+}
+
+func (s memStore) Put(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = append([]byte(nil), value...)
+	return nil
+}
+
+func (s memStore) Get(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.data[key]
+	if !ok {
+		return nil, fastscheduler.ErrNotFound
+	}
+	return v, nil
+}
+
+func (s memStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	return nil
+}
+
+func (s memStore) Scan(fn func(key string, value []byte) error) error {
+	s.mu.Lock()
+	keys := make([]string, 0, len(s.data))
+	for k := range s.data {
+		keys = append(keys, k)
+	}
+	s.mu.Unlock()
+	sort.Strings(keys)
+	for _, k := range keys {
+		v, err := s.Get(k)
+		if err != nil {
+			continue
+		}
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s memStore) Close() error {
+	return nil
+}
+
+func TestStore_Memory(t *testing.T) {
+	TestStore(t, func(t *testing.T) Opener {
+		s := memStore{mu: &sync.Mutex{}, data: make(map[string][]byte)}
+		return func() (fastscheduler.Store, error) {
+			return s, nil
+		}
+	})
+}
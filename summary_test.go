@@ -0,0 +1,55 @@
+package fastscheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBatch_Summary(t *testing.T) {
+	scheduler := NewScheduler(3, 10)
+	defer scheduler.Stop()
+
+	batch, err := scheduler.SubmitBatch([]*Task{
+		{
+			ID: "fail",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 500}, nil
+			},
+		},
+		{
+			ID: "ok",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				time.Sleep(20 * time.Millisecond)
+				return TaskResult{HTTPCode: 200}, nil
+			},
+		},
+		{
+			ID: "slow",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				<-ctx.Done()
+				time.Sleep(30 * time.Millisecond)
+				return TaskResult{}, ctx.Err()
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	sum := batch.Summary()
+	if sum.SucceededTasks != 1 || sum.FailedTasks != 1 || sum.CancelledTasks != 1 {
+		t.Errorf("Unexpected counts: succeeded=%d failed=%d cancelled=%d",
+			sum.SucceededTasks, sum.FailedTasks, sum.CancelledTasks)
+	}
+	if !sum.Succeeded || sum.Winner.TaskID != "ok" || sum.WinnerLatency < 20*time.Millisecond {
+		t.Errorf("Unexpected winner %s after %s", sum.Winner.TaskID, sum.WinnerLatency)
+	}
+	if sum.SlowestTask != "slow" || sum.SlowestLatency < 50*time.Millisecond {
+		t.Errorf("Slowest = %s after %s, want slow after at least 50ms", sum.SlowestTask, sum.SlowestLatency)
+	}
+	if sum.Duration < sum.SlowestLatency || sum.Outcome != OutcomeSucceeded {
+		t.Errorf("Unexpected duration %s or outcome %s", sum.Duration, sum.Outcome)
+	}
+}
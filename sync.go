@@ -0,0 +1,79 @@
+package fastscheduler
+
+import "sync"
+
+// WithSynchronousMode 同步执行模式：提交的任务在提交方的goroutine中按提交顺序逐个执行，
+// SubmitBatch 等提交方法返回时批次中的任务已经执行完毕(回调和对冲尝试仍在各自的goroutine中)。
+// 批次中某个任务成功后，其余尚未执行的任务直接以取消结果完成，不再调用 Execute；
+// 重试等待在提交方goroutine中进行，错开启动(Stagger)和备用任务延迟被忽略，
+// 自定义队列后端、优先级通道和 worker 池大小不生效。
+// 用于复现与并发时序相关的问题，以及对基于调度器的上层代码做确定性的黄金测试
+func WithSynchronousMode() Option {
+	return func(s *Scheduler) {
+		s.inline = &inlineQueue{}
+	}
+}
+
+// inlineQueue 同步模式下待执行的任务
+type inlineQueue struct {
+	mu    sync.Mutex
+	tasks []*Task
+	// draining 是否已有goroutine在执行队列中的任务
+	draining bool
+}
+
+// synchronous 是否为同步执行模式
+func (s *Scheduler) synchronous() bool {
+	return s.inline != nil
+}
+
+// enqueueInline 同步模式下放入任务并在当前goroutine中执行
+// 执行中产生的重试、依赖任务和等待 Key 的任务放入同一队列，由正在执行的goroutine依次执行
+func (s *Scheduler) enqueueInline(t *Task) {
+	q := s.inline
+	q.mu.Lock()
+	q.tasks = append(q.tasks, t)
+	q.mu.Unlock()
+	s.runInline()
+}
+
+// runInline 依次执行队列中的任务直到队列为空或调度器暂停，已有goroutine在执行时直接返回
+// 调度器停止后剩余的任务以 ErrSchedulerStopped 完成
+func (s *Scheduler) runInline() {
+	q := s.inline
+	q.mu.Lock()
+	if q.draining {
+		q.mu.Unlock()
+		return
+	}
+	q.draining = true
+	q.mu.Unlock()
+
+	stop := s.stopSignal()
+	for {
+		q.mu.Lock()
+		if len(q.tasks) == 0 || s.paused.Load() {
+			q.draining = false
+			q.mu.Unlock()
+			return
+		}
+		t := q.tasks[0]
+		q.tasks[0] = nil
+		q.tasks = q.tasks[1:]
+		q.mu.Unlock()
+
+		// 批次中已有任务成功或批次被取消时，不再执行剩余任务
+		if err := t.runCtx().Err(); err != nil {
+			s.skipTask(t, err)
+			continue
+		}
+		if s.stopped() || !s.workerPool.acquire(stop) {
+			s.skipTask(t, ErrSchedulerStopped)
+			continue
+		}
+		s.laneInflight[t.lane].Add(1)
+		s.recordDecision(t, t.lane, false)
+		s.wg.Add(1)
+		s.executeTask(t)
+	}
+}
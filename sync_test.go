@@ -0,0 +1,89 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestScheduler_SynchronousMode(t *testing.T) {
+	scheduler := NewScheduler(4, 1, WithSynchronousMode())
+	defer scheduler.Stop()
+
+	var order []string
+	task := func(id string, codes ...int) *Task {
+		return &Task{
+			ID:    id,
+			Retry: &RetryPolicy{MaxAttempts: len(codes)},
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				order = append(order, id)
+				code := codes[0]
+				codes = codes[1:]
+				return TaskResult{HTTPCode: code}, nil
+			},
+		}
+	}
+
+	// 队列容量为1也不会阻塞：任务不经过队列，提交返回时已按顺序执行完毕
+	batch, err := scheduler.SubmitBatch([]*Task{
+		task("a", 500),
+		task("b", 503, 200),
+		task("c", 200),
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	select {
+	case <-batch.Done():
+	default:
+		t.Fatal("Expected batch to be complete when SubmitBatch returns")
+	}
+	want := []string{"a", "b", "b"}
+	if len(order) != len(want) {
+		t.Fatalf("Execution order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("Execution order = %v, want %v", order, want)
+		}
+	}
+
+	// b 成功后 c 不再执行，直接以取消结果完成
+	results := make(map[string]TaskResult)
+	for r := range batch.ResultsChan() {
+		results[r.TaskID] = r
+	}
+	if !isSuccess(results["b"]) || results["b"].Attempts != 2 {
+		t.Errorf("Unexpected result for b: %+v", results["b"])
+	}
+	if !errors.Is(results["c"].Err, context.Canceled) || results["c"].Attempts != 0 {
+		t.Errorf("Expected c to be cancelled without running, got %+v", results["c"])
+	}
+}
+
+func TestScheduler_SynchronousModeDependencies(t *testing.T) {
+	scheduler := NewScheduler(1, 1, WithSynchronousMode())
+	defer scheduler.Stop()
+
+	var order []string
+	step := func(id string, deps ...string) *Task {
+		return &Task{
+			ID:        id,
+			DependsOn: deps,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				order = append(order, id)
+				return TaskResult{HTTPCode: 200}, nil
+			},
+		}
+	}
+	batch, err := scheduler.SubmitBatch([]*Task{step("publish", "build"), step("build", "fetch"), step("fetch")})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if done, total := batch.Progress(); done != total {
+		t.Fatalf("Expected all tasks done on return, got %d/%d", done, total)
+	}
+	if len(order) != 3 || order[0] != "fetch" || order[1] != "build" || order[2] != "publish" {
+		t.Errorf("Execution order = %v, want [fetch build publish]", order)
+	}
+}
@@ -0,0 +1,61 @@
+package fastscheduler
+
+// TaskClass 任务的资源类型
+type TaskClass int
+
+const (
+	// ClassDefault 默认类型，使用共享worker池
+	ClassDefault TaskClass = iota
+	// ClassIO 以等待网络或磁盘为主的任务
+	ClassIO
+	// ClassCPU 以计算为主的任务
+	ClassCPU
+
+	classCount = 3
+)
+
+// String 返回类型名称
+func (c TaskClass) String() string {
+	switch c {
+	case ClassDefault:
+		return "default"
+	case ClassIO:
+		return "io"
+	case ClassCPU:
+		return "cpu"
+	default:
+		return "unknown"
+	}
+}
+
+// WithClassPools 为 IO 和 CPU 任务分别创建独立的worker池，提交方式不变，按 Task.Class 路由：
+// ClassIO 任务在 ioSize 个名额的池中执行，ClassCPU 任务在 cpuSize 个名额的池中执行，
+// cpuSize 小于等于0或超过可用CPU数(GOMAXPROCS 与容器CPU配额中的较小值)时按可用CPU数。
+// 慢的网络任务不会挤占计算任务，反之亦然；ClassDefault 任务仍使用共享池。
+// 两个池与 WithBulkhead 的隔离舱行为相同，ioSize 小于等于0时不创建IO池，IO任务使用共享池
+func WithClassPools(ioSize, cpuSize int) Option {
+	return func(s *Scheduler) {
+		if ioSize > 0 {
+			s.classPools[ClassIO] = newBulkhead("io pool", ioSize)
+		}
+		s.classPools[ClassCPU] = newBulkhead("cpu pool", capToCPUs(cpuSize))
+	}
+}
+
+// classPool 返回任务类型对应的worker池，未启用时返回nil
+func (s *Scheduler) classPool(c TaskClass) *bulkhead {
+	if c <= ClassDefault || c >= classCount {
+		return nil
+	}
+	return s.classPools[c]
+}
+
+// ClassPoolUsage 返回任务类型对应的worker池中执行中的任务权重之和和容量，未启用时返回 ok=false
+func (s *Scheduler) ClassPoolUsage(c TaskClass) (busy, size int, ok bool) {
+	b := s.classPool(c)
+	if b == nil {
+		return 0, 0, false
+	}
+	busy, size = b.slots.usage()
+	return busy, size, true
+}
@@ -0,0 +1,70 @@
+package fastscheduler
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_ClassPools(t *testing.T) {
+	scheduler := NewScheduler(1, 50, WithClassPools(4, 1))
+	defer scheduler.Stop()
+
+	// IO 任务长时间等待网络，占满 IO 池
+	release := make(chan struct{})
+	var ioRunning atomic.Int32
+	var slow []*Task
+	for i := 0; i < 6; i++ {
+		slow = append(slow, &Task{
+			ID:    fmt.Sprintf("fetch-%d", i),
+			Class: ClassIO,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				ioRunning.Add(1)
+				<-release
+				return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+			},
+		})
+	}
+	fetches, err := scheduler.SubmitBatch(slow)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	waitFor(t, func() bool { return ioRunning.Load() == 4 })
+
+	// CPU 任务和默认任务不受 IO 池影响
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for _, class := range []TaskClass{ClassCPU, ClassDefault} {
+		future, err := scheduler.Submit(&Task{
+			ID:    "compute-" + class.String(),
+			Class: class,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 200}, nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		if err := future.Wait(ctx); err != nil {
+			t.Fatalf("%s task was crowded out by IO tasks: %v", class, err)
+		}
+	}
+
+	if busy, size, ok := scheduler.ClassPoolUsage(ClassIO); !ok || busy != 4 || size != 4 {
+		t.Errorf("Expected IO pool 4/4 busy, got %d/%d ok=%v", busy, size, ok)
+	}
+	if _, size, ok := scheduler.ClassPoolUsage(ClassCPU); !ok || size != 1 {
+		t.Errorf("Expected CPU pool of size 1, got %d ok=%v", size, ok)
+	}
+	if _, _, ok := scheduler.ClassPoolUsage(ClassDefault); ok {
+		t.Error("Default class should use the shared pool")
+	}
+
+	close(release)
+	fetches.Wait()
+	if n := ioRunning.Load(); n != 6 {
+		t.Errorf("Expected all 6 IO tasks to run, got %d", n)
+	}
+}
@@ -0,0 +1,55 @@
+package fastscheduler
+
+import "time"
+
+// TaskRecord 一个已结束任务的摘要
+type TaskRecord struct {
+	// Finished 任务结束的时间
+	Finished time.Time
+	TaskID   string
+	BatchID  string
+	Lane     Lane
+	// Attempts 执行次数，未执行就结束(例如被取消或排队超时)时为0
+	Attempts int
+	// Success 结果是否成功
+	Success bool
+	// Err 失败原因
+	Err error
+	// Duration 最后一次执行的耗时，未执行时为0
+	Duration time.Duration
+}
+
+// WithTaskLog 记录最近结束的 size 个任务，通过 RecentTasks 查看，未启用时没有额外开销
+func WithTaskLog(size int) Option {
+	return func(s *Scheduler) {
+		if size > 0 {
+			s.taskLog = newRingBuffer[TaskRecord](size)
+		}
+	}
+}
+
+// recordTask 记录任务结束
+func (s *Scheduler) recordTask(task *Task, result TaskResult) {
+	if s.taskLog == nil {
+		return
+	}
+	record := TaskRecord{
+		Finished: result.Metrics.FinishedAt,
+		TaskID:   task.ID,
+		BatchID:  task.group.id,
+		Lane:     task.lane,
+		Attempts: task.attempts,
+		Success:  isSuccess(result),
+		Err:      result.Err,
+		Duration: result.Metrics.Duration,
+	}
+	s.taskLog.add(record)
+}
+
+// RecentTasks 返回最近结束的任务(从旧到新)，未通过 WithTaskLog 启用时返回nil
+func (s *Scheduler) RecentTasks() []TaskRecord {
+	if s.taskLog == nil {
+		return nil
+	}
+	return s.taskLog.snapshot()
+}
@@ -0,0 +1,42 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithTaskLog(t *testing.T) {
+	scheduler := NewScheduler(2, 10, WithTaskLog(2))
+	defer scheduler.Stop()
+
+	for _, id := range []string{"first", "second", "third"} {
+		future, err := scheduler.Submit(&Task{
+			ID: id,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				time.Sleep(time.Millisecond)
+				if id == "third" {
+					return TaskResult{}, errors.New("boom")
+				}
+				return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		future.Result()
+	}
+	scheduler.Wait()
+
+	records := scheduler.RecentTasks()
+	if len(records) != 2 || records[0].TaskID != "second" || records[1].TaskID != "third" {
+		t.Fatalf("Expected the 2 most recent tasks, got %+v", records)
+	}
+	if !records[0].Success || records[1].Success || records[1].Err == nil {
+		t.Errorf("Unexpected outcomes %+v", records)
+	}
+	if records[0].Attempts != 1 || records[0].Duration < time.Millisecond {
+		t.Errorf("Expected attempt and duration to be recorded, got %+v", records[0])
+	}
+}
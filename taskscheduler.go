@@ -2,19 +2,62 @@ package fastscheduler
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"runtime"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // TaskResult 表示任务执行结果
 type TaskResult struct {
+	// TaskID 产生该结果的任务ID
+	TaskID       string
 	HTTPCode     int
 	BusinessCode int
 	Err          error
 	Data         interface{}
+
+	// Cost 任务实际消耗的成本，为0时使用Task.Cost
+	Cost float64
+
+	// Truncated 表示Data因超过大小上限被截断或丢弃
+	Truncated bool
+
+	// Hints 产生该结果的任务的环境/位置提示
+	Hints Hints
+
+	// DeadlineMissed 表示任务在 Task.Deadline 之后才完成，结果成功时也会设置
+	DeadlineMissed bool
+
+	// Attempts 任务实际执行的次数，未执行就结束的任务为0
+	Attempts int
+
+	// RetryAfter 任务要求的重试前等待时间(例如被限流时服务端告知的时间)，
+	// 大于0时重试按此等待，代替 RetryPolicy 的 Delay 和 Backoff
+	RetryAfter time.Duration
+
+	// Metrics 任务排队和执行的时间
+	Metrics TaskMetrics
+}
+
+// TaskMetrics 任务各阶段的时间，用于区分排队和执行造成的延迟
+type TaskMetrics struct {
+	// EnqueuedAt 任务提交的时间
+	EnqueuedAt time.Time
+	// StartedAt 最后一次执行开始的时间，未执行就结束的任务为零值
+	StartedAt time.Time
+	// FinishedAt 任务结束的时间
+	FinishedAt time.Time
+	// QueueWait 从提交到最后一次执行开始的时长(重试时包含之前的执行和重试等待)，未执行时为提交到结束的时长
+	QueueWait time.Duration
+	// Duration 最后一次执行的时长，未执行时为0
+	Duration time.Duration
 }
 
 // Task 表示要执行的任务
+// 提交时调度器使用任务的副本，同一个 *Task 可以重复提交
 type Task struct {
 	// ID 用于标识任务
 	ID string
@@ -22,26 +65,274 @@ type Task struct {
 	// Execute 是任务执行函数
 	Execute func(ctx context.Context) (TaskResult, error)
 
-	// ResultChan 用于接收结果(可选)
+	// ResultChan 用于接收结果(可选)，通道已关闭时结果被丢弃
 	ResultChan chan<- TaskResult
 
+	// Cost 任务的预估成本(如API调用额度)，用于成本统计
+	Cost float64
+
+	// Tenant 任务所属租户，用于按租户汇总成本
+	Tenant string
+
+	// Tags 任务标签，用于按标签汇总成本
+	Tags []string
+
+	// Hedge 对冲配置(可选)，慢请求时并发发起重复执行
+	Hedge *HedgeConfig
+
+	// Retry 重试配置(可选)，失败时延迟后重新执行
+	Retry *RetryPolicy
+
+	// DependsOn 同一批次内必须先成功完成的任务ID，依赖失败时该任务以 ErrDependencyFailed 完成
+	// 批次中存在依赖关系时，任务成功不会取消同批次的其他任务
+	DependsOn []string
+
+	// IdempotencyKey 幂等键，相同键的任务同时在执行时只执行一次，结果分发给所有任务
+	IdempotencyKey string
+
+	// Key 串行化键，相同 Key 的任务按提交顺序启动，同时执行的数量受
+	// WithKeyConcurrencyLimit 限制(默认1，即依次执行)，不同 Key 之间并发执行
+	Key string
+
+	// Weight 任务占用的worker名额，默认1
+	// 较重的任务设置更大的权重，worker池按执行中任务的权重之和限制并发，而不是按任务数
+	Weight int
+
+	// Class 任务的资源类型，启用 WithClassPools 时 IO 和 CPU 任务在各自的worker池中执行
+	Class TaskClass
+
+	// Bulkhead 任务所属的隔离舱，需通过 WithBulkhead 注册，为空时使用共享worker池
+	Bulkhead string
+
+	// Hints 任务的环境/位置提示，供区域优先等策略和结果评估使用
+	Hints Hints
+
+	// QueueTTL 任务在队列中的最长等待时间，超时后不再执行，
+	// 以 ErrQueueTTLExpired 结果完成。0 表示使用批次的 WithQueueTTL 设置
+	QueueTTL time.Duration
+
+	// Deadline 任务从提交到完成的目标时长(SLO)，超过后仍会执行，
+	// 但结果标记 DeadlineMissed 并计入 Stats。0 表示使用批次的 WithDeadline 设置
+	Deadline time.Duration
+
+	// Timeout 单次执行的超时，超时后任务的 ctx 被取消。0 表示使用 WithDefaultTaskTimeout 设置
+	Timeout time.Duration
+
+	// OnSuccess、OnFailure、OnCancel 任务结束后按结果调用其中一个(可选)
+	// 回调在独立的goroutine中运行，不占用worker；Batch.Wait 会等待回调返回
+	OnSuccess func(TaskResult)
+	OnFailure func(TaskResult)
+	OnCancel  func(TaskResult)
+
 	// 内部使用的字段
+	lane       Lane
+	release    func()
+	enqueuedAt time.Time
+	startDelay time.Duration
+	keyHeld    bool
+	host       string
+	hostHeld   bool
+	bulkhead   *bulkhead
+	tenantHeld bool
+	// extraSlots 按 Weight 额外占用的worker名额
+	extraSlots int
+	// preempted 本次执行被抢占，由 group.taskMu 保护
+	preempted  bool
+	blocked    bool
+	attempts   int
+	startedAt  time.Time
+	reason     string
 	group      *taskGroup
 	cancelFunc context.CancelFunc
+	// ctx 任务运行所在的上下文，为nil时使用组上下文；备用任务使用自己的上下文
+	ctx context.Context
+	// taskCancel 取消本次执行的上下文，cancelled 表示已通过 CancelTask 取消，由 group.taskMu 保护
+	taskCancel context.CancelFunc
+	cancelled  bool
+	// serial 由 SerializableTask 创建时的可序列化形式
+	serial *SerializableTask
+	// resolved 已在其他进程中执行完成的结果，由 ResolveTask 设置
+	resolved *resolvedResult
 }
 
 // Batch 表示一批任务
 type Batch struct {
+	// Tasks 提交的任务定义；开放批次在 Add 时追加，与 Add 并发读取时使用 TaskList
 	Tasks []*Task
-	group *taskGroup
+	// tasksMu 保护开放批次的 Tasks
+	tasksMu sync.Mutex
+	group   *taskGroup
+
+	// lane 和 opts 用于重新提交
+	lane Lane
+	opts []BatchOption
 }
 
 // Scheduler 任务调度器
 type Scheduler struct {
-	taskQueue  chan *Task
-	workerPool chan struct{}
+	// queues 各优先级通道的排队任务
+	queues     [laneCount]Queue
+	workerPool *workerSlots
 	wg         sync.WaitGroup
 	stopChan   chan struct{}
+
+	// handoff 将任务交给空闲worker goroutine
+	handoff chan *Task
+	// poolSize worker池大小
+	poolSize int
+	// cpuBound 是否将worker数限制在可用CPU数以内
+	cpuBound bool
+	// autoPool 是否随 GOMAXPROCS 自动调整worker数，autoProcs 为上次计算池大小时的 GOMAXPROCS
+	autoPoolMu sync.Mutex
+	autoPool   bool
+	autoProcs  int
+	// autoPoolWatch 以 AutoPoolSize 创建，启动时检查 GOMAXPROCS 的变化
+	autoPoolWatch bool
+	// poolMultiplier 自动池大小的倍数
+	poolMultiplier float64
+	// overflow 队列已满时的提交策略
+	overflow OverflowPolicy
+
+	// lifecycleMu 串行化 Start/Stop
+	lifecycleMu sync.Mutex
+	// stopMu 保护 stopChan 的替换
+	stopMu sync.RWMutex
+	// state 调度器生命周期状态
+	state atomic.Int32
+	// dispatcher 调度goroutine的等待组
+	dispatcher sync.WaitGroup
+	// autoStart 创建后是否自动启动
+	autoStart bool
+
+	// laneQuota 各通道的最大并发数，0表示不限制
+	laneQuota [laneCount]int
+	// laneInflight 各通道正在执行的任务数，仅由调度goroutine和worker更新
+	laneInflight [laneCount]atomic.Int64
+	// quotaReleased 配额释放信号，唤醒等待配额的调度goroutine
+	quotaReleased chan struct{}
+	// laneMu 工作窃取模式下多个调度goroutine检查通道配额时加锁
+	laneMu sync.Mutex
+	// stealShards 工作窃取模式的分片数，0表示使用单个调度goroutine；
+	// shardQueued 和 shardPaused 为各分片调度goroutine的新任务和暂停状态变化信号；
+	// shardHandoff 为各分片自己的空闲worker交接通道，分片之间不共用 handoff
+	stealShards  int
+	shardQueued  []chan struct{}
+	shardPaused  []chan struct{}
+	shardHandoff []chan *Task
+	// newQueue 创建各通道的队列，为nil时使用 NewFIFOQueue
+	newQueue func(lane Lane, capacity int) Queue
+	// queued 任务入队信号，唤醒等待任务的调度goroutine
+	queued chan struct{}
+	// spaceMu 保护 space；space 在任务出队时关闭，唤醒等待空位的提交方
+	spaceMu sync.Mutex
+	space   chan struct{}
+	// spaceWaiters 等待空位的提交方数量，为0时出队不需要唤醒
+	spaceWaiters atomic.Int64
+	// wake 各通道队列实现 Waker 时的唤醒信号，否则为nil
+	wake [laneCount]<-chan struct{}
+	// executor 任务的执行方式，为nil时直接调用 Task.Execute
+	executor Executor
+	// running 正在执行的任务，值为 TaskInfo
+	running sync.Map
+	// batches 未完成的批次
+	batches batchRegistry
+	// paused 是否暂停调度；pauseChanged 在暂停状态变化时唤醒调度goroutine
+	paused       atomic.Bool
+	pauseChanged chan struct{}
+
+	// costs 任务成本台账
+	costs costLedger
+	// costAudit 逐次执行的成本记录，为nil时不记录
+	costAudit CostAuditor
+	// deadlines 完成时限统计
+	deadlines deadlineCounter
+
+	// latencies 对冲目标的延迟样本，未对冲的任务 ID 与已统计的目标相同时同样计入
+	latencies latencyTracker
+	// timings 任务排队和执行耗时的直方图
+	timings timingStats
+
+	// maxDataSize 结果数据的最大字节数，0表示不限制
+	maxDataSize int
+
+	// fetches GetOrFetch 的缓存
+	fetches fetchCache
+
+	// keys 按 Key 限制任务并发
+	keys keyGate
+	// hosts 按目标主机限制HTTP任务并发
+	hosts keyGate
+
+	// codec 结果跨进程传递时的编码
+	codec Codec
+
+	// transformers 结果处理函数
+	transformers []ResultTransformer
+
+	// resultBuffers 调用方 ResultChan 的缓冲，nil表示直接发送
+	resultBuffers *resultBuffers
+	// delivery 直接发送 ResultChan 的策略；droppedResults 丢弃的结果数
+	delivery        DeliveryPolicy
+	deliveryTimeout time.Duration
+	droppedResults  atomic.Int64
+
+	// deadLetters 死信队列，deadLetterHandler 非nil时改为调用该函数
+	deadLetters       chan FailedTask
+	deadLetterHandler func(FailedTask)
+
+	// decisions 最近的调度决策，nil表示未启用
+	decisions *ringBuffer[Decision]
+	// taskLog 最近结束的任务，nil表示未启用
+	taskLog *ringBuffer[TaskRecord]
+
+	// flights 按 IdempotencyKey 合并的执行
+	flights flightGroup
+
+	// breakers 按 Key 熔断，nil表示未启用
+	breakers *breakerSet
+
+	// clock 时间来源，默认系统时间
+	clock Clock
+
+	// inline 同步模式下待执行的任务，nil表示未启用同步模式
+	inline *inlineQueue
+
+	// chaos 故障注入，nil表示未启用
+	chaos *chaos
+
+	// baseCtx 所有组上下文的根上下文
+	baseCtx context.Context
+	// metrics 指标上报，nil表示未启用
+	metrics *metricsReporter
+	// pprofLabels 执行任务时是否附加 pprof 标签
+	pprofLabels bool
+	// watchdog 慢任务检测配置，nil表示未启用
+	watchdog *WatchdogConfig
+	// defaultTimeout 任务单次执行的默认超时；taskTimeouts 超时触发的次数
+	defaultTimeout time.Duration
+	taskTimeouts   atomic.Int64
+
+	// logger 结构化日志，nil表示不输出
+	logger *slog.Logger
+	// events 事件订阅者
+	events eventBus
+
+	// tenants 多租户调度策略和各租户执行中的任务数，nil表示未启用
+	tenants *tenantState
+	// batchFair 同一通道内按批次轮流调度
+	batchFair bool
+	// policy 同一通道内的出队顺序，nil表示先进先出
+	policy SchedulingPolicy
+	// preemption 高优先级任务入队时是否抢占低优先级的执行
+	preemption bool
+
+	// bulkheads 命名隔离舱，创建后只读
+	bulkheads map[string]*bulkhead
+	// classPools 按任务类型划分的worker池，未启用的类型为nil
+	classPools [classCount]*bulkhead
+
+	// leasePools 命名租约池，创建后只读
+	leasePools map[string]chan struct{}
 }
 
 // taskGroup 用于管理一批任务
@@ -50,57 +341,416 @@ type taskGroup struct {
 	cancel  context.CancelFunc
 	success *atomic.Bool
 	wg      sync.WaitGroup
+
+	// id 批次ID；created 批次创建的时间；lane 批次所在的优先级通道
+	id      string
+	created time.Time
+	lane    Lane
+
+	// sched 批次所属的调度器
+	sched *Scheduler
+	// parent 组上下文的父上下文
+	parent context.Context
+	// stopBase 解除父上下文不是 baseCtx 时与 baseCtx 的关联
+	stopBase func() bool
+	// chain Then 提交的后续任务链的上下文，首次使用时创建；它不随批次完成取消，
+	// 只在 Batch.Cancel 或父上下文结束时取消。chainCancelled 记录创建前是否已调用 Batch.Cancel
+	chainMu        sync.Mutex
+	chain          context.Context
+	cancelChain    context.CancelFunc
+	chainCancelled bool
+	// cancelOnSuccess 第一个任务成功时是否取消同组其他任务
+	cancelOnSuccess bool
+	// dag 批次内任务的依赖关系，没有依赖时为nil
+	dag *dagState
+	// transformers 批次级结果处理函数
+	transformers []ResultTransformer
+	// queueTTL 批次内任务的默认排队超时
+	queueTTL time.Duration
+	// deadline 批次内任务的默认完成时限
+	deadline time.Duration
+	// tenant 批次内任务的默认租户
+	tenant string
+	// weight 批次在按批次轮流调度时的权重
+	weight int
+	// after 开始入队前需要等待完成的前序批次
+	after []<-chan struct{}
+	// preferRegion 优先执行的区域，其他区域的任务延迟 fallbackDelay 后启动
+	preferRegion  string
+	fallbackDelay time.Duration
+	// stagger 批次内相邻任务的启动间隔
+	stagger time.Duration
+	// scope 通过受限视图提交时的视图，依赖满足后入队的任务同样受其限制
+	scope *Scope
+
+	// total 批次任务总数
+	total atomic.Int64
+	// remaining 尚未完成的任务数
+	remaining atomic.Int64
+	// closed 不再加入新任务，普通批次创建时即关闭
+	closed atomic.Bool
+	// finishOnce 保证结果流只关闭一次
+	finishOnce sync.Once
+	// stream 开放批次的结果流，普通批次为nil
+	stream *resultStream
+	// onProgress 进度回调(可选)
+	onProgress func(done, total int)
+	// results 按完成顺序缓存结果，容量等于任务数，发送不会阻塞
+	results chan TaskResult
+	// done 所有任务完成后关闭
+	done chan struct{}
+	// winner 第一个成功的结果，winnerAt 其完成的时间，succeeded 关闭后只读
+	winner   TaskResult
+	winnerAt time.Time
+	// succeeded 第一个任务成功时关闭
+	succeeded chan struct{}
+	// finished 批次完成的时间
+	finished time.Time
+	// topK 需要收集的成功结果，nil表示第一个成功即取消其他任务
+	topK *topKState
+	// best 按比较函数选出的最优结果，nil表示第一个成功的结果获胜
+	best *bestState
+	// grace 获胜后取消其余任务前的宽限
+	grace graceState
+	// fallback 所有主任务失败后执行的备用任务，nil表示没有
+	fallback *fallbackState
+	// retryBudget 批次的重试次数上限，nil表示不限制
+	retryBudget *retryBudget
+	// notifiers 批次完成时调用
+	notifiers []Notifier
+
+	// tasks 批次内的任务副本，供 CancelTask 查找
+	taskMu sync.Mutex
+	tasks  []*Task
+
+	// errs 失败任务的错误；tally 已完成任务的计数和最慢耗时，同样由 errMu 保护
+	errMu sync.Mutex
+	errs  []error
+	tally batchTally
+
+	// cost 批次已执行任务的成本总和
+	costMu sync.Mutex
+	cost   float64
+	// budget 批次成本预算，0表示不限制；overBudget 表示已达到预算
+	budget     float64
+	overBudget bool
 }
 
 // NewScheduler 创建一个新的调度器
-// poolSize: goroutine池大小
-// queueSize: 任务队列大小
-func NewScheduler(poolSize, queueSize int) *Scheduler {
+// poolSize: goroutine池大小，传入 AutoPoolSize 时按 GOMAXPROCS 自动计算
+// queueSize: 任务队列大小(每个优先级通道独立计算)，小于1时按1处理：
+// 队列的 Push/Pop 不阻塞，无法实现无缓冲的直接交接，queueSize 为0时每个通道最多有一个任务等待worker
+func NewScheduler(poolSize, queueSize int, opts ...Option) *Scheduler {
 	s := &Scheduler{
-		taskQueue:  make(chan *Task, queueSize),
-		workerPool: make(chan struct{}, poolSize),
-		stopChan:   make(chan struct{}),
+		baseCtx:       context.Background(),
+		clock:         systemClock{},
+		handoff:       make(chan *Task),
+		poolSize:      poolSize,
+		quotaReleased: make(chan struct{}, 1),
+		queued:        make(chan struct{}, 1),
+		pauseChanged:  make(chan struct{}, 1),
+		space:         make(chan struct{}),
+		autoStart:     true,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	for _, lane := range laneOrder {
+		if s.tenants != nil || s.batchFair {
+			s.queues[lane] = newFairQueue(queueSize, s.tenants, s.batchFair, s.policy)
+		} else if s.policy != nil {
+			s.queues[lane] = newPolicyQueue(queueSize, s.policy)
+		} else if s.newQueue != nil {
+			s.queues[lane] = s.newQueue(lane, queueSize)
+		} else if s.stealShards > 0 {
+			if s.shardQueued == nil {
+				s.shardQueued = newSignals(s.stealShards)
+				s.shardPaused = newSignals(s.stealShards)
+				s.shardHandoff = make([]chan *Task, s.stealShards)
+				for i := range s.shardHandoff {
+					s.shardHandoff[i] = make(chan *Task)
+				}
+			}
+			s.queues[lane] = newStealingQueue(queueSize, s.shardQueued)
+		} else {
+			s.queues[lane] = NewFIFOQueue(queueSize)
+		}
+		if w, ok := s.queues[lane].(Waker); ok {
+			s.wake[lane] = w.Wake()
+		}
+	}
+	if s.poolSize == AutoPoolSize {
+		s.autoPool = true
+		s.autoPoolWatch = true
+		s.poolSize = s.autoPoolSize()
+	}
+	if s.cpuBound {
+		s.poolSize = capToCPUs(s.poolSize)
+	}
+	s.workerPool = newWorkerSlots(s.poolSize)
+	if s.baseCtx.Done() != nil {
+		context.AfterFunc(s.baseCtx, s.Stop)
 	}
 
 	// 启动调度器
-	s.start()
+	if s.autoStart {
+		s.Start()
+	}
 	return s
 }
 
-// start 启动调度器
+// start 启动调度goroutine，调用方需持有 lifecycleMu
 func (s *Scheduler) start() {
-	s.wg.Add(1)
+	stop := make(chan struct{})
+	s.stopMu.Lock()
+	s.stopChan = stop
+	s.stopMu.Unlock()
+	if s.metrics != nil {
+		s.dispatcher.Add(1)
+		go func() {
+			defer s.dispatcher.Done()
+			s.metrics.run(s, stop)
+		}()
+	}
+	if s.watchdog != nil {
+		s.dispatcher.Add(1)
+		go func() {
+			defer s.dispatcher.Done()
+			s.runWatchdog(stop)
+		}()
+	}
+	// 同步模式下任务在提交方goroutine中执行，不需要调度goroutine
+	if s.synchronous() {
+		return
+	}
+	if s.autoPoolWatch {
+		s.dispatcher.Add(1)
+		go func() {
+			defer s.dispatcher.Done()
+			s.runAutoPool(stop)
+		}()
+	}
+	for _, b := range s.allBulkheads() {
+		s.dispatcher.Add(1)
+		go func() {
+			defer s.dispatcher.Done()
+			s.runBulkhead(b, stop)
+		}()
+	}
+	if s.shardQueued != nil {
+		for home := range s.shardQueued {
+			s.dispatcher.Add(1)
+			go func() {
+				defer s.dispatcher.Done()
+				s.runShard(home, stop)
+			}()
+		}
+		return
+	}
+	s.dispatcher.Add(1)
 	go func() {
-		defer s.wg.Done()
+		defer s.dispatcher.Done()
 		for {
-			select {
-			case task := <-s.taskQueue:
-				// 获取worker
-				s.workerPool <- struct{}{}
-				s.wg.Add(1)
-				go s.executeTask(task)
-			case <-s.stopChan:
+			// 先获取worker再取任务，保证空闲时取出的总是最高优先级的任务
+			if !s.workerPool.acquire(stop) {
 				return
 			}
+			task, ok := s.nextTask(stop, noShard)
+			if !ok {
+				s.workerPool.release()
+				return
+			}
+			if !s.workerPool.acquireWeight(task, stop) {
+				s.workerPool.release()
+				s.unpopTask(task)
+				return
+			}
+			s.wg.Add(1)
+			s.dispatch(task, s.handoff, stop)
+			if cooperativeDispatch {
+				runtime.Gosched()
+			}
 		}
 	}()
 }
 
+// workerIdleTimeout 空闲worker goroutine的存活时间
+const workerIdleTimeout = time.Second
+
+// dispatch 将任务交给空闲的worker goroutine，没有空闲worker时新建一个
+// worker数量受 workerPool 约束，复用goroutine避免每个任务创建一次
+// handoff 为调度goroutine自己的交接通道，worker 只从创建它的调度goroutine接收任务
+func (s *Scheduler) dispatch(task *Task, handoff chan *Task, stop <-chan struct{}) {
+	select {
+	case handoff <- task:
+	default:
+		go s.worker(task, handoff, stop)
+	}
+}
+
+// worker 执行任务后保持空闲等待下一个任务，空闲超时或调度器停止时退出
+func (s *Scheduler) worker(task *Task, handoff <-chan *Task, stop <-chan struct{}) {
+	idle := time.NewTimer(workerIdleTimeout)
+	defer idle.Stop()
+	for {
+		s.executeTask(task)
+
+		if !idle.Stop() {
+			select {
+			case <-idle.C:
+			default:
+			}
+		}
+		idle.Reset(workerIdleTimeout)
+		select {
+		case task = <-handoff:
+		case <-idle.C:
+			return
+		case <-stop:
+			return
+		}
+	}
+}
+
 // executeTask 执行单个任务
 func (s *Scheduler) executeTask(task *Task) {
+	release := s.taskRelease(task)
 	defer func() {
-		<-s.workerPool // 释放worker
+		release()
 		s.wg.Done()
-		task.group.wg.Done()
 	}()
 
+	task.attempts++
+	// 已在其他进程中执行完成的任务不再执行
+	if r := task.resolved; r != nil {
+		task.resolved = nil
+		s.finishTask(task, r.result, r.err)
+		return
+	}
+
+	// 通过 CancelTask 取消的任务不再执行
+	taskCtx, endTask, ok := task.group.taskContext(task)
+	if !ok {
+		s.finishTask(task, TaskResult{}, context.Canceled)
+		return
+	}
+	defer endTask()
+
+	// 批次已停止开始新任务(获胜后的取消宽限期间)
+	if task.group.pendingStopped() {
+		s.finishTask(task, TaskResult{}, context.Canceled)
+		return
+	}
+
+	// 排队超时的任务不再执行，重试时不再检查
+	if task.attempts == 1 && task.QueueTTL > 0 && s.since(task.enqueuedAt) > task.QueueTTL {
+		s.finishTask(task, TaskResult{HTTPCode: 504}, ErrQueueTTLExpired)
+		return
+	}
+
+	// 预估成本超出批次剩余预算的任务不再执行
+	if !task.group.admitCost(task.Cost) {
+		s.finishTask(task, TaskResult{}, ErrBudgetExceeded)
+		return
+	}
+
+	// 相同 IdempotencyKey 的任务正在执行时，等待其结果而不是重复执行
+	if task.IdempotencyKey != "" && !s.flights.join(task) {
+		return
+	}
+
+	// 目标熔断期间快速失败，不占用worker执行
+	useBreaker := s.breakers != nil && task.Key != ""
+	if useBreaker && !s.breakers.allow(task.Key, s.now()) {
+		s.finishTask(task, TaskResult{HTTPCode: 503}, ErrCircuitOpen)
+		return
+	}
+
 	var result TaskResult
 
-	// 执行任务
+	// 执行任务，任务获取的租约在执行结束时归还
+	execCtx, timedOut := s.withTimeout(taskCtx, task)
+	ctx, releaseLeases := s.withLeases(execCtx)
 	var err error
-	result, err = task.Execute(task.group.ctx)
+	func() {
+		defer releaseLeases()
+		ctx, untrack := s.trackRunning(ctx, task)
+		defer untrack()
+		s.logTask(slog.LevelDebug, "task started", task)
+		s.publishTask(EventTaskStarted, task, nil)
+		if task.Hedge != nil {
+			result, err = s.runHedged(ctx, task)
+		} else {
+			start := s.now()
+			result, err = s.execute(ctx, task)
+			if ctx.Err() == nil {
+				s.latencies.recordTracked(task.ID, s.since(start))
+			}
+		}
+	}()
+	// 被抢占的执行不计入结果，批次仍有效时重新入队
+	if s.preemption && task.group.takePreempted(task) && task.runCtx().Err() == nil {
+		s.requeuePreempted(task)
+		return
+	}
+	if timedOut() && err == nil && !isSuccess(result) {
+		err = context.DeadlineExceeded
+	}
+
+	// 记录成本
+	if result.Cost == 0 {
+		result.Cost = task.Cost
+	}
+	task.group.chargeCost(result.Cost)
+	s.costs.add(task.Tenant, task.Tags, result.Cost)
+	s.auditCost(task, result, err)
+	if useBreaker {
+		s.breakers.record(task.Key, result, err, s.now())
+	}
+
+	if s.shouldRetry(task, result, err) {
+		if task.group.spendRetry() {
+			s.retry(task, result, err)
+			return
+		}
+		err = s.exhaustRetry(task, err)
+	}
+	s.finishTask(task, result, err)
+}
+
+// skipTask 以错误结果完成一个未执行的任务
+func (s *Scheduler) skipTask(task *Task, err error) {
+	s.finishTask(task, TaskResult{}, err)
+}
+
+// finishTask 处理任务结果：补全状态码、判定成功、投递结果并更新批次状态
+func (s *Scheduler) finishTask(task *Task, result TaskResult, err error) {
+	defer task.group.wg.Done()
+	if task.IdempotencyKey != "" {
+		defer s.shareResult(task, result, err)
+	}
+	if task.release != nil {
+		task.release()
+	}
+	if task.keyHeld {
+		s.releaseKey(task.Key)
+	}
+	s.releaseHost(task)
+	defer func() {
+		if task.group.dag != nil {
+			s.resolveDependents(task, isSuccess(result))
+		}
+	}()
+
+	result.TaskID = task.ID
+	result.Hints = task.Hints
+	result.Attempts = task.attempts
+	result.Metrics = task.metrics(s.now())
+	if task.Deadline > 0 {
+		result.DeadlineMissed = s.since(task.enqueuedAt) > task.Deadline
+	}
 	if err != nil {
-		result.Err = err
+		result.Err = task.group.budgetError(err)
 		// 确保在错误情况下也设置适当的状态码
 		if result.HTTPCode == 0 {
 			result.HTTPCode = 500
@@ -110,55 +760,410 @@ func (s *Scheduler) executeTask(task *Task) {
 		}
 	}
 
+	result = s.transform(task.group, result)
+	truncateData(&result, s.maxDataSize)
+
 	// 检查是否成功(HTTP 200且业务码0)
-	if result.HTTPCode == 200 && result.BusinessCode == 0 {
-		if task.group.success.CompareAndSwap(false, true) {
-			// 第一个成功的任务，取消同组其他任务
-			task.group.cancel()
+	if isSuccess(result) {
+		if task.group.best != nil {
+			task.group.offerBest(result, s.now())
+		} else if task.group.success.CompareAndSwap(false, true) {
+			task.group.winner = result
+			task.group.winnerAt = s.now()
+			close(task.group.succeeded)
 		}
+		if task.group.collect(result) && task.group.cancelOnSuccess {
+			// 成功的任务已经足够(默认为第一个)，取消同组其他任务
+			task.group.cancelOnWin()
+		}
+	} else {
+		task.group.recordFailure(result)
+		s.deadLetter(task, result)
+	}
+	if task.Deadline > 0 {
+		s.deadlines.record(result)
 	}
 
 	// 发送结果(如果有接收channel)
 	if task.ResultChan != nil {
-		task.ResultChan <- result
+		s.deliverResult(task.ResultChan, result)
+	}
+
+	s.timings.record(task.Key, result.Metrics)
+	s.runCallbacks(task, result)
+	s.ack(task, result)
+	s.recordTask(task, result)
+	s.logTaskFinished(task, result)
+	s.publishTask(EventTaskFinished, task, func(e *Event) { e.Result = result })
+	task.group.complete(result)
+}
+
+// isSuccess 判断结果是否成功(HTTP 200且业务码0)
+func isSuccess(result TaskResult) bool {
+	return result.HTTPCode == 200 && result.BusinessCode == 0
+}
+
+// complete 记录一个任务完成，批次关闭后最后一个任务完成时关闭结果流
+func (g *taskGroup) complete(result TaskResult) {
+	g.count(result)
+	if g.stream != nil {
+		g.stream.push(result)
+	} else {
+		g.results <- result
 	}
+	remaining := g.remaining.Add(-1)
+	if g.onProgress != nil {
+		total := g.total.Load()
+		g.onProgress(int(total-remaining), int(total))
+	}
+	if remaining == 0 && g.closed.Load() && !g.runFallback() {
+		g.finish()
+	}
+}
+
+// finish 关闭结果流并标记批次完成
+// 所有任务都已完成，取消组上下文以释放其资源并中止仍在运行的对冲尝试
+func (g *taskGroup) finish() {
+	g.finishOnce.Do(func() {
+		g.sched.batches.forget(g.id)
+		g.finished = g.sched.now()
+		if g.best != nil {
+			g.pickBest()
+		}
+		g.cancel()
+		if g.stopBase != nil {
+			g.stopBase()
+		}
+		if g.fallback != nil {
+			g.fallback.release()
+		}
+		if g.stream == nil {
+			close(g.results)
+		}
+		close(g.done)
+		if len(g.notifiers) > 0 {
+			go g.sched.notify(g)
+		}
+		if g.sched.subscribed() {
+			g.sched.publish(Event{Type: EventBatchCompleted, BatchID: g.id, Lane: g.lane, Outcome: g.outcome()})
+		}
+		if g.sched.logger != nil {
+			g.sched.logBatch(slog.LevelInfo, "batch completed", g,
+				slog.String("outcome", g.outcome().String()),
+				slog.Int64("total", g.total.Load()),
+				slog.Duration("duration", g.sched.since(g.created)))
+		}
+	})
 }
 
 // SubmitBatch 提交一批任务
-func (s *Scheduler) SubmitBatch(tasks []*Task) *Batch {
-	ctx, cancel := context.WithCancel(context.Background())
-	group := &taskGroup{
-		ctx:     ctx,
-		cancel:  cancel,
-		success: &atomic.Bool{},
+// 调度器已停止时返回 ErrSchedulerStopped
+func (s *Scheduler) SubmitBatch(tasks []*Task, opts ...BatchOption) (*Batch, error) {
+	return s.submitBatch(LaneNormal, tasks, opts)
+}
+
+// SubmitBatchContext 提交一批任务，批次的上下文从 ctx 派生
+// 任务的 ctx 带有调用方上下文中的值(如追踪ID、认证信息)，ctx 取消时批次随之取消
+func (s *Scheduler) SubmitBatchContext(ctx context.Context, tasks []*Task, opts ...BatchOption) (*Batch, error) {
+	return s.submitBatch(LaneNormal, tasks, append([]BatchOption{withParentContext(ctx)}, opts...))
+}
+
+// submitBatch 将一批任务提交到指定优先级通道
+// 入队过程中调度器停止时，未入队的任务以 ErrSchedulerStopped 完成，并返回批次和该错误
+func (s *Scheduler) submitBatch(lane Lane, tasks []*Task, opts []BatchOption) (*Batch, error) {
+	if s.stopped() {
+		return nil, ErrSchedulerStopped
+	}
+	batch, queued, err := s.prepareBatch(lane, tasks, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(batch.group.after) > 0 {
+		run := func() {
+			if err := batch.group.waitAfter(); err != nil {
+				s.skipAll(queued, err)
+				return
+			}
+			_ = s.enqueueAll(queued)
+		}
+		if s.synchronous() {
+			run()
+		} else {
+			go run()
+		}
+		return batch, nil
+	}
+	return batch, s.enqueueAll(queued)
+}
+
+// skipAll 以错误完成所有尚未入队的任务，等待依赖的任务随依赖失败而完成
+func (s *Scheduler) skipAll(queued []*Task, err error) {
+	for _, t := range queued {
+		if !t.blocked {
+			s.skipTask(t, err)
+		}
+	}
+}
+
+// enqueueAll 依次入队，失败时以该错误完成剩余任务
+// 设置了启动延迟的任务在其余任务入队后延迟入队，有未完成依赖的任务暂不入队
+func (s *Scheduler) enqueueAll(queued []*Task) error {
+	var delayed []*Task
+	for i, t := range queued {
+		if t.blocked {
+			// 等待依赖完成后由 resolveDependents 入队
+			continue
+		}
+		if t.startDelay > 0 && !s.synchronous() {
+			delayed = append(delayed, t)
+			continue
+		}
+		if err := s.enqueue(t); err != nil {
+			s.skipAll(append(delayed, queued[i:]...), err)
+			return err
+		}
+	}
+	for _, t := range delayed {
+		go s.enqueueAfter(t, t.startDelay)
+	}
+	return nil
+}
+
+// admitTask 按提交时的准入路径入队：先等待批次所属视图的限制，设置了启动延迟时延迟入队
+// 视图的限制可能阻塞，调用方需在提交路径之外调用
+func (s *Scheduler) admitTask(t *Task) error {
+	if sc := t.group.scope; sc != nil {
+		sc.admit(t)
+	}
+	if t.startDelay > 0 && !s.synchronous() {
+		go s.enqueueAfter(t, t.startDelay)
+		return nil
+	}
+	return s.enqueue(t)
+}
+
+// enqueueAfter 延迟 d 后入队，等待期间批次被取消或停止开始新任务时直接以取消结果完成
+func (s *Scheduler) enqueueAfter(t *Task, d time.Duration) {
+	timer := s.clock.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+	case <-t.runCtx().Done():
+		s.skipTask(t, t.runCtx().Err())
+		return
+	case <-t.group.grace.halted:
+		s.skipTask(t, context.Canceled)
+		return
+	}
+	if err := s.enqueue(t); err != nil {
+		s.skipTask(t, err)
 	}
+}
 
-	batch := &Batch{
-		Tasks: tasks,
-		group: group,
+// requeue 在执行路径之外将任务入队，失败时以该错误完成任务
+// 同步模式下直接入队以保持执行顺序确定，否则在新的goroutine中入队，避免阻塞调用方
+func (s *Scheduler) requeue(t *Task) {
+	if s.synchronous() {
+		if err := s.enqueue(t); err != nil {
+			s.skipTask(t, err)
+		}
+		return
+	}
+	go func() {
+		if err := s.enqueue(t); err != nil {
+			s.skipTask(t, err)
+		}
+	}()
+}
+
+// enqueue 将任务放入所属通道，通道已满时按溢出策略处理，调度器停止时返回 ErrSchedulerStopped
+func (s *Scheduler) enqueue(t *Task) error {
+	if s.stopped() {
+		return ErrSchedulerStopped
+	}
+	// 相同 Key 的任务达到并发上限时排队等待，持有者完成时再入队
+	if t.Key != "" && !t.keyHeld {
+		if !s.keys.acquire(t.Key, t) {
+			return nil
+		}
+		t.keyHeld = true
+	}
+	// 同一主机的HTTP任务达到上限时同样排队等待
+	if !s.acquireHost(t) {
+		return nil
+	}
+	b, err := s.bulkheadFor(t)
+	if err != nil {
+		return err
+	}
+	if s.synchronous() {
+		s.enqueueInline(t)
+		return nil
+	}
+	if b != nil {
+		b.push(t)
+		return nil
+	}
+	if handled, err := s.offer(t); handled {
+		return err
+	}
+	if err := s.pushTask(t); err != ErrQueueFull {
+		return err
+	}
+
+	// 队列已满，等待出队腾出空位
+	s.spaceWaiters.Add(1)
+	defer s.spaceWaiters.Add(-1)
+	for {
+		// 先取得空位信号再尝试放入，避免错过两者之间的出队
+		s.spaceMu.Lock()
+		space := s.space
+		s.spaceMu.Unlock()
+		if err := s.pushTask(t); err != ErrQueueFull {
+			return err
+		}
+		select {
+		case <-space:
+		case <-s.stopSignal():
+			return ErrSchedulerStopped
+		}
+	}
+}
+
+// prepareBatch 创建批次及其任务的内部副本，但不入队
+func (s *Scheduler) prepareBatch(lane Lane, tasks []*Task, opts []BatchOption) (*Batch, []*Task, error) {
+	deps, err := resolveDependencies(tasks)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	batch, err := s.newBatch(lane, tasks, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	group := batch.group
+	// 备用任务执行时多产生一个结果
+	capacity := len(tasks)
+	if group.fallback != nil {
+		capacity++
+	}
+	group.results = make(chan TaskResult, capacity)
+	if deps != nil {
+		// 有依赖关系的批次需要全部执行，成功的任务不能取消其下游任务
+		group.cancelOnSuccess = false
+	}
+	group.total.Store(int64(len(tasks)))
+	group.remaining.Store(int64(len(tasks)))
+	group.closed.Store(true)
+	if len(tasks) == 0 {
+		group.finish()
 	}
 
 	group.wg.Add(len(tasks))
-	for _, task := range tasks {
-		task.group = group
-		task.cancelFunc = cancel
-		s.taskQueue <- task
+	// 一次性分配整批任务的副本，避免逐个任务分配
+	copies := make([]Task, len(tasks))
+	queued := make([]*Task, len(tasks))
+	now := s.now()
+	for i, task := range tasks {
+		// 复制任务，同一个 *Task 可以安全地在多个批次中重复提交
+		copies[i] = *task
+		t := &copies[i]
+		group.attach(t, lane, now)
+		if group.stagger > 0 {
+			t.startDelay += time.Duration(i) * group.stagger
+		}
+		queued[i] = t
+	}
+	if deps != nil {
+		group.dag = newDAGState(queued, deps)
 	}
 
-	return batch
+	return batch, queued, nil
 }
 
-// Wait 等待所有任务完成
-func (s *Scheduler) Wait() {
-	s.wg.Wait()
+// newGroup 按批次选项创建任务组
+func (s *Scheduler) newGroup(opts []BatchOption) *taskGroup {
+	group := &taskGroup{
+		sched:           s,
+		parent:          s.baseCtx,
+		success:         &atomic.Bool{},
+		cancelOnSuccess: true,
+		done:            make(chan struct{}),
+		succeeded:       make(chan struct{}),
+		grace:           graceState{halted: make(chan struct{})},
+	}
+	for _, opt := range opts {
+		opt(group)
+	}
+	group.ctx, group.cancel = context.WithCancel(group.parent)
+	if group.parent != s.baseCtx && s.baseCtx.Done() != nil {
+		group.stopBase = context.AfterFunc(s.baseCtx, group.cancel)
+	}
+	return group
+}
+
+// chainContext 返回后续任务链的上下文。父上下文为 baseCtx 时不直接派生自它，
+// 以免在 baseCtx 上长期登记，后续批次仍通过 newGroup 与 baseCtx 关联
+func (g *taskGroup) chainContext() context.Context {
+	g.chainMu.Lock()
+	defer g.chainMu.Unlock()
+	if g.chain == nil {
+		parent := g.parent
+		if parent == g.sched.baseCtx {
+			parent = context.WithoutCancel(parent)
+		}
+		g.chain, g.cancelChain = context.WithCancel(parent)
+		if g.chainCancelled {
+			g.cancelChain()
+		}
+	}
+	return g.chain
 }
 
-// Stop 停止调度器
-func (s *Scheduler) Stop() {
-	close(s.stopChan)
+// cancelAll 取消任务组及由它派生的后续任务链
+func (g *taskGroup) cancelAll() {
+	g.chainMu.Lock()
+	g.chainCancelled = true
+	if g.cancelChain != nil {
+		g.cancelChain()
+	}
+	g.chainMu.Unlock()
+	g.cancel()
+}
+
+// runCtx 返回任务运行所在的上下文
+func (t *Task) runCtx() context.Context {
+	if t.ctx != nil {
+		return t.ctx
+	}
+	return t.group.ctx
+}
+
+// attach 将任务副本加入任务组并设置批次级默认值
+func (g *taskGroup) attach(t *Task, lane Lane, now time.Time) {
+	t.group = g
+	t.cancelFunc = g.cancel
+	g.track(t)
+	t.lane = lane
+	t.enqueuedAt = now
+	if t.QueueTTL == 0 {
+		t.QueueTTL = g.queueTTL
+	}
+	if t.Deadline == 0 {
+		t.Deadline = g.deadline
+	}
+	if t.Tenant == "" {
+		t.Tenant = g.tenant
+	}
+	if g.preferRegion != "" && t.Hints.Region != g.preferRegion {
+		t.startDelay = g.fallbackDelay
+		t.reason = "delayed as region fallback"
+	}
+}
+
+// Wait 等待所有已开始执行的任务完成
+func (s *Scheduler) Wait() {
 	s.wg.Wait()
-	close(s.taskQueue)
-	close(s.workerPool)
 }
 
 // Wait 等待批次中的所有任务完成
@@ -166,7 +1171,77 @@ func (b *Batch) Wait() {
 	b.group.wg.Wait()
 }
 
+// WaitContext 等待批次中的所有任务完成或 ctx 结束，ctx 先结束时返回 ctx.Err()
+// ctx 结束不会取消批次，需要时调用 Cancel；与 Done 一样不等待任务回调
+func (b *Batch) WaitContext(ctx context.Context) error {
+	select {
+	case <-b.group.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WaitFirstSuccess 在第一个任务成功时立即返回其结果，不等待其余任务处理取消
+// 批次完成且没有任务成功时返回 ErrAllFailed 和各任务的错误，ctx 先结束时返回 ctx.Err()
+func (b *Batch) WaitFirstSuccess(ctx context.Context) (TaskResult, error) {
+	select {
+	case <-b.group.succeeded:
+		return b.group.winner, nil
+	case <-b.group.done:
+	case <-ctx.Done():
+		return TaskResult{}, ctx.Err()
+	}
+	// 完成前可能刚好有任务成功
+	select {
+	case <-b.group.succeeded:
+		return b.group.winner, nil
+	default:
+		return TaskResult{}, errors.Join(ErrAllFailed, b.Err())
+	}
+}
+
+// Done 返回在批次所有任务完成后关闭的通道，此时批次的上下文已被取消
+// 可以与调用方自己的超时或取消一起 select；任务回调可能仍在运行，需要等待回调时使用 Wait
+func (b *Batch) Done() <-chan struct{} {
+	return b.group.done
+}
+
 // IsSuccess 返回批次中是否有任务成功
 func (b *Batch) IsSuccess() bool {
 	return b.group.success.Load()
 }
+
+// ResultsChan 返回按完成顺序产出结果的只读通道，批次完成后通道关闭
+func (b *Batch) ResultsChan() <-chan TaskResult {
+	if b.group.stream != nil {
+		return b.group.stream.subscribe(b.group.done)
+	}
+	return b.group.results
+}
+
+// Progress 返回批次已完成的任务数和任务总数
+func (b *Batch) Progress() (done, total int) {
+	total = int(b.group.total.Load())
+	return total - int(b.group.remaining.Load()), total
+}
+
+// Cost 返回批次中已执行任务的成本总和
+func (b *Batch) Cost() float64 {
+	b.group.costMu.Lock()
+	defer b.group.costMu.Unlock()
+	return b.group.cost
+}
+
+// Resubmit 使用原始任务定义和批次选项重新提交一个新批次
+// 新批次拥有独立的上下文和状态，可用于基础设施瞬时故障后重试整个批次
+func (b *Batch) Resubmit(s *Scheduler) (*Batch, error) {
+	return s.submitBatch(b.lane, b.TaskList(), b.opts)
+}
+
+// TaskList 返回批次任务定义的副本，可以与开放批次的 Add 并发调用
+func (b *Batch) TaskList() []*Task {
+	b.tasksMu.Lock()
+	defer b.tasksMu.Unlock()
+	return append([]*Task(nil), b.Tasks...)
+}
@@ -3,6 +3,8 @@ package fastscheduler
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -36,7 +38,10 @@ func TestScheduler_BasicFunctionality(t *testing.T) {
 	}
 
 	// 提交批次
-	batch := scheduler.SubmitBatch([]*Task{successTask, failTask})
+	batch, err := scheduler.SubmitBatch([]*Task{successTask, failTask})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
 	batch.Wait()
 
 	if !batch.IsSuccess() {
@@ -83,7 +88,10 @@ func TestScheduler_EarlyCancellation(t *testing.T) {
 	}
 
 	// 提交批次
-	batch := scheduler.SubmitBatch([]*Task{fastTask, slowTask})
+	batch, err := scheduler.SubmitBatch([]*Task{fastTask, slowTask})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
 	batch.Wait()
 
 	// 检查执行顺序和结果
@@ -132,7 +140,10 @@ func TestScheduler_AllTasksFail(t *testing.T) {
 	}
 
 	// 提交批次
-	batch := scheduler.SubmitBatch([]*Task{task1, task2})
+	batch, err := scheduler.SubmitBatch([]*Task{task1, task2})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
 	batch.Wait()
 
 	if batch.IsSuccess() {
@@ -140,6 +151,169 @@ func TestScheduler_AllTasksFail(t *testing.T) {
 	}
 }
 
+func TestScheduler_SubmitBatchContext(t *testing.T) {
+	scheduler := NewScheduler(2, 10)
+	defer scheduler.Stop()
+
+	type traceKey struct{}
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), traceKey{}, "trace-1"))
+	started := make(chan struct{})
+	batch, err := scheduler.SubmitBatchContext(ctx, []*Task{{
+		ID: "traced",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			if ctx.Value(traceKey{}) != "trace-1" {
+				t.Error("Expected caller context values in task context")
+			}
+			close(started)
+			<-ctx.Done()
+			return TaskResult{}, ctx.Err()
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	<-started
+	cancel()
+	batch.Wait()
+	if batch.Outcome() != OutcomeCancelled {
+		t.Errorf("Expected cancelled batch, got %s", batch.Outcome())
+	}
+}
+
+func TestTaskResult_Metrics(t *testing.T) {
+	scheduler := NewScheduler(1, 10)
+	defer scheduler.Stop()
+
+	// worker池大小为1，第二个任务需要等待第一个执行完
+	slow := func(ctx context.Context) (TaskResult, error) {
+		time.Sleep(30 * time.Millisecond)
+		return TaskResult{HTTPCode: 500}, nil
+	}
+	batch, err := scheduler.SubmitBatch([]*Task{{ID: "first", Execute: slow}, {ID: "second", Execute: slow}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	results := make(map[string]TaskMetrics)
+	for r := range batch.ResultsChan() {
+		results[r.TaskID] = r.Metrics
+	}
+	first, second := results["first"], results["second"]
+	if first.Duration < 30*time.Millisecond || second.Duration < 30*time.Millisecond {
+		t.Errorf("Expected execution durations >= 30ms, got %s and %s", first.Duration, second.Duration)
+	}
+	if second.QueueWait < 30*time.Millisecond || second.QueueWait < first.QueueWait {
+		t.Errorf("Expected second task to wait for the first, got queue waits %s and %s", first.QueueWait, second.QueueWait)
+	}
+	if !second.StartedAt.After(second.EnqueuedAt) || second.FinishedAt.Sub(second.StartedAt) != second.Duration {
+		t.Errorf("Inconsistent metrics %+v", second)
+	}
+}
+
+func TestBatch_WaitContext(t *testing.T) {
+	scheduler := NewScheduler(1, 10)
+	defer scheduler.Stop()
+
+	release := make(chan struct{})
+	batch, err := scheduler.SubmitBatch([]*Task{{
+		ID: "slow",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			<-release
+			return TaskResult{HTTPCode: 200}, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := batch.WaitContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+
+	close(release)
+	if err := batch.WaitContext(context.Background()); err != nil {
+		t.Errorf("WaitContext failed: %v", err)
+	}
+	if !batch.IsSuccess() {
+		t.Error("Expected batch to succeed")
+	}
+}
+
+func TestBatch_WaitFirstSuccess(t *testing.T) {
+	scheduler := NewScheduler(2, 10)
+	defer scheduler.Stop()
+
+	// 失败方在取消后仍需要较长时间清理
+	cleanup := make(chan struct{})
+	batch, err := scheduler.SubmitBatch([]*Task{
+		{
+			ID: "fast",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 200, Data: "fast"}, nil
+			},
+		},
+		{
+			ID: "loser",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				<-ctx.Done()
+				<-cleanup
+				return TaskResult{}, ctx.Err()
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	defer close(cleanup)
+
+	result, err := batch.WaitFirstSuccess(context.Background())
+	if err != nil || result.TaskID != "fast" {
+		t.Errorf("Expected fast result, got %+v, %v", result, err)
+	}
+
+	failed, err := scheduler.SubmitBatch([]*Task{{
+		ID: "fail",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: 500}, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if _, err := failed.WaitFirstSuccess(context.Background()); !errors.Is(err, ErrAllFailed) {
+		t.Errorf("Expected ErrAllFailed, got %v", err)
+	}
+}
+
+func TestBatch_DoneCancelsContext(t *testing.T) {
+	scheduler := NewScheduler(2, 10)
+	defer scheduler.Stop()
+
+	fail := func(ctx context.Context) (TaskResult, error) {
+		return TaskResult{HTTPCode: 500}, nil
+	}
+	batch, err := scheduler.SubmitBatch([]*Task{{ID: "a", Execute: fail}, {ID: "b", Execute: fail}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	select {
+	case <-batch.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for batch Done")
+	}
+	// 全部失败的批次完成后组上下文同样被取消
+	if err := batch.group.ctx.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected group context cancelled after completion, got %v", err)
+	}
+	if batch.Outcome() != OutcomeAllFailed {
+		t.Errorf("Expected all failed outcome, got %s", batch.Outcome())
+	}
+}
+
 func TestScheduler_ErrorHandling(t *testing.T) {
 	scheduler := NewScheduler(5, 10)
 	defer scheduler.Stop()
@@ -169,7 +343,10 @@ func TestScheduler_ErrorHandling(t *testing.T) {
 	}
 
 	// 提交批次
-	batch := scheduler.SubmitBatch([]*Task{errTask, successTask})
+	batch, err := scheduler.SubmitBatch([]*Task{errTask, successTask})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
 	batch.Wait()
 
 	// 检查错误任务的结果，添加超时机制
@@ -226,13 +403,23 @@ func TestScheduler_ConcurrentBatches(t *testing.T) {
 
 	// 并发提交两个批次
 	go func() {
-		batch := scheduler.SubmitBatch(batch1Tasks)
+		batch, err := scheduler.SubmitBatch(batch1Tasks)
+		if err != nil {
+			t.Errorf("Submit failed: %v", err)
+			batch1Success <- false
+			return
+		}
 		batch.Wait()
 		batch1Success <- batch.IsSuccess()
 	}()
 
 	go func() {
-		batch := scheduler.SubmitBatch(batch2Tasks)
+		batch, err := scheduler.SubmitBatch(batch2Tasks)
+		if err != nil {
+			t.Errorf("Submit failed: %v", err)
+			batch2Success <- false
+			return
+		}
 		batch.Wait()
 		batch2Success <- batch.IsSuccess()
 	}()
@@ -266,7 +453,7 @@ func TestScheduler_StopBehavior(t *testing.T) {
 	}
 
 	// 提交任务但不等待
-	_ = scheduler.SubmitBatch([]*Task{blockingTask})
+	_, _ = scheduler.SubmitBatch([]*Task{blockingTask})
 
 	// 立即停止
 	stopDone := make(chan struct{})
@@ -283,3 +470,216 @@ func TestScheduler_StopBehavior(t *testing.T) {
 		t.Error("Stop() took too long to complete")
 	}
 }
+
+func TestBatch_ResultsChan(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	var tasks []*Task
+	for _, id := range []string{"a", "b", "c"} {
+		tasks = append(tasks, &Task{
+			ID: id,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+			},
+		})
+	}
+
+	batch, err := scheduler.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	// 通道应在所有结果产出后关闭
+	seen := make(map[string]bool)
+	timeout := time.After(time.Second)
+	for done := false; !done; {
+		select {
+		case res, ok := <-batch.ResultsChan():
+			if !ok {
+				done = true
+				break
+			}
+			seen[res.TaskID] = true
+		case <-timeout:
+			t.Fatal("ResultsChan was not closed in time")
+		}
+	}
+
+	if len(seen) != len(tasks) {
+		t.Errorf("Expected %d results, got %d", len(tasks), len(seen))
+	}
+}
+
+func TestBatch_Progress(t *testing.T) {
+	scheduler := NewScheduler(2, 10)
+	defer scheduler.Stop()
+
+	var tasks []*Task
+	for i := 0; i < 4; i++ {
+		tasks = append(tasks, &Task{
+			ID: fmt.Sprintf("task-%d", i),
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+			},
+		})
+	}
+
+	var calls atomic.Int32
+	batch, err := scheduler.SubmitBatch(tasks, OnProgress(func(done, total int) {
+		calls.Add(1)
+		if total != 4 {
+			t.Errorf("Expected total 4, got %d", total)
+		}
+	}))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	done, total := batch.Progress()
+	if done != 4 || total != 4 {
+		t.Errorf("Expected progress 4/4, got %d/%d", done, total)
+	}
+	if calls.Load() != 4 {
+		t.Errorf("Expected 4 progress callbacks, got %d", calls.Load())
+	}
+}
+
+func TestScheduler_TaskReuse(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	var runs atomic.Int32
+	task := &Task{
+		ID: "reused",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			runs.Add(1)
+			return TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+		},
+	}
+
+	// 同一个任务在一个批次内和多个批次间重复提交
+	batch1, err := scheduler.SubmitBatch([]*Task{task, task})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch2, err := scheduler.SubmitBatch([]*Task{task})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch1.Wait()
+	batch2.Wait()
+
+	if runs.Load() != 3 {
+		t.Errorf("Expected 3 executions, got %d", runs.Load())
+	}
+	if done, total := batch1.Progress(); done != 2 || total != 2 {
+		t.Errorf("Expected batch1 progress 2/2, got %d/%d", done, total)
+	}
+}
+
+func TestScheduler_ClosedResultChan(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	resultChan := make(chan TaskResult, 1)
+	close(resultChan)
+
+	batch, err := scheduler.SubmitBatch([]*Task{{
+		ID: "closed-chan",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+		ResultChan: resultChan,
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	if !batch.IsSuccess() {
+		t.Error("Expected batch to succeed despite closed ResultChan")
+	}
+}
+
+func TestBatch_Resubmit(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	var attempts atomic.Int32
+	task := &Task{
+		ID: "flaky",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			// 第一次失败，重新提交后成功
+			if attempts.Add(1) == 1 {
+				return TaskResult{}, errors.New("transient")
+			}
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	}
+
+	batch, err := scheduler.SubmitBatch([]*Task{task})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+	if batch.IsSuccess() {
+		t.Fatal("Expected first batch to fail")
+	}
+
+	retry, err := batch.Resubmit(scheduler)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	retry.Wait()
+	if !retry.IsSuccess() {
+		t.Error("Expected resubmitted batch to succeed")
+	}
+	if batch.IsSuccess() {
+		t.Error("Resubmission should not change the original batch state")
+	}
+}
+
+func TestScheduler_QueueTTL(t *testing.T) {
+	scheduler := NewScheduler(1, 10)
+	defer scheduler.Stop()
+
+	// 占住唯一的worker，让后续任务排队
+	release := make(chan struct{})
+	blocker, err := scheduler.SubmitBatch([]*Task{{
+		ID: "blocker",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			<-release
+			return TaskResult{HTTPCode: 200, BusinessCode: 1}, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	var executed atomic.Bool
+	batch, err := scheduler.SubmitBatch([]*Task{{
+		ID: "stale",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			executed.Store(true)
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	}}, WithQueueTTL(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	blocker.Wait()
+	batch.Wait()
+
+	if executed.Load() {
+		t.Error("Expired task should not be executed")
+	}
+	res := <-batch.ResultsChan()
+	if !errors.Is(res.Err, ErrQueueTTLExpired) || res.HTTPCode != 504 {
+		t.Errorf("Expected queue ttl timeout result, got %+v", res)
+	}
+}
@@ -0,0 +1,106 @@
+package fastscheduler
+
+import "sync"
+
+// TenantQuota 单个租户的配额
+type TenantQuota struct {
+	// Weight 公平调度的权重，各租户都有任务排队时按权重比例分配执行机会，默认1
+	Weight int
+	// MaxConcurrent 租户同时执行的任务数上限，0表示不限制
+	MaxConcurrent int
+	// QueueShare 租户在每个通道队列中最多占用的容量比例(0~1]，0表示不限制
+	// 超出时按溢出策略处理，默认阻塞该租户的提交方，其他租户不受影响
+	QueueShare float64
+}
+
+// TenantPolicy 多租户调度策略，按 Task.Tenant 区分租户
+type TenantPolicy struct {
+	// Tenants 各租户的配额
+	Tenants map[string]TenantQuota
+	// Default 未在 Tenants 中列出的租户(包括未设置 Tenant 的任务)使用的配额
+	Default TenantQuota
+}
+
+// WithTenantPolicy 启用多租户公平调度：同一通道内按租户权重轮流调度，
+// 并限制每个租户的并发数和排队容量，避免单个租户的大量任务饿死其他租户。
+// 启用后各通道使用内置的公平队列，WithQueue 不生效；隔离舱中的任务和同步模式不受该策略影响
+func WithTenantPolicy(policy TenantPolicy) Option {
+	return func(s *Scheduler) {
+		s.tenants = &tenantState{policy: policy, running: make(map[string]int)}
+	}
+}
+
+// WithTenant 设置批次内任务的默认租户，任务自身的 Tenant 优先
+func WithTenant(tenant string) BatchOption {
+	return func(g *taskGroup) {
+		g.tenant = tenant
+	}
+}
+
+// tenantState 各租户执行中的任务数，所有通道共享
+type tenantState struct {
+	policy  TenantPolicy
+	mu      sync.Mutex
+	running map[string]int
+}
+
+// quota 返回租户的配额
+func (ts *tenantState) quota(tenant string) TenantQuota {
+	if q, ok := ts.policy.Tenants[tenant]; ok {
+		return q
+	}
+	return ts.policy.Default
+}
+
+// full 判断租户是否已达到并发上限
+func (ts *tenantState) full(tenant string) bool {
+	limit := ts.quota(tenant).MaxConcurrent
+	if limit <= 0 {
+		return false
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.running[tenant] >= limit
+}
+
+// start 记录租户开始执行一个任务
+func (ts *tenantState) start(tenant string) {
+	ts.mu.Lock()
+	ts.running[tenant]++
+	ts.mu.Unlock()
+}
+
+// done 记录租户的任务执行结束
+func (ts *tenantState) done(tenant string) {
+	ts.mu.Lock()
+	ts.running[tenant]--
+	if ts.running[tenant] <= 0 {
+		delete(ts.running, tenant)
+	}
+	ts.mu.Unlock()
+}
+
+// releaseTenant 任务执行结束后释放租户并发名额，唤醒调度goroutine重新选择
+func (s *Scheduler) releaseTenant(tenant string) {
+	s.tenants.done(tenant)
+	select {
+	case s.quotaReleased <- struct{}{}:
+	default:
+	}
+}
+
+// TenantUsage 返回租户执行中和排队中的任务数，未启用 WithTenantPolicy 时返回0
+func (s *Scheduler) TenantUsage(tenant string) (running, queued int) {
+	if s.tenants == nil {
+		return 0, 0
+	}
+	s.tenants.mu.Lock()
+	running = s.tenants.running[tenant]
+	s.tenants.mu.Unlock()
+	for _, q := range s.queues {
+		if fq, ok := q.(*fairQueue); ok {
+			queued += fq.tenantLen(tenant)
+		}
+	}
+	return running, queued
+}
@@ -0,0 +1,151 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_TenantWeightedFairness(t *testing.T) {
+	scheduler := NewScheduler(1, 100, WithTenantPolicy(TenantPolicy{
+		Tenants: map[string]TenantQuota{"quiet": {Weight: 3}},
+		Default: TenantQuota{Weight: 1},
+	}))
+	defer scheduler.Stop()
+
+	var mu sync.Mutex
+	var order []string
+	makeTasks := func(tenant string, n int) []*Task {
+		var tasks []*Task
+		for i := 0; i < n; i++ {
+			tasks = append(tasks, &Task{
+				ID: fmt.Sprintf("%s-%d", tenant, i),
+				Execute: func(ctx context.Context) (TaskResult, error) {
+					mu.Lock()
+					order = append(order, tenant)
+					mu.Unlock()
+					return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+				},
+			})
+		}
+		return tasks
+	}
+
+	// 暂停后提交，吵闹的租户先排队 20 个任务
+	scheduler.Pause()
+	noisy, err := scheduler.SubmitBatch(makeTasks("noisy", 20), WithTenant("noisy"))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	quiet, err := scheduler.SubmitBatch(makeTasks("quiet", 6), WithTenant("quiet"))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	scheduler.Resume()
+	noisy.Wait()
+	quiet.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"noisy", "quiet", "quiet", "quiet", "noisy", "quiet", "quiet", "quiet"}
+	for i, tenant := range want {
+		if order[i] != tenant {
+			t.Fatalf("Expected weighted interleaving %v, got %v", want, order[:len(want)])
+		}
+	}
+}
+
+func TestScheduler_TenantMaxConcurrent(t *testing.T) {
+	scheduler := NewScheduler(4, 100, WithTenantPolicy(TenantPolicy{
+		Tenants: map[string]TenantQuota{"noisy": {MaxConcurrent: 2}},
+	}))
+	defer scheduler.Stop()
+
+	release := make(chan struct{})
+	var inflight, peak atomic.Int32
+	var tasks []*Task
+	for i := 0; i < 8; i++ {
+		tasks = append(tasks, &Task{
+			ID:     fmt.Sprintf("noisy-%d", i),
+			Tenant: "noisy",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				n := inflight.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				<-release
+				inflight.Add(-1)
+				return TaskResult{HTTPCode: 500, BusinessCode: 1}, nil
+			},
+		})
+	}
+	noisy, err := scheduler.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	// 吵闹的租户达到上限后，其他租户仍能使用剩余的 worker
+	future, err := scheduler.Submit(&Task{
+		ID:     "other",
+		Tenant: "other",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: 200}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := future.Wait(ctx); err != nil {
+		t.Fatalf("Other tenant was starved: %v", err)
+	}
+	if running, queued := scheduler.TenantUsage("noisy"); running != 2 || queued != 6 {
+		t.Errorf("Expected noisy tenant 2 running and 6 queued, got %d and %d", running, queued)
+	}
+
+	close(release)
+	noisy.Wait()
+	if p := peak.Load(); p > 2 {
+		t.Errorf("Expected at most 2 concurrent noisy tasks, got %d", p)
+	}
+}
+
+func TestScheduler_TenantQueueShare(t *testing.T) {
+	scheduler := NewScheduler(1, 10,
+		WithOverflowPolicy(OverflowReject),
+		WithTenantPolicy(TenantPolicy{Default: TenantQuota{QueueShare: 0.3}}),
+	)
+	defer scheduler.Stop()
+	scheduler.Pause()
+	defer scheduler.Resume()
+
+	submit := func(tenant string) error {
+		_, err := scheduler.Submit(&Task{
+			ID:     tenant,
+			Tenant: tenant,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 200}, nil
+			},
+		})
+		return err
+	}
+	for i := 0; i < 3; i++ {
+		if err := submit("noisy"); err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+	}
+	if err := submit("noisy"); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull beyond the tenant's queue share, got %v", err)
+	}
+	if err := submit("quiet"); err != nil {
+		t.Errorf("Expected other tenants to keep their share, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package fastscheduler
+
+import "context"
+
+// Then 在批次成功后以第一个成功的结果调用 fn，并自动提交其返回的后续任务
+// 整条任务链共用一个派生自本批次父上下文的链上下文，取消父上下文或调用本批次的 Cancel
+// 都会取消整条任务链，包括已经开始执行的后续批次。
+// 返回的 Future 以后续批次第一个成功的结果完成；本批次失败时不调用 fn，
+// Future 以本批次的错误完成；fn 没有返回任务时 Future 直接以本批次的结果完成
+func (b *Batch) Then(fn func(TaskResult) []*Task, opts ...BatchOption) *Future {
+	return b.group.sched.then(b.group.chainContext(), b.group.done, b.outcome, fn, opts)
+}
+
+// Then 在任务成功后以其结果调用 fn 并提交后续任务，语义与 Batch.Then 相同
+func (f *Future) Then(fn func(TaskResult) []*Task, opts ...BatchOption) *Future {
+	return f.sched.then(f.chainContext(), f.done, f.Result, fn, opts)
+}
+
+// then 等待 done 关闭后按结果提交后续批次，后续批次以 chain 为父上下文
+func (s *Scheduler) then(chain context.Context, done <-chan struct{}, outcome func() TaskResult,
+	fn func(TaskResult) []*Task, opts []BatchOption) *Future {
+	next := make(chan struct{})
+	var result TaskResult
+	go func() {
+		defer close(next)
+		<-done
+		result = outcome()
+		if !isSuccess(result) {
+			return
+		}
+		tasks := fn(result)
+		if len(tasks) == 0 {
+			return
+		}
+		opts = append([]BatchOption{withParentContext(chain)}, opts...)
+		batch, err := s.submitBatch(LaneNormal, tasks, opts)
+		if err != nil {
+			result = TaskResult{HTTPCode: 500, BusinessCode: 1, Err: err}
+			return
+		}
+		<-batch.group.done
+		result = batch.outcome()
+	}()
+
+	return &Future{
+		done: next,
+		resolve: func() TaskResult {
+			return result
+		},
+		sched: s,
+		chain: chain,
+	}
+}
+
+// outcome 返回批次的最终结果：第一个成功的结果，或汇总了全部错误的失败结果
+func (b *Batch) outcome() TaskResult {
+	if b.IsSuccess() {
+		return b.group.winner
+	}
+	return TaskResult{HTTPCode: 500, BusinessCode: 1, Err: b.Err()}
+}
@@ -0,0 +1,146 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBatch_Then(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	search := []*Task{
+		{
+			ID: "mirror-a",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 200, BusinessCode: 0, Data: "token-42"}, nil
+			},
+		},
+	}
+	batch, err := scheduler.SubmitBatch(search)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	future := batch.Then(func(r TaskResult) []*Task {
+		token := r.Data.(string)
+		return []*Task{{
+			ID: "fetch",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 200, BusinessCode: 0, Data: "data for " + token}, nil
+			},
+		}}
+	}).Then(func(r TaskResult) []*Task {
+		data := r.Data.(string)
+		return []*Task{{
+			ID: "store",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 200, BusinessCode: 0, Data: "stored " + data}, nil
+			},
+		}}
+	})
+
+	result := future.Result()
+	if result.TaskID != "store" || result.Data != "stored data for token-42" {
+		t.Errorf("Unexpected chained result: %+v", result)
+	}
+}
+
+func TestBatch_ThenSkippedOnFailure(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	boom := errors.New("boom")
+	batch, err := scheduler.SubmitBatch([]*Task{{
+		ID: "failing",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{}, boom
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	called := false
+	result := batch.Then(func(r TaskResult) []*Task {
+		called = true
+		return nil
+	}).Result()
+	if called {
+		t.Error("Continuation should not run after failure")
+	}
+	if !errors.Is(result.Err, boom) {
+		t.Errorf("Expected batch error, got %v", result.Err)
+	}
+}
+
+func TestBatch_ThenCancelledWithParent(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	batch, err := scheduler.SubmitBatch([]*Task{{
+		ID: "first",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	}}, withParentContext(ctx))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	result := batch.Then(func(r TaskResult) []*Task {
+		cancel()
+		return []*Task{{
+			ID: "second",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				<-ctx.Done()
+				return TaskResult{}, ctx.Err()
+			},
+		}}
+	}).Result()
+	if !errors.Is(result.Err, context.Canceled) {
+		t.Errorf("Expected follow-up to be cancelled, got %v", result.Err)
+	}
+}
+
+func TestBatch_ThenCancelledWithBatch(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	batch, err := scheduler.SubmitBatch([]*Task{{
+		ID: "first",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	started := make(chan struct{})
+	next := func(id string) func(TaskResult) []*Task {
+		return func(r TaskResult) []*Task {
+			return []*Task{{
+				ID: id,
+				Execute: func(ctx context.Context) (TaskResult, error) {
+					if id == "third" {
+						close(started)
+						<-ctx.Done()
+						return TaskResult{}, ctx.Err()
+					}
+					return TaskResult{HTTPCode: 200, BusinessCode: 0}, nil
+				},
+			}}
+		}
+	}
+	future := batch.Then(next("second")).Then(next("third"))
+
+	<-started
+	batch.Cancel()
+	result := future.Result()
+	if !errors.Is(result.Err, context.Canceled) {
+		t.Errorf("Expected chain to be cancelled with the batch, got %v", result.Err)
+	}
+}
@@ -0,0 +1,38 @@
+package fastscheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithDefaultTaskTimeout 为没有设置 Task.Timeout 的任务设置单次执行的超时
+// 避免一个挂起的下游调用一直占用worker；超时后任务的 ctx 被取消，计入 Stats().TaskTimeouts
+func WithDefaultTaskTimeout(d time.Duration) Option {
+	return func(s *Scheduler) {
+		s.defaultTimeout = d
+	}
+}
+
+// withTimeout 按任务的超时设置派生执行上下文，返回的函数报告超时是否触发并释放上下文
+func (s *Scheduler) withTimeout(ctx context.Context, task *Task) (context.Context, func() bool) {
+	timeout := task.Timeout
+	if timeout == 0 {
+		timeout = s.defaultTimeout
+	}
+	if timeout <= 0 {
+		return ctx, func() bool { return false }
+	}
+	tctx, cancel := s.contextWithTimeout(ctx, timeout)
+	return tctx, func() bool {
+		// 只统计本次超时，批次或调用方上下文的结束不计入
+		fired := tctx.Err() == context.DeadlineExceeded && ctx.Err() == nil
+		cancel()
+		if fired {
+			s.taskTimeouts.Add(1)
+			s.logTask(slog.LevelWarn, "task timed out", task,
+				slog.Duration("timeout", timeout), slog.Bool("default", task.Timeout == 0))
+		}
+		return fired
+	}
+}
@@ -0,0 +1,61 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScheduler_DefaultTaskTimeout(t *testing.T) {
+	scheduler := NewScheduler(2, 10, WithDefaultTaskTimeout(20*time.Millisecond))
+	defer scheduler.Stop()
+
+	hung := &Task{
+		ID: "hung",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			<-ctx.Done()
+			return TaskResult{}, nil
+		},
+	}
+	batch, err := scheduler.SubmitBatch([]*Task{hung})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+	result := <-batch.ResultsChan()
+	if !errors.Is(result.Err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", result.Err)
+	}
+	if batch.Outcome() != OutcomeTimedOut {
+		t.Errorf("Expected timed out batch, got %s", batch.Outcome())
+	}
+	if got := scheduler.Stats().TaskTimeouts; got != 1 {
+		t.Errorf("TaskTimeouts = %d, want 1", got)
+	}
+
+	// 任务自身的 Timeout 优先于默认值
+	slow := &Task{
+		ID:      "slow",
+		Timeout: time.Second,
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			select {
+			case <-time.After(50 * time.Millisecond):
+				return TaskResult{HTTPCode: 200}, nil
+			case <-ctx.Done():
+				return TaskResult{}, ctx.Err()
+			}
+		},
+	}
+	batch, err = scheduler.SubmitBatch([]*Task{slow})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+	if !batch.IsSuccess() {
+		t.Errorf("Expected task with its own timeout to succeed, got %v", batch.Err())
+	}
+	if got := scheduler.Stats().TaskTimeouts; got != 1 {
+		t.Errorf("TaskTimeouts = %d, want 1", got)
+	}
+}
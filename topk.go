@@ -0,0 +1,84 @@
+package fastscheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// WithTopK 批次在 k 个任务成功后才取消其余任务，而不是第一个成功时，用于法定数读取等需要多个结果的场景
+// less 不为nil时 TopK 按 less 从小到大排列结果，否则按完成顺序排列
+func WithTopK(k int, less func(a, b TaskResult) bool) BatchOption {
+	return func(g *taskGroup) {
+		if k > 0 {
+			g.topK = &topKState{k: k, less: less, ready: make(chan struct{})}
+		}
+	}
+}
+
+// topKState 收集前 k 个成功结果
+type topKState struct {
+	k    int
+	less func(a, b TaskResult) bool
+
+	mu      sync.Mutex
+	results []TaskResult
+	// ready 收集到 k 个结果时关闭
+	ready chan struct{}
+}
+
+// collect 记录一个成功结果，返回是否已经收集到足够的结果，未启用 WithTopK 时第一个成功即足够
+func (g *taskGroup) collect(result TaskResult) bool {
+	st := g.topK
+	if st == nil {
+		return true
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if len(st.results) >= st.k {
+		return true
+	}
+	st.results = append(st.results, result)
+	if len(st.results) < st.k {
+		return false
+	}
+	close(st.ready)
+	return true
+}
+
+// sorted 返回已收集结果的副本，按 less 或完成顺序排列
+func (st *topKState) sorted() []TaskResult {
+	st.mu.Lock()
+	results := append([]TaskResult(nil), st.results...)
+	st.mu.Unlock()
+	if st.less != nil {
+		sort.SliceStable(results, func(i, j int) bool { return st.less(results[i], results[j]) })
+	}
+	return results
+}
+
+// TopK 等待 WithTopK 指定数量的成功结果，收集够后立即返回，不等待其余任务处理取消
+// 批次完成时成功数不足 k，返回已有的成功结果和 ErrNotEnoughSuccesses；ctx 先结束时返回 ctx.Err()
+// 未使用 WithTopK 的批次等同于 k 为1
+func (b *Batch) TopK(ctx context.Context) ([]TaskResult, error) {
+	st := b.group.topK
+	if st == nil {
+		result, err := b.WaitFirstSuccess(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return []TaskResult{result}, nil
+	}
+	select {
+	case <-st.ready:
+	case <-b.group.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	results := st.sorted()
+	if len(results) < st.k {
+		return results, fmt.Errorf("%w: got %d of %d", ErrNotEnoughSuccesses, len(results), st.k)
+	}
+	return results, nil
+}
@@ -0,0 +1,74 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBatch_TopK(t *testing.T) {
+	scheduler := NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	replica := func(id string, delay time.Duration, version int) *Task {
+		return &Task{
+			ID: id,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				select {
+				case <-time.After(delay):
+					return TaskResult{HTTPCode: 200, Data: version}, nil
+				case <-ctx.Done():
+					return TaskResult{}, ctx.Err()
+				}
+			},
+		}
+	}
+	tasks := []*Task{
+		replica("r1", 10*time.Millisecond, 3),
+		replica("r2", 30*time.Millisecond, 5),
+		replica("r3", 50*time.Millisecond, 4),
+		replica("r4", time.Second, 9),
+	}
+
+	// 按完成顺序取最快的3个结果，第4个副本被取消
+	batch, err := scheduler.SubmitBatch(tasks, WithTopK(3, nil))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	results, err := batch.TopK(context.Background())
+	if err != nil {
+		t.Fatalf("TopK failed: %v", err)
+	}
+	if len(results) != 3 || results[0].TaskID != "r1" || results[1].TaskID != "r2" || results[2].TaskID != "r3" {
+		t.Errorf("Unexpected results %v", results)
+	}
+	batch.Wait()
+	if sum := batch.Summary(); sum.SucceededTasks != 3 || sum.CancelledTasks != 1 {
+		t.Errorf("Expected 3 successes and 1 cancelled task, got %+v", sum)
+	}
+
+	// 按比较函数排列：版本号从大到小
+	newest := func(a, b TaskResult) bool { return a.Data.(int) > b.Data.(int) }
+	batch, err = scheduler.SubmitBatch(tasks[:3], WithTopK(2, newest))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	results, err = batch.TopK(context.Background())
+	if err != nil {
+		t.Fatalf("TopK failed: %v", err)
+	}
+	if len(results) != 2 || results[0].TaskID != "r2" || results[1].TaskID != "r1" {
+		t.Errorf("Unexpected ordered results %v", results)
+	}
+
+	// 成功数不足 k
+	batch, err = scheduler.SubmitBatch(tasks[:2], WithTopK(3, nil))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	results, err = batch.TopK(context.Background())
+	if !errors.Is(err, ErrNotEnoughSuccesses) || len(results) != 2 {
+		t.Errorf("Expected ErrNotEnoughSuccesses with 2 results, got %v, %v", results, err)
+	}
+}
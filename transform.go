@@ -0,0 +1,30 @@
+package fastscheduler
+
+// ResultTransformer 在判定成功和投递之前处理任务结果，可用于校验、规范化或补充数据
+// 例如把不同上游的响应格式统一为相同的 Data 类型，或在数据不合法时将结果标记为失败
+type ResultTransformer func(result TaskResult) TaskResult
+
+// WithResultTransformers 注册作用于所有任务结果的处理函数，按注册顺序执行
+func WithResultTransformers(fns ...ResultTransformer) Option {
+	return func(s *Scheduler) {
+		s.transformers = append(s.transformers, fns...)
+	}
+}
+
+// TransformResults 注册只作用于当前批次的处理函数，在调度器级处理函数之后执行
+func TransformResults(fns ...ResultTransformer) BatchOption {
+	return func(g *taskGroup) {
+		g.transformers = append(g.transformers, fns...)
+	}
+}
+
+// transform 依次执行调度器级和批次级处理函数
+func (s *Scheduler) transform(g *taskGroup, result TaskResult) TaskResult {
+	for _, fn := range s.transformers {
+		result = fn(result)
+	}
+	for _, fn := range g.transformers {
+		result = fn(result)
+	}
+	return result
+}
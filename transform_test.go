@@ -0,0 +1,58 @@
+package fastscheduler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestScheduler_ResultTransformers(t *testing.T) {
+	// 调度器级：把字符串数据统一为小写
+	normalize := func(r TaskResult) TaskResult {
+		if s, ok := r.Data.(string); ok {
+			r.Data = strings.ToLower(s)
+		}
+		return r
+	}
+	scheduler := NewScheduler(5, 10, WithResultTransformers(normalize))
+	defer scheduler.Stop()
+
+	// 批次级：空数据视为失败，在成功判定之前生效
+	errEmpty := errors.New("empty payload")
+	validate := func(r TaskResult) TaskResult {
+		if r.Data == "" {
+			r.BusinessCode = 1
+			r.Err = errEmpty
+		}
+		return r
+	}
+
+	resultChan := make(chan TaskResult, 1)
+	batch, err := scheduler.SubmitBatch([]*Task{
+		{
+			ID: "empty",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 200, BusinessCode: 0, Data: ""}, nil
+			},
+		},
+		{
+			ID: "upper",
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 200, BusinessCode: 0, Data: "HELLO"}, nil
+			},
+			ResultChan: resultChan,
+		},
+	}, runToCompletion(), TransformResults(validate))
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	if res := <-resultChan; res.Data != "hello" {
+		t.Errorf("Expected normalized data, got %v", res.Data)
+	}
+	if !errors.Is(batch.group.errs[0], errEmpty) {
+		t.Errorf("Expected validation failure to be recorded, got %v", batch.group.errs)
+	}
+}
@@ -0,0 +1,50 @@
+package fastscheduler
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+// Sizer 由自定义结果数据实现，用于报告数据大小(字节)
+type Sizer interface {
+	Size() int
+}
+
+// WithMaxResultDataSize 限制 TaskResult.Data 的最大字节数
+// string 和 []byte 超限时截断到上限(string 不会截断在UTF-8字符中间)，实现 Sizer 的数据超限时被丢弃，
+// 两种情况均设置 TaskResult.Truncated。其他类型的数据无法度量，不做限制
+func WithMaxResultDataSize(n int) Option {
+	return func(s *Scheduler) {
+		s.maxDataSize = n
+	}
+}
+
+// truncateData 按上限截断结果数据
+// 截断后的数据复制到新的内存，不再引用原始数据，使较大的原始数据可以被回收
+func truncateData(result *TaskResult, limit int) {
+	if limit <= 0 || result.Data == nil {
+		return
+	}
+	switch data := result.Data.(type) {
+	case string:
+		if len(data) > limit {
+			n := limit
+			for n > 0 && !utf8.RuneStart(data[n]) {
+				n--
+			}
+			result.Data = strings.Clone(data[:n])
+			result.Truncated = true
+		}
+	case []byte:
+		if len(data) > limit {
+			result.Data = bytes.Clone(data[:limit])
+			result.Truncated = true
+		}
+	case Sizer:
+		if data.Size() > limit {
+			result.Data = nil
+			result.Truncated = true
+		}
+	}
+}
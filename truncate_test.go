@@ -0,0 +1,76 @@
+package fastscheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type sizedPayload int
+
+func (p sizedPayload) Size() int { return int(p) }
+
+func TestScheduler_MaxResultDataSize(t *testing.T) {
+	scheduler := NewScheduler(5, 10, WithMaxResultDataSize(8))
+	defer scheduler.Stop()
+
+	payloads := map[string]interface{}{
+		"small":  "ok",
+		"string": strings.Repeat("x", 100),
+		"bytes":  make([]byte, 100),
+		"sizer":  sizedPayload(100),
+	}
+
+	var tasks []*Task
+	for id, data := range payloads {
+		tasks = append(tasks, &Task{
+			ID: id,
+			Execute: func(ctx context.Context) (TaskResult, error) {
+				return TaskResult{HTTPCode: 200, BusinessCode: 1, Data: data}, nil
+			},
+		})
+	}
+
+	batch, err := scheduler.SubmitBatch(tasks)
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	batch.Wait()
+
+	for res := range batch.ResultsChan() {
+		switch res.TaskID {
+		case "small":
+			if res.Truncated || res.Data != "ok" {
+				t.Errorf("Small payload should be untouched: %+v", res)
+			}
+		case "string":
+			if !res.Truncated || len(res.Data.(string)) != 8 {
+				t.Errorf("String payload should be truncated to 8 bytes: %+v", res)
+			}
+		case "bytes":
+			if !res.Truncated || len(res.Data.([]byte)) != 8 {
+				t.Errorf("Byte payload should be truncated to 8 bytes: %+v", res)
+			}
+		case "sizer":
+			if !res.Truncated || res.Data != nil {
+				t.Errorf("Sizer payload should be dropped: %+v", res)
+			}
+		}
+	}
+}
+
+func TestTruncateData_CopiesAndKeepsRunes(t *testing.T) {
+	source := make([]byte, 1<<20)
+	result := TaskResult{Data: source}
+	truncateData(&result, 8)
+	if kept := result.Data.([]byte); len(kept) != 8 || cap(kept) != 8 || &kept[0] == &source[0] {
+		t.Errorf("Truncated bytes should be a separate 8-byte copy")
+	}
+
+	// "数据" 每个字符3字节，上限4时只保留第一个字符
+	result = TaskResult{Data: "数据"}
+	truncateData(&result, 4)
+	if result.Data != "数" || !result.Truncated {
+		t.Errorf("Expected truncation at a rune boundary, got %q", result.Data)
+	}
+}
@@ -0,0 +1,185 @@
+// Package typed 提供基于泛型的任务API，结果数据类型在编译期检查
+// 内部复用 fastscheduler 的调度器，未类型化的API保持不变
+package typed
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+// TaskResult 表示带类型的任务执行结果
+type TaskResult[T any] struct {
+	// TaskID 产生该结果的任务ID
+	TaskID       string
+	HTTPCode     int
+	BusinessCode int
+	// Err 任务的错误；Data 不是 T 类型时为 *fastscheduler.ResultTypeError
+	Err  error
+	Data T
+
+	// Truncated 表示Data因超过大小上限被截断或丢弃
+	Truncated bool
+
+	// Cost 任务实际消耗的成本，为0时使用Task.Cost
+	Cost float64
+
+	// Attempts 任务实际执行的次数，未执行就结束的任务为0
+	Attempts int
+
+	// Metrics 任务排队和执行的时间
+	Metrics fastscheduler.TaskMetrics
+}
+
+// Task 表示带类型的任务
+type Task[T any] struct {
+	// ID 用于标识任务
+	ID string
+
+	// Execute 是任务执行函数
+	Execute func(ctx context.Context) (TaskResult[T], error)
+
+	// ResultChan 用于接收结果(可选)
+	ResultChan chan<- TaskResult[T]
+
+	// Cost 任务的预估成本，用于成本统计
+	Cost float64
+
+	// Tenant 任务所属租户
+	Tenant string
+
+	// Tags 任务标签
+	Tags []string
+}
+
+// Batch 表示一批带类型的任务
+type Batch[T any] struct {
+	Tasks []*Task[T]
+
+	batch   *fastscheduler.Batch
+	results chan TaskResult[T]
+	done    chan struct{}
+}
+
+// SubmitBatch 将一批带类型的任务提交到调度器
+// 调度器已停止时返回 fastscheduler.ErrSchedulerStopped
+func SubmitBatch[T any](s *fastscheduler.Scheduler, tasks []*Task[T], opts ...fastscheduler.BatchOption) (*Batch[T], error) {
+	// 每个任务的 ResultChan 对应各自的未类型化通道，不依赖任务ID唯一
+	untyped := make([]*fastscheduler.Task, len(tasks))
+	forwards := make([]chan fastscheduler.TaskResult, len(tasks))
+	for i, task := range tasks {
+		untyped[i] = toUntyped(task)
+		if task.ResultChan != nil {
+			forwards[i] = make(chan fastscheduler.TaskResult, 1)
+			untyped[i].ResultChan = forwards[i]
+		}
+	}
+
+	batch, err := s.SubmitBatch(untyped, opts...)
+	if batch == nil {
+		return nil, err
+	}
+
+	var forwarding sync.WaitGroup
+	for i, ch := range forwards {
+		if ch == nil {
+			continue
+		}
+		forwarding.Add(1)
+		go func(out chan<- TaskResult[T]) {
+			defer forwarding.Done()
+			out <- fromUntyped[T](<-ch)
+		}(tasks[i].ResultChan)
+	}
+
+	b := &Batch[T]{
+		Tasks:   tasks,
+		batch:   batch,
+		results: make(chan TaskResult[T], len(tasks)),
+		done:    make(chan struct{}),
+	}
+
+	// 转发结果到带类型的通道
+	go func() {
+		defer close(b.done)
+		defer forwarding.Wait()
+		defer close(b.results)
+		for r := range b.batch.ResultsChan() {
+			b.results <- fromUntyped[T](r)
+		}
+	}()
+
+	return b, err
+}
+
+// toUntyped 将带类型的任务包装为未类型化的任务
+func toUntyped[T any](task *Task[T]) *fastscheduler.Task {
+	return &fastscheduler.Task{
+		ID: task.ID,
+		Execute: func(ctx context.Context) (fastscheduler.TaskResult, error) {
+			r, err := task.Execute(ctx)
+			return fastscheduler.TaskResult{
+				HTTPCode:     r.HTTPCode,
+				BusinessCode: r.BusinessCode,
+				Err:          r.Err,
+				Data:         r.Data,
+				Cost:         r.Cost,
+			}, err
+		},
+		Cost:   task.Cost,
+		Tenant: task.Tenant,
+		Tags:   task.Tags,
+	}
+}
+
+// fromUntyped 将未类型化的结果转换为带类型的结果
+// Data 不是 T 类型(例如被结果处理函数替换)时 Err 为 *fastscheduler.ResultTypeError，
+// 任务本身已有错误时保留原错误；没有 Data 的结果(如失败的任务)使用零值
+func fromUntyped[T any](r fastscheduler.TaskResult) TaskResult[T] {
+	result := TaskResult[T]{
+		TaskID:       r.TaskID,
+		HTTPCode:     r.HTTPCode,
+		BusinessCode: r.BusinessCode,
+		Err:          r.Err,
+		Truncated:    r.Truncated,
+		Cost:         r.Cost,
+		Attempts:     r.Attempts,
+		Metrics:      r.Metrics,
+	}
+	if r.Data == nil {
+		return result
+	}
+	if data, ok := r.Data.(T); ok {
+		result.Data = data
+	} else if result.Err == nil {
+		result.Err = &fastscheduler.ResultTypeError{TaskID: r.TaskID, Data: r.Data, Want: reflect.TypeFor[T]()}
+	}
+	return result
+}
+
+// Wait 等待批次中的所有任务完成且结果已转发
+func (b *Batch[T]) Wait() {
+	<-b.done
+}
+
+// IsSuccess 返回批次中是否有任务成功
+func (b *Batch[T]) IsSuccess() bool {
+	return b.batch.IsSuccess()
+}
+
+// ResultsChan 返回按完成顺序产出结果的只读通道，批次完成后通道关闭
+func (b *Batch[T]) ResultsChan() <-chan TaskResult[T] {
+	return b.results
+}
+
+// Progress 返回批次已完成的任务数和任务总数
+func (b *Batch[T]) Progress() (done, total int) {
+	return b.batch.Progress()
+}
+
+// Cost 返回批次中已执行任务的成本总和
+func (b *Batch[T]) Cost() float64 {
+	return b.batch.Cost()
+}
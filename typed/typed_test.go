@@ -0,0 +1,132 @@
+package typed
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	fastscheduler "github.com/hawkli-1994/fast-scheduler"
+)
+
+type quote struct {
+	Provider string
+	Price    int
+}
+
+func TestSubmitBatch_TypedResults(t *testing.T) {
+	scheduler := fastscheduler.NewScheduler(5, 10)
+	defer scheduler.Stop()
+
+	resultChan := make(chan TaskResult[quote], 1)
+	tasks := []*Task[quote]{
+		{
+			ID: "provider-a",
+			Execute: func(ctx context.Context) (TaskResult[quote], error) {
+				return TaskResult[quote]{
+					HTTPCode:     200,
+					BusinessCode: 0,
+					Data:         quote{Provider: "a", Price: 42},
+				}, nil
+			},
+			ResultChan: resultChan,
+		},
+	}
+
+	batch, err := SubmitBatch(scheduler, tasks)
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	batch.Wait()
+
+	if !batch.IsSuccess() {
+		t.Error("Expected batch to have a successful task")
+	}
+
+	select {
+	case res := <-resultChan:
+		// Data 无需类型断言
+		if res.Data.Price != 42 || res.TaskID != "provider-a" {
+			t.Errorf("Unexpected typed result: %+v", res)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Expected to receive typed result, but timed out")
+	}
+
+	var count int
+	for range batch.ResultsChan() {
+		count++
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 streamed result, got %d", count)
+	}
+}
+
+func TestSubmitBatch_ResultChansWithoutIDs(t *testing.T) {
+	scheduler := fastscheduler.NewScheduler(2, 10)
+	defer scheduler.Stop()
+
+	chans := []chan TaskResult[int]{make(chan TaskResult[int], 1), make(chan TaskResult[int], 1)}
+	tasks := make([]*Task[int], len(chans))
+	for i := range tasks {
+		tasks[i] = &Task[int]{
+			Execute: func(ctx context.Context) (TaskResult[int], error) {
+				return TaskResult[int]{HTTPCode: 500, BusinessCode: 1, Data: i}, nil
+			},
+			ResultChan: chans[i],
+		}
+	}
+
+	batch, err := SubmitBatch(scheduler, tasks)
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		batch.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Wait hung with tasks sharing an empty ID")
+	}
+
+	for i, ch := range chans {
+		select {
+		case res := <-ch:
+			if res.Data != i || res.Attempts != 1 || res.Metrics.FinishedAt.IsZero() {
+				t.Errorf("Task %d: unexpected result %+v", i, res)
+			}
+		default:
+			t.Errorf("Task %d: expected its own result", i)
+		}
+	}
+}
+
+func TestSubmitBatch_ResultTypeMismatch(t *testing.T) {
+	scheduler := fastscheduler.NewScheduler(1, 10)
+	defer scheduler.Stop()
+
+	tasks := []*Task[quote]{{
+		ID: "replaced",
+		Execute: func(ctx context.Context) (TaskResult[quote], error) {
+			return TaskResult[quote]{HTTPCode: 200, Data: quote{Price: 1}}, nil
+		},
+	}}
+	replace := fastscheduler.TransformResults(func(r fastscheduler.TaskResult) fastscheduler.TaskResult {
+		r.Data = "not a quote"
+		return r
+	})
+	batch, err := SubmitBatch(scheduler, tasks, replace)
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	batch.Wait()
+
+	res := <-batch.ResultsChan()
+	var typeErr *fastscheduler.ResultTypeError
+	if !errors.As(res.Err, &typeErr) || typeErr.TaskID != "replaced" {
+		t.Errorf("Expected ResultTypeError, got %v", res.Err)
+	}
+}
@@ -0,0 +1,148 @@
+package fastscheduler
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// WatchdogConfig 慢任务检测配置
+type WatchdogConfig struct {
+	// Threshold 单次执行超过该时长的任务被标记为慢任务
+	Threshold time.Duration
+	// Interval 检查间隔，默认为 Threshold 的四分之一
+	Interval time.Duration
+	// Cancel 为 true 时取消慢任务本次执行的上下文，任务按失败处理(可以重试)
+	Cancel bool
+}
+
+// WithWatchdog 定期检查执行中的任务，超过阈值的任务产生 EventSlowTask 事件，
+// 带有任务ID、已运行时长和执行该任务的goroutine栈；启用 WithLogger 时同时输出 Warn 日志
+// 每次执行只标记一次。启用后每个任务开始时需要记录goroutine ID，有少量额外开销
+func WithWatchdog(cfg WatchdogConfig) Option {
+	return func(s *Scheduler) {
+		if cfg.Threshold <= 0 {
+			return
+		}
+		if cfg.Interval <= 0 {
+			cfg.Interval = cfg.Threshold / 4
+		}
+		s.watchdog = &cfg
+	}
+}
+
+// runWatchdog 按间隔检查慢任务，stop 关闭时退出
+func (s *Scheduler) runWatchdog(stop <-chan struct{}) {
+	ticker := s.clock.NewTicker(s.watchdog.Interval)
+	defer ticker.Stop()
+	// flagged 已标记的执行，值为执行开始的时间，用于区分同一任务的重试
+	flagged := make(map[*Task]time.Time)
+	for {
+		select {
+		case <-ticker.C():
+			s.checkSlowTasks(flagged)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// checkSlowTasks 标记超过阈值的执行
+func (s *Scheduler) checkSlowTasks(flagged map[*Task]time.Time) {
+	now := s.now()
+	var stacks []byte
+	s.running.Range(func(k, v any) bool {
+		t, info := k.(*Task), v.(TaskInfo)
+		elapsed := now.Sub(info.Started)
+		if elapsed < s.watchdog.Threshold || flagged[t].Equal(info.Started) {
+			return true
+		}
+		flagged[t] = info.Started
+		if stacks == nil {
+			stacks = allStacks()
+		}
+		stack := goroutineStack(stacks, info.goroutine)
+
+		if s.subscribed() {
+			s.publish(Event{
+				Type:    EventSlowTask,
+				BatchID: info.BatchID,
+				TaskID:  info.ID,
+				Lane:    info.Lane,
+				Attempt: info.Attempt,
+				Runtime: elapsed,
+				Stack:   stack,
+			})
+		}
+		// 任务字段可能被worker并发修改，日志只使用 TaskInfo
+		if s.logEnabled(slog.LevelWarn) {
+			s.logger.LogAttrs(context.Background(), slog.LevelWarn, "slow task",
+				slog.String("batch_id", info.BatchID),
+				slog.String("task_id", info.ID),
+				slog.String("lane", info.Lane.String()),
+				slog.Int("attempt", info.Attempt),
+				slog.Duration("runtime", elapsed),
+				slog.Bool("cancelled", s.watchdog.Cancel),
+				slog.String("stack", stack))
+		}
+		if s.watchdog.Cancel {
+			g := t.group
+			g.taskMu.Lock()
+			if t.taskCancel != nil {
+				t.taskCancel()
+			}
+			g.taskMu.Unlock()
+		}
+		return true
+	})
+	for t := range flagged {
+		if _, ok := s.running.Load(t); !ok {
+			delete(flagged, t)
+		}
+	}
+}
+
+// goroutineID 返回当前goroutine的ID
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	// 格式为 "goroutine 123 [running]:"
+	line := bytes.TrimPrefix(buf[:n], []byte("goroutine "))
+	if i := bytes.IndexByte(line, ' '); i > 0 {
+		id, _ := strconv.ParseUint(string(line[:i]), 10, 64)
+		return id
+	}
+	return 0
+}
+
+// allStacks 返回所有goroutine的栈
+func allStacks() []byte {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// goroutineStack 从所有goroutine的栈中取出指定goroutine的部分，找不到时返回空字符串
+func goroutineStack(stacks []byte, id uint64) string {
+	if id == 0 {
+		return ""
+	}
+	header := []byte("goroutine " + strconv.FormatUint(id, 10) + " [")
+	i := bytes.Index(stacks, header)
+	if i < 0 {
+		return ""
+	}
+	stack := stacks[i:]
+	if end := bytes.Index(stack, []byte("\n\n")); end >= 0 {
+		stack = stack[:end]
+	}
+	return string(stack)
+}
@@ -0,0 +1,47 @@
+package fastscheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScheduler_Watchdog(t *testing.T) {
+	scheduler := NewScheduler(2, 10, WithWatchdog(WatchdogConfig{Threshold: 20 * time.Millisecond, Cancel: true}))
+	defer scheduler.Stop()
+
+	events := scheduler.Subscribe(EventTypes(EventSlowTask))
+	batch, err := scheduler.SubmitBatch([]*Task{{
+		ID: "stuck",
+		Execute: func(ctx context.Context) (TaskResult, error) {
+			<-ctx.Done()
+			return TaskResult{}, ctx.Err()
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	select {
+	case e := <-events:
+		if e.TaskID != "stuck" || e.BatchID != batch.ID() || e.Runtime < 20*time.Millisecond {
+			t.Errorf("Unexpected slow task event %+v", e)
+		}
+		if !strings.Contains(e.Stack, "TestScheduler_Watchdog") {
+			t.Errorf("Expected stack of the stuck task, got %q", e.Stack)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for slow task event")
+	}
+
+	// 启用 Cancel 时慢任务被取消
+	select {
+	case <-batch.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected watchdog to cancel the slow task")
+	}
+	if batch.Outcome() != OutcomeCancelled {
+		t.Errorf("Expected cancelled batch, got %s", batch.Outcome())
+	}
+}